@@ -0,0 +1,145 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file or at
+// https://developers.google.com/open-source/licenses/bsd.
+
+package database
+
+import (
+	"math"
+	"time"
+)
+
+// refreshHalfLife is the time after which a user refresh of a package
+// counts half in the crawl priority of the package.
+const refreshHalfLife = 7 * 24 * time.Hour
+
+// CrawlState is the state kept by the crawl scheduler for an import path.
+type CrawlState struct {
+	// Failures is the number of consecutive failed crawls.
+	Failures int `json:"failures,omitempty"`
+
+	// LastError is the error of the last failed crawl.
+	LastError string `json:"lastError,omitempty"`
+
+	// LastAttempt is the time of the last crawl.
+	LastAttempt time.Time `json:"lastAttempt"`
+
+	// LastChange is the time a crawl last found a new version.
+	LastChange time.Time `json:"lastChange"`
+
+	// ChangeInterval is the estimated time between new versions or 0 if
+	// the time is not known.
+	ChangeInterval time.Duration `json:"changeInterval,omitempty"`
+
+	// Refreshes is the number of user refreshes at RefreshTime. See
+	// RecentRefreshes.
+	Refreshes   float64   `json:"refreshes,omitempty"`
+	RefreshTime time.Time `json:"refreshTime"`
+}
+
+// RecordSuccess records a successful crawl at time t. The crawl found a new
+// version if changed is true. The time between the new versions found is
+// averaged into the change interval. The change interval grows while no
+// new version is found.
+func (st *CrawlState) RecordSuccess(t time.Time, changed bool) {
+	st.Failures = 0
+	st.LastError = ""
+	st.LastAttempt = t
+	if st.LastChange.IsZero() {
+		if changed {
+			st.LastChange = t
+		}
+		return
+	}
+	d := t.Sub(st.LastChange)
+	switch {
+	case changed && st.ChangeInterval == 0:
+		st.ChangeInterval = d
+	case changed:
+		st.ChangeInterval = (st.ChangeInterval + d) / 2
+	case d > st.ChangeInterval:
+		st.ChangeInterval = d
+	}
+	if changed {
+		st.LastChange = t
+	}
+}
+
+// RecordFailure records a crawl failed with the error err at time t.
+func (st *CrawlState) RecordFailure(t time.Time, err error) {
+	st.Failures++
+	st.LastError = err.Error()
+	st.LastAttempt = t
+}
+
+// RecordRefresh records a user refresh at time t.
+func (st *CrawlState) RecordRefresh(t time.Time) {
+	st.Refreshes = st.RecentRefreshes(t) + 1
+	st.RefreshTime = t
+}
+
+// RecentRefreshes returns the number of user refreshes at time t where
+// each refresh counts half after refreshHalfLife.
+func (st *CrawlState) RecentRefreshes(t time.Time) float64 {
+	if st.Refreshes == 0 {
+		return 0
+	}
+	return st.Refreshes * math.Exp2(-float64(t.Sub(st.RefreshTime))/float64(refreshHalfLife))
+}
+
+// CrawlState returns the crawl state of the path. The state of a path that
+// was never crawled is the zero state.
+func (db *Database) CrawlState(path string) (*CrawlState, error) {
+	st, err := db.Store.GetCrawlState(path)
+	if st == nil && err == nil {
+		st = &CrawlState{}
+	}
+	return st, err
+}
+
+// PutCrawlState replaces the crawl state of the path.
+func (db *Database) PutCrawlState(path string, st *CrawlState) error {
+	return db.Store.PutCrawlState(path, st)
+}
+
+// DueCrawls returns the paths of up to n stored packages scheduled for
+// crawling at or before now, earliest first.
+func (db *Database) DueCrawls(now time.Time, n int) ([]string, error) {
+	return db.Store.DueCrawls(now, n)
+}
+
+// CrawlPriority returns the priority of crawling the package with the
+// given path and crawl state at time now. The priority is 1 for a package
+// with no importers, page views or refreshes and grows with the logarithm
+// of the import rank or count, of the popularity relative to the most
+// popular package and of the recent user refreshes.
+func (db *Database) CrawlPriority(path string, st *CrawlState, now time.Time) (float64, error) {
+	counts, err := db.Store.TermCounts([]string{"import:" + path})
+	if err != nil {
+		return 0, err
+	}
+	ranks, err := db.Store.ImportRanks([]string{path})
+	if err != nil {
+		return 0, err
+	}
+	priority := 1 + math.Log10(1+importWeight(counts[0], ranks[0]))
+
+	scores, err := db.Store.PopularScores([]string{path})
+	if err != nil {
+		return 0, err
+	}
+	if scores[0] > 0 {
+		top, err := db.Store.Popular(1)
+		if err != nil {
+			return 0, err
+		}
+		if len(top) > 0 && top[0].Score > 0 {
+			priority += math.Log10(1 + 1000*math.Min(1, scores[0]/top[0].Score))
+		}
+	}
+
+	priority += math.Log2(1 + st.RecentRefreshes(now))
+	return priority, nil
+}
@@ -0,0 +1,127 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file or at
+// https://developers.google.com/open-source/licenses/bsd.
+
+package database
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/golang/gddo/doc"
+)
+
+func TestCrawlState(t *testing.T) {
+	t0 := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	var st CrawlState
+
+	st.RecordSuccess(t0, true)
+	if st.ChangeInterval != 0 || !st.LastChange.Equal(t0) {
+		t.Fatalf("after first change: %+v", st)
+	}
+	st.RecordSuccess(t0.Add(4*day), true)
+	if st.ChangeInterval != 4*day {
+		t.Errorf("change interval is %v, want %v", st.ChangeInterval, 4*day)
+	}
+	st.RecordSuccess(t0.Add(6*day), true)
+	if st.ChangeInterval != 3*day {
+		t.Errorf("change interval is %v, want %v", st.ChangeInterval, 3*day)
+	}
+	// The interval grows while the package does not change.
+	st.RecordSuccess(t0.Add(16*day), false)
+	if st.ChangeInterval != 10*day {
+		t.Errorf("change interval is %v, want %v", st.ChangeInterval, 10*day)
+	}
+
+	st.RecordFailure(t0.Add(17*day), errors.New("timeout"))
+	st.RecordFailure(t0.Add(18*day), errors.New("bad gateway"))
+	if st.Failures != 2 || st.LastError != "bad gateway" {
+		t.Errorf("after failures: %+v", st)
+	}
+	st.RecordSuccess(t0.Add(19*day), false)
+	if st.Failures != 0 || st.LastError != "" {
+		t.Errorf("after success: %+v", st)
+	}
+
+	st.RecordRefresh(t0)
+	st.RecordRefresh(t0)
+	if got := st.RecentRefreshes(t0.Add(refreshHalfLife)); math.Abs(got-1) > 1e-9 {
+		t.Errorf("recent refreshes after half life is %g, want 1", got)
+	}
+}
+
+func TestFileStoreCrawlState(t *testing.T) {
+	ctx := context.Background()
+	db, _, cleanup := newFileDB(t)
+	defer cleanup()
+
+	now := time.Now()
+	for _, p := range []struct {
+		path string
+		next time.Duration
+	}{
+		{"example.com/a", -time.Hour},
+		{"example.com/b", -3 * time.Hour},
+		{"example.com/c", time.Hour},
+		{"other.org/d", 0},
+	} {
+		pdoc := &doc.Package{ImportPath: p.path, ProjectRoot: p.path, Name: "p"}
+		if err := db.Put(ctx, pdoc, now.Add(p.next), false); err != nil {
+			t.Fatal(err)
+		}
+	}
+	paths, err := db.DueCrawls(now, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]string{"example.com/b", "example.com/a", "other.org/d"}, paths); diff != "" {
+		t.Errorf("DueCrawls differs (-want +got):\n%s", diff)
+	}
+	if paths, _ := db.DueCrawls(now, 1); len(paths) != 1 {
+		t.Errorf("DueCrawls(now, 1) = %v, want one path", paths)
+	}
+
+	st, err := db.CrawlState("example.com/a")
+	if err != nil || st == nil || st.Failures != 0 {
+		t.Fatalf("CrawlState of a new path = %+v, %v; want zero state", st, err)
+	}
+	st.RecordFailure(now, errors.New("timeout"))
+	if err := db.PutCrawlState("example.com/a", st); err != nil {
+		t.Fatal(err)
+	}
+	st.Failures = 10
+	if st, _ := db.CrawlState("example.com/a"); st.Failures != 1 || st.LastError != "timeout" {
+		t.Errorf("CrawlState(example.com/a) = %+v, want one failure", st)
+	}
+
+	// Packages with importers have a higher priority.
+	if err := db.Put(ctx, &doc.Package{ImportPath: "other.org/e", Name: "p", Imports: []string{"example.com/b"}}, time.Time{}, false); err != nil {
+		t.Fatal(err)
+	}
+	pa, err := db.CrawlPriority("example.com/a", &CrawlState{}, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pb, err := db.CrawlPriority("example.com/b", &CrawlState{}, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pa != 1 || pb <= pa {
+		t.Errorf("crawl priorities are %g and %g, want 1 and more", pa, pb)
+	}
+
+	if err := db.Block("example.com"); err != nil {
+		t.Fatal(err)
+	}
+	if st, _ := db.CrawlState("example.com/a"); st.Failures != 0 {
+		t.Errorf("CrawlState(example.com/a) after Block = %+v, want zero state", st)
+	}
+}
@@ -40,11 +40,16 @@ func newDB(t *testing.T) *Database {
 		t.Errorf("DBSIZE returned %d, %v", n, err)
 	}
 
-	return &Database{Pool: p, RemoteClient: nil}
+	return &Database{Store: &redisStore{pool: p}, RemoteClient: nil}
+}
+
+// redisConn returns a connection to the Redis server of a test database.
+func redisConn(db *Database) redis.Conn {
+	return db.Store.(*redisStore).pool.Get()
 }
 
 func closeDB(db *Database) {
-	c := db.Pool.Get()
+	c := redisConn(db)
 	c.Do("FLUSHDB")
 	c.Close()
 }
@@ -176,7 +181,7 @@ func TestPutGet(t *testing.T) {
 		t.Errorf("db.IsBlocked(github.com/foo/bar) returned %v, %v, want false, nil", blocked, err)
 	}
 
-	c := db.Pool.Get()
+	c := redisConn(db)
 	defer c.Close()
 	c.Send("DEL", "maxQueryId")
 	c.Send("DEL", "maxPackageId")
@@ -195,10 +200,39 @@ func TestPutGet(t *testing.T) {
 
 const epsilon = 0.000001
 
+func TestDocs(t *testing.T) {
+	ctx := context.Background()
+	db := newDB(t)
+	defer closeDB(db)
+
+	// SCAN returns about ten keys per call. Store enough packages for
+	// several calls, and check that the last batch is not dropped.
+	want := make(map[string]string)
+	for i := 0; i < 25; i++ {
+		path := "github.com/user/repo" + strconv.Itoa(i)
+		pdoc := &doc.Package{ImportPath: path, Name: "repo", Synopsis: "Package " + strconv.Itoa(i) + ".", ProjectRoot: path}
+		if err := db.Put(ctx, pdoc, time.Time{}, false); err != nil {
+			t.Fatal(err)
+		}
+		want[path] = pdoc.Synopsis
+	}
+	got := make(map[string]string)
+	err := db.Store.Docs(func(r *Record) error {
+		got[r.Path] = r.Synopsis
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Docs returned error %v", err)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Docs mismatch (-want +got):\n%s", diff)
+	}
+}
+
 func TestPopular(t *testing.T) {
 	db := newDB(t)
 	defer closeDB(db)
-	c := db.Pool.Get()
+	c := redisConn(db)
 	defer c.Close()
 
 	// Add scores for packages. On each iteration, add half-life to time and
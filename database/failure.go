@@ -0,0 +1,179 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file or at
+// https://developers.google.com/open-source/licenses/bsd.
+
+package database
+
+import (
+	"context"
+	"net"
+	"sort"
+	"time"
+
+	"github.com/golang/gddo/gosrc"
+)
+
+// Crawl error classes. See CrawlErrorClass.
+const (
+	// CrawlNotFound is the class of errors reporting that the package does
+	// not exist, is blocked or has no Go files.
+	CrawlNotFound = "notfound"
+
+	// CrawlRemote is the class of errors of the remote host or of the
+	// connection to the host.
+	CrawlRemote = "remote"
+
+	// CrawlTimeout is the class of timeouts.
+	CrawlTimeout = "timeout"
+
+	// CrawlError is the class of other errors.
+	CrawlError = "error"
+)
+
+// A path failing to crawl with a transient error is crawled again after
+// crawlRetryDelay, doubled for each failed crawl, up to maxCrawlRetries
+// times. The records of the paths that are not retried are kept for
+// crawlFailureRetention so that AddNewCrawl ignores the paths meanwhile.
+const (
+	crawlRetryDelay       = time.Hour
+	maxCrawlRetries       = 5
+	crawlFailureRetention = 30 * 24 * time.Hour
+)
+
+// CrawlErrorClass returns the class of an error returned by a crawl.
+func CrawlErrorClass(err error) string {
+	switch err := err.(type) {
+	case gosrc.NotFoundError:
+		return CrawlNotFound
+	case *gosrc.RemoteError:
+		return CrawlRemote
+	case net.Error:
+		if err.Timeout() {
+			return CrawlTimeout
+		}
+		return CrawlRemote
+	}
+	if err == context.DeadlineExceeded {
+		return CrawlTimeout
+	}
+	return CrawlError
+}
+
+// CrawlFailure is the record of the failed crawls of an import path. The
+// record is deleted when the package is stored.
+type CrawlFailure struct {
+	Path string `json:"path"`
+
+	// Class and Message are the class and the message of the error of the
+	// last crawl.
+	Class   string `json:"class"`
+	Message string `json:"message"`
+
+	// Attempts is the number of failed crawls.
+	Attempts int `json:"attempts"`
+
+	FirstAttempt time.Time `json:"firstAttempt"`
+	LastAttempt  time.Time `json:"lastAttempt"`
+}
+
+// Transient returns true if the error of the last crawl may go away when
+// the path is crawled again.
+func (f *CrawlFailure) Transient() bool {
+	return f.Class == CrawlRemote || f.Class == CrawlTimeout
+}
+
+// NextRetry returns the time the path is due for a retry or the zero time
+// if the path is not retried.
+func (f *CrawlFailure) NextRetry() time.Time {
+	if !f.Transient() || f.Attempts > maxCrawlRetries {
+		return time.Time{}
+	}
+	return f.LastAttempt.Add(crawlRetryDelay << uint(f.Attempts-1))
+}
+
+// AddCrawlFailure records a crawl of the path failed with the error err at
+// time t.
+func (db *Database) AddCrawlFailure(path string, err error, t time.Time) error {
+	f, ferr := db.Store.GetCrawlFailure(path)
+	if ferr != nil {
+		return ferr
+	}
+	if f == nil {
+		f = &CrawlFailure{Path: path, FirstAttempt: t}
+	}
+	f.Class = CrawlErrorClass(err)
+	f.Message = err.Error()
+	f.Attempts++
+	f.LastAttempt = t
+	return db.Store.PutCrawlFailure(f)
+}
+
+// DeleteCrawlFailure deletes the crawl failure record of the path.
+func (db *Database) DeleteCrawlFailure(path string) error {
+	return db.Store.DeleteCrawlFailure(path)
+}
+
+// CrawlFailures returns the crawl failure records of the given class, or of
+// all classes if class is "", the most recent first.
+func (db *Database) CrawlFailures(class string) ([]*CrawlFailure, error) {
+	all, err := db.Store.CrawlFailures()
+	if err != nil {
+		return nil, err
+	}
+	var failures []*CrawlFailure
+	for _, f := range all {
+		if class == "" || f.Class == class {
+			failures = append(failures, f)
+		}
+	}
+	sort.Slice(failures, func(i, j int) bool {
+		if !failures[i].LastAttempt.Equal(failures[j].LastAttempt) {
+			return failures[i].LastAttempt.After(failures[j].LastAttempt)
+		}
+		return failures[i].Path < failures[j].Path
+	})
+	return failures, nil
+}
+
+// RetryCrawlFailures adds the paths due for a retry at time now to the new
+// crawl set and deletes the records of the paths that are not retried and
+// last failed more than crawlFailureRetention ago. Stored packages are
+// crawled again by their crawl schedule instead. RetryCrawlFailures returns
+// the number of paths added to the new crawl set.
+func (db *Database) RetryCrawlFailures(now time.Time) (int, error) {
+	failures, err := db.Store.CrawlFailures()
+	if err != nil {
+		return 0, err
+	}
+	n := 0
+	for _, f := range failures {
+		retry := f.NextRetry()
+		switch {
+		case !retry.IsZero():
+			if retry.After(now) {
+				continue
+			}
+			stored, err := db.Store.Exists(f.Path)
+			if err != nil {
+				return n, err
+			}
+			if stored {
+				continue
+			}
+			added, err := db.Store.RetryCrawl(f.Path)
+			if err != nil {
+				return n, err
+			}
+			if added {
+				n++
+			}
+		case now.Sub(f.LastAttempt) > crawlFailureRetention:
+			if err := db.Store.DeleteCrawlFailure(f.Path); err != nil {
+				return n, err
+			}
+		}
+	}
+	return n, nil
+}
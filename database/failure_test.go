@@ -0,0 +1,138 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file or at
+// https://developers.google.com/open-source/licenses/bsd.
+
+package database
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/golang/gddo/doc"
+	"github.com/golang/gddo/gosrc"
+)
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func TestCrawlErrorClass(t *testing.T) {
+	for _, tt := range []struct {
+		err  error
+		want string
+	}{
+		{gosrc.NotFoundError{Message: "blocked."}, CrawlNotFound},
+		{&gosrc.RemoteError{Host: "example.com"}, CrawlRemote},
+		{timeoutError{}, CrawlTimeout},
+		{context.DeadlineExceeded, CrawlTimeout},
+		{errors.New("bad"), CrawlError},
+	} {
+		if got := CrawlErrorClass(tt.err); got != tt.want {
+			t.Errorf("CrawlErrorClass(%T) = %q, want %q", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestFileStoreCrawlFailures(t *testing.T) {
+	ctx := context.Background()
+	db, _, cleanup := newFileDB(t)
+	defer cleanup()
+
+	t0 := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	add := func(path string, err error, t1 time.Time) {
+		if err := db.AddCrawlFailure(path, err, t1); err != nil {
+			t.Fatal(err)
+		}
+	}
+	add("example.com/flaky", timeoutError{}, t0)
+	add("example.com/flaky", timeoutError{}, t0.Add(time.Minute))
+	add("example.com/gone", gosrc.NotFoundError{Message: "not found"}, t0)
+	add("example.com/stored", timeoutError{}, t0)
+	if err := db.Put(ctx, &doc.Package{ImportPath: "example.com/stored", Name: "p"}, time.Time{}, false); err != nil {
+		t.Fatal(err)
+	}
+
+	failures, err := db.CrawlFailures("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*CrawlFailure{{
+		Path:         "example.com/flaky",
+		Class:        CrawlTimeout,
+		Message:      "i/o timeout",
+		Attempts:     2,
+		FirstAttempt: t0,
+		LastAttempt:  t0.Add(time.Minute),
+	}, {
+		Path:         "example.com/gone",
+		Class:        CrawlNotFound,
+		Message:      "not found",
+		Attempts:     1,
+		FirstAttempt: t0,
+		LastAttempt:  t0,
+	}}
+	if diff := cmp.Diff(want, failures); diff != "" {
+		t.Errorf("CrawlFailures differs (-want +got):\n%s", diff)
+	}
+	if failures, _ := db.CrawlFailures(CrawlNotFound); len(failures) != 1 || failures[0].Path != "example.com/gone" {
+		t.Errorf("CrawlFailures(%q) = %v, want example.com/gone", CrawlNotFound, failures)
+	}
+
+	// Paths with failure records are not added to the new crawl set.
+	if err := db.AddNewCrawl("example.com/gone"); err != nil {
+		t.Fatal(err)
+	}
+	if paths, _ := db.NewCrawls(); len(paths) != 0 {
+		t.Errorf("NewCrawls() = %v, want none", paths)
+	}
+
+	// The second attempt is retried two hours after the attempt.
+	retry := func(now time.Time, want int) {
+		t.Helper()
+		n, err := db.RetryCrawlFailures(now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n != want {
+			t.Errorf("RetryCrawlFailures(%v) = %d, want %d", now, n, want)
+		}
+	}
+	retry(t0.Add(time.Hour), 0)
+	retry(t0.Add(3*time.Hour), 1)
+	retry(t0.Add(4*time.Hour), 0)
+	if paths, _ := db.NewCrawls(); !cmp.Equal(paths, []string{"example.com/flaky"}) {
+		t.Errorf("NewCrawls() = %v, want example.com/flaky", paths)
+	}
+
+	// The records of the paths that are not retried expire.
+	retry(t0.Add(crawlFailureRetention+time.Hour), 0)
+	if failures, _ := db.CrawlFailures(""); len(failures) != 1 || failures[0].Path != "example.com/flaky" {
+		t.Errorf("CrawlFailures after retention = %v, want example.com/flaky", failures)
+	}
+
+	for i := 2; i < maxCrawlRetries; i++ {
+		add("example.com/flaky", timeoutError{}, t0)
+	}
+	if f, _ := db.Store.GetCrawlFailure("example.com/flaky"); f.NextRetry().IsZero() {
+		t.Errorf("no retry after %d attempts", f.Attempts)
+	}
+	add("example.com/flaky", timeoutError{}, t0)
+	if f, _ := db.Store.GetCrawlFailure("example.com/flaky"); !f.NextRetry().IsZero() {
+		t.Errorf("retry after %d attempts", f.Attempts)
+	}
+
+	if err := db.Block("example.com"); err != nil {
+		t.Fatal(err)
+	}
+	if failures, _ := db.CrawlFailures(""); len(failures) != 0 {
+		t.Errorf("CrawlFailures after Block = %v, want none", failures)
+	}
+}
@@ -0,0 +1,1348 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file or at
+// https://developers.google.com/open-source/licenses/bsd.
+
+package database
+
+import (
+	"bytes"
+	"encoding/gob"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// fileStoreVersion is the version of the file format. Files with another
+// version are not opened.
+const fileStoreVersion = 2
+
+// fileStoreFlushDelay is the maximum time a change to a file store is kept
+// only in memory.
+const fileStoreFlushDelay = 2 * time.Second
+
+// fileStoreLockTimeout is how long OpenFileStore waits for another process
+// to close the file.
+const fileStoreLockTimeout = time.Second
+
+// Buckets of the file. Each bucket of fileBuckets holds one of the maps of
+// fileData, except metaBucket, which holds the fileMeta record. The buckets
+// of valueBuckets hold the large values, which are read when requested.
+const (
+	metaBucket     = "meta"
+	packagesBucket = "packages"
+	newCrawlBucket = "newcrawl"
+	blockBucket    = "block"
+	popularBucket  = "popular"
+	ranksBucket    = "ranks"
+	countersBucket = "counters"
+	historyBucket  = "history"
+	crawlBucket    = "crawl"
+	failuresBucket = "failures"
+
+	docsBucket     = "docs"     // import path to Record.Doc
+	symbolsBucket  = "symbols"  // import path to Record.Symbols
+	typesBucket    = "types"    // valueKey(import path, type name) to type page
+	sourcesBucket  = "sources"  // valueKey(import path, file name) to source
+	versionsBucket = "versions" // valueKey(import path, version key) to VersionRecord
+	blobsBucket    = "blobs"
+)
+
+var fileBuckets = []string{
+	metaBucket,
+	packagesBucket,
+	newCrawlBucket,
+	blockBucket,
+	popularBucket,
+	ranksBucket,
+	countersBucket,
+	historyBucket,
+	crawlBucket,
+	failuresBucket,
+}
+
+var valueBuckets = []string{
+	docsBucket,
+	symbolsBucket,
+	typesBucket,
+	sourcesBucket,
+	versionsBucket,
+	blobsBucket,
+}
+
+var metaKey = []byte("meta")
+
+// fileStore is a Store saved to a bolt database file. The small records of
+// fileData, such as the package metadata and the crawl state, are kept in
+// memory together with the term and implementation indexes, which are not
+// saved and built from the packages when the file is opened. The large
+// values of valueBuckets, such as the documents and the history, are only
+// kept in the file and read in a transaction when requested.
+//
+// The large values are written when they are stored, before the records
+// referring to them are changed. Changes to the records of fileData are
+// recorded as dirty keys and written to the file shortly after the change
+// and when the store is closed. The records of the dirty keys are copied
+// while holding mu and encoded and written after releasing mu, so the maps
+// and slices referenced by a record must not be modified after the record
+// is stored. The file is locked while the store is open.
+type fileStore struct {
+	name string
+	db   *bolt.DB
+
+	flushMu sync.Mutex // serializes flushes
+	writeMu sync.Mutex // serializes the writes of large values, held before mu
+
+	mu    sync.Mutex
+	data  fileData
+	terms map[string]map[string]bool // search term to paths
+	impls map[string]map[string]bool // interface package to path#Interface#Type
+	dirty map[string]map[string]bool // bucket to changed keys
+	timer *time.Timer                // pending flush
+	err   error                      // error of the last flush
+}
+
+// fileData is the content of the file.
+type fileData struct {
+	MaxID    int
+	Packages map[string]*filePackage // by import path
+	NewCrawl map[string]bool
+	Block    map[string]bool
+	Popular  map[string]float64 // import path to popularity score
+	Popular0 float64            // scaled base time for popularity scores
+	Ranks    map[string]float64 // import path to import rank
+	Counters map[string]*fileCounter
+	History  map[string][]*VersionRecord // by import path, newest first, without Doc and Types
+	Crawl    map[string]*CrawlState      // by import path
+	Failures map[string]*CrawlFailure    // by import path
+}
+
+// fileMeta is the record of the meta bucket.
+type fileMeta struct {
+	Version  int
+	MaxID    int
+	Popular0 float64
+}
+
+// filePackage is the record of a package. The document, symbols, type
+// pages and sources are stored in the buckets of valueBuckets.
+type filePackage struct {
+	ID       string
+	Path     string
+	Synopsis string
+	Score    float64
+	Terms    []string
+	Etag     string
+	Kind     string
+	Coverage string
+	Impls    []string
+	Methods  []string
+
+	// Crawl is the Unix time of the next crawl reported for the package
+	// and Queue orders the package in the crawl queue. Queue is zero if
+	// the package is not in the queue.
+	Crawl int64
+	Queue int64
+}
+
+type fileCounter struct {
+	N, T    float64
+	Expires time.Time
+}
+
+// OpenFileStore opens the store saved in the named file. The file is
+// created if it does not exist. OpenFileStore fails if another process has
+// the file open.
+func OpenFileStore(name string) (Store, error) {
+	db, err := bolt.Open(name, 0644, &bolt.Options{Timeout: fileStoreLockTimeout})
+	if err == bolt.ErrTimeout {
+		return nil, fmt.Errorf("%s is in use by another process", name)
+	} else if err != nil {
+		return nil, err
+	}
+	s := &fileStore{name: name, db: db}
+	s.init()
+	if err := db.Update(s.load); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("reading %s: %v", name, err)
+	}
+	return s, nil
+}
+
+// init makes the maps of the data and the indexes.
+func (s *fileStore) init() {
+	s.data = fileData{
+		Packages: make(map[string]*filePackage),
+		NewCrawl: make(map[string]bool),
+		Block:    make(map[string]bool),
+		Popular:  make(map[string]float64),
+		Ranks:    make(map[string]float64),
+		Counters: make(map[string]*fileCounter),
+		History:  make(map[string][]*VersionRecord),
+		Crawl:    make(map[string]*CrawlState),
+		Failures: make(map[string]*CrawlFailure),
+	}
+	s.terms = make(map[string]map[string]bool)
+	s.impls = make(map[string]map[string]bool)
+	s.dirty = make(map[string]map[string]bool)
+}
+
+// load creates the buckets of a new file or reads the records of an
+// existing file and builds the indexes.
+func (s *fileStore) load(tx *bolt.Tx) error {
+	for _, name := range append(fileBuckets, valueBuckets...) {
+		if _, err := tx.CreateBucketIfNotExists([]byte(name)); err != nil {
+			return err
+		}
+	}
+	meta := fileMeta{Version: fileStoreVersion}
+	if p := tx.Bucket([]byte(metaBucket)).Get(metaKey); p == nil {
+		if err := putGob(tx.Bucket([]byte(metaBucket)), metaKey, &meta); err != nil {
+			return err
+		}
+	} else if err := gob.NewDecoder(bytes.NewReader(p)).Decode(&meta); err != nil {
+		return err
+	} else if meta.Version != fileStoreVersion {
+		return fmt.Errorf("file has version %d, want %d", meta.Version, fileStoreVersion)
+	}
+	s.data.MaxID = meta.MaxID
+	s.data.Popular0 = meta.Popular0
+
+	for _, name := range fileBuckets[1:] {
+		err := tx.Bucket([]byte(name)).ForEach(func(k, v []byte) error {
+			return s.decode(name, string(k), v)
+		})
+		if err != nil {
+			return fmt.Errorf("%s bucket: %v", name, err)
+		}
+	}
+	for _, p := range s.data.Packages {
+		s.addTerms(p.Path, p.Terms)
+		s.addImpls(p.Path, p.Impls)
+	}
+	return nil
+}
+
+// decode decodes the record p of the key in the bucket and adds it to the
+// data.
+func (s *fileStore) decode(bucket, key string, p []byte) error {
+	d := &s.data
+	dec := gob.NewDecoder(bytes.NewReader(p))
+	var err error
+	switch bucket {
+	case packagesBucket:
+		var v filePackage
+		err = dec.Decode(&v)
+		d.Packages[key] = &v
+	case newCrawlBucket:
+		d.NewCrawl[key] = true
+	case blockBucket:
+		d.Block[key] = true
+	case popularBucket:
+		var v float64
+		err = dec.Decode(&v)
+		d.Popular[key] = v
+	case ranksBucket:
+		var v float64
+		err = dec.Decode(&v)
+		d.Ranks[key] = v
+	case countersBucket:
+		var v fileCounter
+		err = dec.Decode(&v)
+		d.Counters[key] = &v
+	case historyBucket:
+		var v []*VersionRecord
+		err = dec.Decode(&v)
+		d.History[key] = v
+	case crawlBucket:
+		var v CrawlState
+		err = dec.Decode(&v)
+		d.Crawl[key] = &v
+	case failuresBucket:
+		var v CrawlFailure
+		err = dec.Decode(&v)
+		d.Failures[key] = &v
+	default:
+		err = fmt.Errorf("unknown bucket %s", bucket)
+	}
+	return err
+}
+
+// record returns a copy of the record of the key in the bucket or nil if
+// the key is not in the data. The caller holds s.mu.
+func (s *fileStore) record(bucket, key string) interface{} {
+	d := &s.data
+	switch bucket {
+	case packagesBucket:
+		if p := d.Packages[key]; p != nil {
+			pCopy := *p
+			return &pCopy
+		}
+	case newCrawlBucket:
+		if d.NewCrawl[key] {
+			return true
+		}
+	case blockBucket:
+		if d.Block[key] {
+			return true
+		}
+	case popularBucket:
+		if v, ok := d.Popular[key]; ok {
+			return v
+		}
+	case ranksBucket:
+		if v, ok := d.Ranks[key]; ok {
+			return v
+		}
+	case countersBucket:
+		if c := d.Counters[key]; c != nil {
+			cCopy := *c
+			return &cCopy
+		}
+	case historyBucket:
+		if versions, ok := d.History[key]; ok {
+			return append([]*VersionRecord(nil), versions...)
+		}
+	case crawlBucket:
+		if st := d.Crawl[key]; st != nil {
+			stCopy := *st
+			return &stCopy
+		}
+	case failuresBucket:
+		if f := d.Failures[key]; f != nil {
+			fCopy := *f
+			return &fCopy
+		}
+	}
+	return nil
+}
+
+func putGob(b *bolt.Bucket, key []byte, v interface{}) error {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(v); err != nil {
+		return err
+	}
+	return b.Put(key, buf.Bytes())
+}
+
+// valueKey returns the key of the value with the given name of the package
+// with the given path in the types, sources and versions buckets.
+func valueKey(path, name string) []byte {
+	return []byte(path + "\x00" + name)
+}
+
+// putValue puts the value of the key in the bucket or deletes the key if
+// the value is nil.
+func putValue(b *bolt.Bucket, key, v []byte) error {
+	if v == nil {
+		return b.Delete(key)
+	}
+	return b.Put(key, v)
+}
+
+// putValues replaces the values of the package with the given path in the
+// bucket.
+func putValues(b *bolt.Bucket, path string, values map[string][]byte) error {
+	if err := deleteValues(b, path); err != nil {
+		return err
+	}
+	for name, v := range values {
+		if err := b.Put(valueKey(path, name), v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteValues deletes the values of the package with the given path from
+// the bucket.
+func deleteValues(b *bolt.Bucket, path string) error {
+	prefix := valueKey(path, "")
+	var keys [][]byte
+	c := b.Cursor()
+	for k, _ := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, _ = c.Next() {
+		keys = append(keys, append([]byte(nil), k...))
+	}
+	for _, k := range keys {
+		if err := b.Delete(k); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deletePackageValues deletes the document, symbols, type pages and
+// sources of the package with the given path.
+func deletePackageValues(tx *bolt.Tx, path string) error {
+	for _, name := range []string{docsBucket, symbolsBucket} {
+		if err := tx.Bucket([]byte(name)).Delete([]byte(path)); err != nil {
+			return err
+		}
+	}
+	for _, name := range []string{typesBucket, sourcesBucket} {
+		if err := deleteValues(tx.Bucket([]byte(name)), path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getValue returns a copy of the value of the key in the bucket or nil if
+// the key is not in the bucket.
+func (s *fileStore) getValue(bucket string, key []byte) ([]byte, error) {
+	var v []byte
+	err := s.db.View(func(tx *bolt.Tx) error {
+		if p := tx.Bucket([]byte(bucket)).Get(key); p != nil {
+			v = append([]byte{}, p...)
+		}
+		return nil
+	})
+	return v, err
+}
+
+func addToSet(m map[string]map[string]bool, key, member string) {
+	set := m[key]
+	if set == nil {
+		set = make(map[string]bool)
+		m[key] = set
+	}
+	set[member] = true
+}
+
+func removeFromSet(m map[string]map[string]bool, key, member string) {
+	set := m[key]
+	delete(set, member)
+	if len(set) == 0 {
+		delete(m, key)
+	}
+}
+
+func (s *fileStore) addTerms(path string, terms []string) {
+	for _, term := range terms {
+		addToSet(s.terms, term, path)
+	}
+}
+
+func (s *fileStore) removeTerms(path string, terms []string) {
+	for _, term := range terms {
+		removeFromSet(s.terms, term, path)
+	}
+}
+
+// splitImpl splits an implementation term into the interface package and
+// the rest of the term.
+func splitImpl(impl string) (dep, rest string, ok bool) {
+	i := strings.IndexByte(impl, '#')
+	if i <= 0 {
+		return "", "", false
+	}
+	return impl[:i], impl[i+1:], true
+}
+
+func (s *fileStore) addImpls(path string, impls []string) {
+	for _, impl := range impls {
+		if dep, rest, ok := splitImpl(impl); ok {
+			addToSet(s.impls, dep, path+"#"+rest)
+		}
+	}
+}
+
+func (s *fileStore) removeImpls(path string, impls []string) {
+	for _, impl := range impls {
+		if dep, rest, ok := splitImpl(impl); ok {
+			removeFromSet(s.impls, dep, path+"#"+rest)
+		}
+	}
+}
+
+// changed marks the key in the bucket as changed and schedules a flush.
+// The caller holds s.mu.
+func (s *fileStore) changed(bucket, key string) {
+	addToSet(s.dirty, bucket, key)
+	if s.timer == nil {
+		s.timer = time.AfterFunc(fileStoreFlushDelay, func() {
+			if err := s.flush(); err != nil {
+				log.Printf("saving %s: %v", s.name, err)
+			}
+		})
+	}
+}
+
+// fileWrite is a pending write of a record. A nil record deletes the key.
+type fileWrite struct {
+	bucket, key string
+	record      interface{}
+}
+
+// flush writes the changed records to the file. The records are copied
+// while holding s.mu and encoded and written after releasing it. Records
+// that cannot be written are marked as changed again.
+func (s *fileStore) flush() error {
+	s.flushMu.Lock()
+	defer s.flushMu.Unlock()
+
+	s.mu.Lock()
+	if s.timer != nil {
+		s.timer.Stop()
+		s.timer = nil
+	}
+	now := time.Now()
+	for key, c := range s.data.Counters {
+		if now.After(c.Expires) {
+			delete(s.data.Counters, key)
+			addToSet(s.dirty, countersBucket, key)
+		}
+	}
+	var writes []fileWrite
+	for bucket, keys := range s.dirty {
+		for key := range keys {
+			writes = append(writes, fileWrite{bucket, key, s.record(bucket, key)})
+		}
+	}
+	s.dirty = make(map[string]map[string]bool)
+	meta := fileMeta{Version: fileStoreVersion, MaxID: s.data.MaxID, Popular0: s.data.Popular0}
+	s.mu.Unlock()
+
+	if len(writes) == 0 {
+		return nil
+	}
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		for _, w := range writes {
+			b := tx.Bucket([]byte(w.bucket))
+			var err error
+			if w.record == nil {
+				err = b.Delete([]byte(w.key))
+			} else {
+				err = putGob(b, []byte(w.key), w.record)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return putGob(tx.Bucket([]byte(metaBucket)), metaKey, &meta)
+	})
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.err = err
+	if err != nil {
+		for _, w := range writes {
+			s.changed(w.bucket, w.key)
+		}
+	}
+	return err
+}
+
+// CheckHealth returns the error of the last flush.
+func (s *fileStore) CheckHealth() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err
+}
+
+// Close writes pending changes and closes the file.
+func (s *fileStore) Close() error {
+	err := s.flush()
+	if cerr := s.db.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+func (s *fileStore) Exists(path string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.data.Packages[path]
+	return ok, nil
+}
+
+func (s *fileStore) ID(path string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if p := s.data.Packages[path]; p != nil {
+		return p.ID, nil
+	}
+	return "", nil
+}
+
+func (s *fileStore) PutDoc(r *Record) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		if err := putValue(tx.Bucket([]byte(docsBucket)), []byte(r.Path), r.Doc); err != nil {
+			return err
+		}
+		if err := putValue(tx.Bucket([]byte(symbolsBucket)), []byte(r.Path), r.Symbols); err != nil {
+			return err
+		}
+		if err := putValues(tx.Bucket([]byte(typesBucket)), r.Path, r.Types); err != nil {
+			return err
+		}
+		return putValues(tx.Bucket([]byte(sourcesBucket)), r.Path, r.Sources)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p := s.data.Packages[r.Path]
+	if p == nil {
+		s.data.MaxID++
+		p = &filePackage{ID: strconv.Itoa(s.data.MaxID), Path: r.Path}
+		s.data.Packages[r.Path] = p
+	}
+	s.removeTerms(p.Path, p.Terms)
+	s.addTerms(p.Path, r.Terms)
+	delete(s.data.Failures, r.Path)
+	delete(s.data.NewCrawl, r.Path)
+	if !r.NextCrawl.IsZero() {
+		p.Crawl = r.NextCrawl.Unix()
+		p.Queue = p.Crawl
+	}
+	p.Synopsis = r.Synopsis
+	p.Score = r.Score
+	p.Terms = r.Terms
+	p.Etag = r.Etag
+	p.Kind = r.Kind
+	p.Coverage = r.Coverage
+	s.changed(packagesBucket, r.Path)
+	s.changed(failuresBucket, r.Path)
+	s.changed(newCrawlBucket, r.Path)
+	return nil
+}
+
+func (s *fileStore) GetDoc(path string) (*Record, error) {
+	s.mu.Lock()
+	var p *filePackage
+	if path == "-" {
+		for _, q := range s.data.Packages {
+			if q.Queue != 0 && (p == nil || q.Queue < p.Queue || q.Queue == p.Queue && q.Path < p.Path) {
+				p = q
+			}
+		}
+	} else {
+		p = s.data.Packages[path]
+	}
+	if p == nil {
+		s.mu.Unlock()
+		return nil, nil
+	}
+	r := &Record{ID: p.ID, Path: p.Path}
+	if p.Crawl != 0 {
+		r.NextCrawl = time.Unix(p.Crawl, 0).UTC()
+	}
+	s.mu.Unlock()
+
+	var err error
+	r.Doc, err = s.getValue(docsBucket, []byte(r.Path))
+	if r.Doc == nil || err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// deleteDoc deletes the record of the package with the given path. The
+// caller holds s.mu and deletes the values of the package, see
+// deletePackageValues.
+func (s *fileStore) deleteDoc(path string) {
+	p := s.data.Packages[path]
+	if p == nil {
+		return
+	}
+	s.removeTerms(path, p.Terms)
+	s.removeImpls(path, p.Impls)
+	delete(s.data.NewCrawl, path)
+	delete(s.data.Popular, path)
+	delete(s.data.Ranks, path)
+	delete(s.data.Packages, path)
+	s.changed(newCrawlBucket, path)
+	s.changed(popularBucket, path)
+	s.changed(ranksBucket, path)
+	s.changed(packagesBucket, path)
+}
+
+func (s *fileStore) DeleteDoc(path string) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	s.mu.Lock()
+	s.deleteDoc(path)
+	s.mu.Unlock()
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return deletePackageValues(tx, path)
+	})
+}
+
+// Docs reads the document of each package in a separate transaction, so
+// that f may use the store.
+func (s *fileStore) Docs(f func(*Record) error) error {
+	s.mu.Lock()
+	records := make([]*Record, 0, len(s.data.Packages))
+	for _, p := range s.data.Packages {
+		records = append(records, &Record{
+			ID:       p.ID,
+			Path:     p.Path,
+			Synopsis: p.Synopsis,
+			Kind:     p.Kind,
+			Score:    p.Score,
+			Terms:    p.Terms,
+		})
+	}
+	s.mu.Unlock()
+
+	sort.Slice(records, func(i, j int) bool { return records[i].Path < records[j].Path })
+	for _, r := range records {
+		var err error
+		r.Doc, err = s.getValue(docsBucket, []byte(r.Path))
+		if err != nil {
+			return err
+		}
+		if r.Doc == nil {
+			// Deleted meanwhile.
+			continue
+		}
+		if err := f(r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *fileStore) PackageTerms(f func(*Record) error) error {
+	s.mu.Lock()
+	records := make([]*Record, 0, len(s.data.Packages))
+	for _, p := range s.data.Packages {
+		records = append(records, &Record{Path: p.Path, Terms: p.Terms})
+	}
+	s.mu.Unlock()
+
+	sort.Slice(records, func(i, j int) bool { return records[i].Path < records[j].Path })
+	for _, r := range records {
+		if err := f(r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *fileStore) Types(path string, names []string) ([][]byte, error) {
+	pages := make([][]byte, len(names))
+	err := s.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(typesBucket))
+		for i, name := range names {
+			if p := b.Get(valueKey(path, name)); p != nil {
+				pages[i] = append([]byte{}, p...)
+			}
+		}
+		return nil
+	})
+	return pages, err
+}
+
+func (s *fileStore) Source(path, name string) ([]byte, error) {
+	return s.getValue(sourcesBucket, valueKey(path, name))
+}
+
+func (s *fileStore) Symbols(path string) ([]byte, error) {
+	return s.getValue(symbolsBucket, []byte(path))
+}
+
+func (s *fileStore) PutImplementations(path string, terms []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p := s.data.Packages[path]
+	if p == nil {
+		return nil
+	}
+	s.removeImpls(path, p.Impls)
+	s.addImpls(path, terms)
+	p.Impls = terms
+	s.changed(packagesBucket, path)
+	return nil
+}
+
+func (s *fileStore) Implementations(path string) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var impls []string
+	for impl := range s.impls[path] {
+		impls = append(impls, impl)
+	}
+	return impls, nil
+}
+
+func (s *fileStore) PutMethodSets(path string, terms []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if p := s.data.Packages[path]; p != nil {
+		p.Methods = terms
+		s.changed(packagesBucket, path)
+	}
+	return nil
+}
+
+func (s *fileStore) MethodSets(paths []string) ([][]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	result := make([][]string, len(paths))
+	for i, path := range paths {
+		if p := s.data.Packages[path]; p != nil {
+			result[i] = p.Methods
+		}
+	}
+	return result, nil
+}
+
+func (s *fileStore) Packages(paths []string) ([]*Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	result := make([]*Record, len(paths))
+	for i, path := range paths {
+		r := &Record{Path: path, Kind: "u"}
+		if p := s.data.Packages[path]; p != nil {
+			r.Synopsis = p.Synopsis
+			r.Kind = p.Kind
+			r.Terms = p.Terms
+		}
+		result[i] = r
+	}
+	return result, nil
+}
+
+func (s *fileStore) TermPackages(term string) ([]*Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	result := make([]*Record, 0, len(s.terms[term]))
+	for path := range s.terms[term] {
+		p := s.data.Packages[path]
+		result = append(result, &Record{
+			Path:     p.Path,
+			Synopsis: p.Synopsis,
+			Kind:     p.Kind,
+			Coverage: p.Coverage,
+		})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Path < result[j].Path })
+	return result, nil
+}
+
+func (s *fileStore) TermCounts(terms []string) ([]int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	counts := make([]int, len(terms))
+	for i, term := range terms {
+		counts[i] = len(s.terms[term])
+	}
+	return counts, nil
+}
+
+func (s *fileStore) Query(terms []string) ([]*Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(terms) == 0 {
+		return nil, nil
+	}
+
+	// Intersect the other sets with the smallest set.
+	smallest := s.terms[terms[0]]
+	for _, term := range terms[1:] {
+		if set := s.terms[term]; len(set) < len(smallest) {
+			smallest = set
+		}
+	}
+	var result []*Record
+	for path := range smallest {
+		ok := true
+		for _, term := range terms {
+			if !s.terms[term][path] {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			p := s.data.Packages[path]
+			result = append(result, &Record{Path: p.Path, Synopsis: p.Synopsis, Score: p.Score})
+		}
+	}
+	return result, nil
+}
+
+func (s *fileStore) PutVersion(path string, v *VersionRecord, n int, since time.Time) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	s.mu.Lock()
+	old := s.data.History[path]
+	s.mu.Unlock()
+	versions := []*VersionRecord{{Key: v.Key, Updated: v.Updated}}
+	for _, o := range old {
+		if o.Key != v.Key && !o.Updated.Before(since) {
+			versions = append(versions, o)
+		}
+	}
+	sort.SliceStable(versions, func(i, j int) bool {
+		return versions[i].Updated.After(versions[j].Updated)
+	})
+	if len(versions) > n {
+		versions = versions[:n]
+	}
+	kept := make(map[string]bool)
+	for _, version := range versions {
+		kept[version.Key] = true
+	}
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(versionsBucket))
+		for _, o := range old {
+			if !kept[o.Key] {
+				if err := b.Delete(valueKey(path, o.Key)); err != nil {
+					return err
+				}
+			}
+		}
+		if !kept[v.Key] {
+			return nil
+		}
+		return putGob(b, valueKey(path, v.Key), &VersionRecord{Key: v.Key, Updated: v.Updated, Doc: v.Doc, Types: v.Types})
+	})
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(versions) == 0 {
+		delete(s.data.History, path)
+	} else {
+		s.data.History[path] = versions
+	}
+	s.changed(historyBucket, path)
+	return nil
+}
+
+func (s *fileStore) Versions(path string) ([]*VersionRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var result []*VersionRecord
+	for _, v := range s.data.History[path] {
+		result = append(result, &VersionRecord{Key: v.Key, Updated: v.Updated})
+	}
+	return result, nil
+}
+
+func (s *fileStore) GetVersion(path, key string) (*VersionRecord, error) {
+	p, err := s.getValue(versionsBucket, valueKey(path, key))
+	if p == nil || err != nil {
+		return nil, err
+	}
+	var v VersionRecord
+	if err := gob.NewDecoder(bytes.NewReader(p)).Decode(&v); err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+func (s *fileStore) AddNewCrawl(paths []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, path := range paths {
+		if s.data.Packages[path] == nil && s.data.Failures[path] == nil && !s.data.NewCrawl[path] {
+			s.data.NewCrawl[path] = true
+			s.changed(newCrawlBucket, path)
+		}
+	}
+	return nil
+}
+
+func (s *fileStore) RetryCrawl(path string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.data.NewCrawl[path] {
+		return false, nil
+	}
+	s.data.NewCrawl[path] = true
+	s.changed(newCrawlBucket, path)
+	return true, nil
+}
+
+func (s *fileStore) PopNewCrawl() (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for path := range s.data.NewCrawl {
+		delete(s.data.NewCrawl, path)
+		s.changed(newCrawlBucket, path)
+		return path, nil
+	}
+	return "", nil
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (s *fileStore) NewCrawls() ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return sortedKeys(s.data.NewCrawl), nil
+}
+
+func (s *fileStore) SetNextCrawl(path string, t time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if p := s.data.Packages[path]; p != nil {
+		p.Crawl = t.Unix()
+		p.Queue = p.Crawl
+		s.changed(packagesBucket, path)
+	}
+	return nil
+}
+
+// BumpCrawl sets the crawl time to now. To avoid continuously crawling
+// frequently updated repositories, the crawl is queued in the future.
+func (s *fileStore) BumpCrawl(projectRoot string, now time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t := now.Unix()
+	for path := range s.terms["project:"+projectRoot] {
+		p := s.data.Packages[path]
+		if p.Crawl == 0 || t < p.Crawl {
+			p.Crawl = t
+		}
+		next := t + 86400
+		if p.Kind == "p" {
+			next = t + 7200
+		}
+		if p.Queue == 0 || next < p.Queue {
+			p.Queue = next
+		}
+		s.changed(packagesBucket, path)
+	}
+	return nil
+}
+
+func (s *fileStore) CrawlQueue() ([]*Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var result []*Record
+	for _, p := range s.data.Packages {
+		if p.Queue != 0 {
+			result = append(result, &Record{Path: p.Path, Synopsis: p.Synopsis, Kind: p.Kind, Score: p.Score})
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Score != result[j].Score {
+			return result[i].Score > result[j].Score
+		}
+		return result[i].Path < result[j].Path
+	})
+	return result, nil
+}
+
+func (s *fileStore) DueCrawls(now time.Time, n int) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var due []*filePackage
+	for _, p := range s.data.Packages {
+		if p.Queue != 0 && p.Queue <= now.Unix() {
+			due = append(due, p)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool {
+		if due[i].Queue != due[j].Queue {
+			return due[i].Queue < due[j].Queue
+		}
+		return due[i].Path < due[j].Path
+	})
+	var paths []string
+	for _, p := range due {
+		if len(paths) >= n {
+			break
+		}
+		paths = append(paths, p.Path)
+	}
+	return paths, nil
+}
+
+func (s *fileStore) GetCrawlState(path string) (*CrawlState, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st := s.data.Crawl[path]
+	if st == nil {
+		return nil, nil
+	}
+	stCopy := *st
+	return &stCopy, nil
+}
+
+func (s *fileStore) PutCrawlState(path string, st *CrawlState) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stCopy := *st
+	s.data.Crawl[path] = &stCopy
+	s.changed(crawlBucket, path)
+	return nil
+}
+
+func (s *fileStore) GetCrawlFailure(path string) (*CrawlFailure, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f := s.data.Failures[path]
+	if f == nil {
+		return nil, nil
+	}
+	fCopy := *f
+	return &fCopy, nil
+}
+
+func (s *fileStore) PutCrawlFailure(f *CrawlFailure) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fCopy := *f
+	s.data.Failures[f.Path] = &fCopy
+	s.changed(failuresBucket, f.Path)
+	return nil
+}
+
+func (s *fileStore) DeleteCrawlFailure(path string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.data.Failures[path] != nil {
+		delete(s.data.Failures, path)
+		s.changed(failuresBucket, path)
+	}
+	return nil
+}
+
+func (s *fileStore) CrawlFailures() ([]*CrawlFailure, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	failures := make([]*CrawlFailure, 0, len(s.data.Failures))
+	for _, f := range s.data.Failures {
+		fCopy := *f
+		failures = append(failures, &fCopy)
+	}
+	return failures, nil
+}
+
+func (s *fileStore) Block(root string) ([]string, error) {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	ids, packages, history := s.block(root)
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		for _, path := range packages {
+			if err := deletePackageValues(tx, path); err != nil {
+				return err
+			}
+		}
+		for _, path := range history {
+			if err := deleteValues(tx.Bucket([]byte(versionsBucket)), path); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return ids, err
+}
+
+// block blocks the root and deletes the records under the root. block
+// returns the ids and the paths of the deleted packages and the paths of the
+// deleted histories.
+func (s *fileStore) block(root string) (ids, packages, history []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data.Block[root] = true
+	s.changed(blockBucket, root)
+	for path, p := range s.data.Packages {
+		if isUnder(path, root) {
+			ids = append(ids, p.ID)
+			packages = append(packages, path)
+			s.deleteDoc(path)
+		}
+	}
+	for path := range s.data.History {
+		if isUnder(path, root) {
+			history = append(history, path)
+			delete(s.data.History, path)
+			s.changed(historyBucket, path)
+		}
+	}
+	for path := range s.data.Crawl {
+		if isUnder(path, root) {
+			delete(s.data.Crawl, path)
+			s.changed(crawlBucket, path)
+		}
+	}
+	for path := range s.data.Failures {
+		if isUnder(path, root) {
+			delete(s.data.Failures, path)
+			s.changed(failuresBucket, path)
+		}
+	}
+	for path := range s.data.NewCrawl {
+		if isUnder(path, root) {
+			delete(s.data.NewCrawl, path)
+			s.changed(newCrawlBucket, path)
+		}
+	}
+	sort.Strings(ids)
+	return ids, packages, history
+}
+
+func (s *fileStore) IsBlocked(path string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	prefix := ""
+	for _, elem := range strings.Split(path, "/") {
+		if elem == "" {
+			continue
+		}
+		prefix += elem
+		if s.data.Block[prefix] {
+			return true, nil
+		}
+		prefix += "/"
+	}
+	return false, nil
+}
+
+func (s *fileStore) IncrementPopularScore(path string, delta, scaledTime float64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.data.Packages[path] == nil {
+		return nil
+	}
+	f := math.Exp(scaledTime - s.data.Popular0)
+	s.data.Popular[path] += delta * f
+	if f > 10 {
+		s.data.Popular0 = scaledTime
+		for p, score := range s.data.Popular {
+			score /= f
+			if score <= 0.05 {
+				delete(s.data.Popular, p)
+			} else {
+				s.data.Popular[p] = score
+			}
+			s.changed(popularBucket, p)
+		}
+	}
+	s.changed(popularBucket, path)
+	return nil
+}
+
+func (s *fileStore) Popular(count int) ([]*Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	result := make([]*Record, 0, len(s.data.Popular))
+	for path, score := range s.data.Popular {
+		p := s.data.Packages[path]
+		result = append(result, &Record{Path: p.Path, Synopsis: p.Synopsis, Kind: p.Kind, Score: score})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Score != result[j].Score {
+			return result[i].Score > result[j].Score
+		}
+		return result[i].Path < result[j].Path
+	})
+	if count > 0 && count < len(result) {
+		result = result[:count]
+	}
+	return result, nil
+}
+
+func (s *fileStore) PutImportRanks(ranks map[string]float64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for path := range s.data.Ranks {
+		if _, ok := ranks[path]; !ok {
+			delete(s.data.Ranks, path)
+			s.changed(ranksBucket, path)
+		}
+	}
+	for path, rank := range ranks {
+		if s.data.Packages[path] != nil && s.data.Ranks[path] != rank {
+			s.data.Ranks[path] = rank
+			s.changed(ranksBucket, path)
+		}
+	}
+	return nil
+}
+
+func (s *fileStore) ImportRanks(paths []string) ([]float64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ranks := make([]float64, len(paths))
+	for i, path := range paths {
+		ranks[i] = s.data.Ranks[path]
+	}
+	return ranks, nil
+}
+
+func (s *fileStore) TopImportRanks(count int) ([]*Record, error) {
+	if count <= 0 {
+		return nil, nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var result []*Record
+	for path, rank := range s.data.Ranks {
+		if rank > 0 {
+			p := s.data.Packages[path]
+			result = append(result, &Record{Path: p.Path, Synopsis: p.Synopsis, Kind: p.Kind, Score: rank})
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Score != result[j].Score {
+			return result[i].Score > result[j].Score
+		}
+		return result[i].Path < result[j].Path
+	})
+	if count < len(result) {
+		result = result[:count]
+	}
+	return result, nil
+}
+
+func (s *fileStore) PopularScores(paths []string) ([]float64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	scores := make([]float64, len(paths))
+	for i, path := range paths {
+		scores[i] = s.data.Popular[path]
+	}
+	return scores, nil
+}
+
+func (s *fileStore) IncrementCounter(key string, delta, scaledTime float64, expiration time.Duration) (float64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	n := delta
+	if c := s.data.Counters[key]; c != nil && now.Before(c.Expires) {
+		n += c.N * math.Exp(c.T-scaledTime)
+	}
+	s.data.Counters[key] = &fileCounter{N: n, T: scaledTime, Expires: now.Add(expiration)}
+	s.changed(countersBucket, key)
+	return n, nil
+}
+
+func (s *fileStore) PutBlob(key string, p []byte) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket([]byte(blobsBucket)).Put([]byte(key), p)
+	})
+}
+
+func (s *fileStore) GetBlob(key string) ([]byte, error) {
+	return s.getValue(blobsBucket, []byte(key))
+}
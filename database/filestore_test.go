@@ -0,0 +1,699 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file or at
+// https://developers.google.com/open-source/licenses/bsd.
+
+package database
+
+import (
+	"context"
+	"encoding/base64"
+	"io/ioutil"
+	"math"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/golang/gddo/doc"
+)
+
+func newFileDB(t *testing.T) (db *Database, name string, cleanup func()) {
+	dir, err := ioutil.TempDir("", "gddo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	name = filepath.Join(dir, "gddo.db")
+	store, err := OpenFileStore(name)
+	if err != nil {
+		os.RemoveAll(dir)
+		t.Fatal(err)
+	}
+	return &Database{Store: store}, name, func() {
+		store.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+func TestFileStorePutGet(t *testing.T) {
+	ctx := context.Background()
+	var nextCrawl = time.Unix(time.Now().Add(time.Hour).Unix(), 0).UTC()
+
+	db, _, cleanup := newFileDB(t)
+	defer cleanup()
+	pdoc := &doc.Package{
+		ImportPath:  "github.com/user/repo/foo/bar",
+		Name:        "bar",
+		Synopsis:    "hello",
+		ProjectRoot: "github.com/user/repo",
+		ProjectName: "foo",
+		Updated:     time.Now().Add(-time.Hour),
+		Imports:     []string{"C", "errors", "github.com/user/repo/foo/bar", "github.com/other/dep"},
+		Funcs:       []*doc.Func{{Name: "Hello", Decl: doc.Code{Text: "func Hello()"}}},
+	}
+	if err := db.Put(ctx, pdoc, nextCrawl, false); err != nil {
+		t.Errorf("db.Put() returned error %v", err)
+	}
+	if err := db.Put(ctx, pdoc, time.Time{}, false); err != nil {
+		t.Errorf("second db.Put() returned error %v", err)
+	}
+
+	actualPdoc, actualSubdirs, actualCrawl, err := db.Get(ctx, "github.com/user/repo/foo/bar")
+	if err != nil {
+		t.Fatalf("db.Get(.../foo/bar) returned %v", err)
+	}
+	if len(actualSubdirs) != 0 {
+		t.Errorf("db.Get(.../foo/bar) returned subdirs %v, want none", actualSubdirs)
+	}
+	if !cmp.Equal(actualPdoc, pdoc) {
+		t.Errorf("db.Get(.../foo/bar) returned doc %v, want %v", actualPdoc, pdoc)
+	}
+	if !nextCrawl.Equal(actualCrawl) {
+		t.Errorf("db.Get(.../foo/bar) returned crawl %v, want %v", actualCrawl, nextCrawl)
+	}
+
+	newCrawls, err := db.NewCrawls()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"github.com/other/dep", "github.com/user/repo"}; !cmp.Equal(newCrawls, want) {
+		t.Errorf("db.NewCrawls() = %v, want %v", newCrawls, want)
+	}
+
+	before := time.Now().Unix()
+	if err := db.BumpCrawl(pdoc.ProjectRoot); err != nil {
+		t.Errorf("db.BumpCrawl() returned %v", err)
+	}
+	after := time.Now().Unix()
+
+	_, _, actualCrawl, _ = db.Get(ctx, "github.com/user/repo/foo/bar")
+	if actualCrawl.Unix() < before || after < actualCrawl.Unix() {
+		t.Errorf("actualCrawl=%v, expect value between %v and %v", actualCrawl.Unix(), before, after)
+	}
+
+	actualPdoc, _, _, err = db.Get(ctx, "-")
+	if err != nil {
+		t.Fatalf("db.Get(-) returned %v", err)
+	}
+	if !cmp.Equal(actualPdoc, pdoc) {
+		t.Errorf("db.Get(-) returned doc %v, want %v", actualPdoc, pdoc)
+	}
+
+	actualPdoc, actualSubdirs, _, err = db.Get(ctx, "github.com/user/repo/foo")
+	if err != nil {
+		t.Fatalf("db.Get(.../foo) returned %v", err)
+	}
+	if actualPdoc != nil {
+		t.Errorf("db.Get(.../foo) returned doc %v, want %v", actualPdoc, nil)
+	}
+	expectedSubdirs := []Package{{Path: "github.com/user/repo/foo/bar", Synopsis: "hello"}}
+	if !cmp.Equal(actualSubdirs, expectedSubdirs) {
+		t.Errorf("db.Get(.../foo) returned subdirs %v, want %v", actualSubdirs, expectedSubdirs)
+	}
+	actualImporters, err := db.Importers("github.com/user/repo/foo/bar")
+	if err != nil {
+		t.Fatalf("db.Importers() returned error %v", err)
+	}
+	expectedImporters := []Package{{Path: "github.com/user/repo/foo/bar", Synopsis: "hello"}}
+	if !cmp.Equal(actualImporters, expectedImporters) {
+		t.Errorf("db.Importers() = %v, want %v", actualImporters, expectedImporters)
+	}
+	actualImports, err := db.Packages(pdoc.Imports)
+	if err != nil {
+		t.Fatalf("db.Imports() returned error %v", err)
+	}
+	for i := range actualImports {
+		if actualImports[i].Path == "C" {
+			actualImports[i].Synopsis = ""
+		}
+	}
+	expectedImports := []Package{
+		{Path: "C", Synopsis: ""},
+		{Path: "errors", Synopsis: ""},
+		{Path: "github.com/other/dep", Synopsis: ""},
+		{Path: "github.com/user/repo/foo/bar", Synopsis: "hello"},
+	}
+	if !cmp.Equal(actualImports, expectedImports) {
+		t.Errorf("db.Imports() = %v, want %v", actualImports, expectedImports)
+	}
+	importerCount, _ := db.ImporterCount("github.com/user/repo/foo/bar")
+	if importerCount != 1 {
+		t.Errorf("db.ImporterCount() = %d, want %d", importerCount, 1)
+	}
+	nodes, edges, err := db.ImportGraph(pdoc, HideStandardAll)
+	if err != nil {
+		t.Fatalf("db.ImportGraph() returned error %v", err)
+	}
+	sort.Slice(edges, func(i, j int) bool {
+		return edges[i][0] < edges[j][0] || edges[i][0] == edges[j][0] && edges[i][1] < edges[j][1]
+	})
+	if want := [][2]int{{0, 1}, {0, 2}, {1, 1}, {1, 2}}; len(nodes) != 3 || !cmp.Equal(edges, want) {
+		t.Errorf("db.ImportGraph() = %v, %v; want 3 nodes and edges %v", nodes, edges, want)
+	}
+	pkgs, err := db.Query("bar")
+	if err != nil || len(pkgs) != 1 || pkgs[0].Path != pdoc.ImportPath {
+		t.Errorf("db.Query(bar) = %v, %v; want %s", pkgs, err, pdoc.ImportPath)
+	}
+
+	if err := db.Delete(ctx, "github.com/user/repo/foo/bar"); err != nil {
+		t.Errorf("db.Delete() returned error %v", err)
+	}
+	if ok, _ := db.Exists(pdoc.ImportPath); ok {
+		t.Errorf("db.Exists() = true after delete")
+	}
+	pkgs, err = db.Query("bar")
+	if err != nil || len(pkgs) != 0 {
+		t.Errorf("db.Query(bar) after delete = %v, %v; want none", pkgs, err)
+	}
+
+	if err := db.Put(ctx, pdoc, time.Time{}, false); err != nil {
+		t.Errorf("db.Put() returned error %v", err)
+	}
+
+	if err := db.Block("github.com/user/repo"); err != nil {
+		t.Errorf("db.Block() returned error %v", err)
+	}
+	if ok, _ := db.Exists(pdoc.ImportPath); ok {
+		t.Errorf("db.Exists() = true after block")
+	}
+	newCrawls, _ = db.NewCrawls()
+	if want := []string{"github.com/other/dep"}; !cmp.Equal(newCrawls, want) {
+		t.Errorf("db.NewCrawls() after block = %v, want %v", newCrawls, want)
+	}
+
+	blocked, err := db.IsBlocked("github.com/user/repo/foo/bar")
+	if !blocked || err != nil {
+		t.Errorf("db.IsBlocked(github.com/user/repo/foo/bar) returned %v, %v, want true, nil", blocked, err)
+	}
+
+	blocked, err = db.IsBlocked("github.com/foo/bar")
+	if blocked || err != nil {
+		t.Errorf("db.IsBlocked(github.com/foo/bar) returned %v, %v, want false, nil", blocked, err)
+	}
+}
+
+func TestFileStoreReopen(t *testing.T) {
+	ctx := context.Background()
+	db, name, cleanup := newFileDB(t)
+	defer cleanup()
+
+	pdoc := &doc.Package{
+		ImportPath:  "github.com/user/repo",
+		Name:        "repo",
+		Synopsis:    "hello",
+		ProjectRoot: "github.com/user/repo",
+		Imports:     []string{"github.com/user/repo"},
+	}
+	if err := db.Put(ctx, pdoc, time.Now(), false); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.PutGob("key", []string{"value"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	store, err := OpenFileStore(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	db = &Database{Store: store}
+	actualPdoc, _, _, err := db.Get(ctx, pdoc.ImportPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cmp.Equal(actualPdoc, pdoc) {
+		t.Errorf("db.Get() after reopen returned doc %v, want %v", actualPdoc, pdoc)
+	}
+	importers, err := db.Importers(pdoc.ImportPath)
+	if err != nil || len(importers) != 1 {
+		t.Errorf("db.Importers() after reopen = %v, %v; want one importer", importers, err)
+	}
+	var value []string
+	if err := db.GetGob("key", &value); err != nil || !cmp.Equal(value, []string{"value"}) {
+		t.Errorf("db.GetGob() after reopen = %v, %v; want [value]", value, err)
+	}
+}
+
+func TestFileStoreReopenDeletes(t *testing.T) {
+	ctx := context.Background()
+	db, name, cleanup := newFileDB(t)
+	defer cleanup()
+
+	for _, path := range []string{"github.com/user/a", "github.com/user/b", "github.com/spam/c"} {
+		pdoc := &doc.Package{ImportPath: path, Name: "p", ProjectRoot: path}
+		if err := db.Put(ctx, pdoc, time.Now(), false); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := db.Store.PutImportRanks(map[string]float64{"github.com/user/a": 2, "github.com/user/b": 1}); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	store, err := OpenFileStore(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	db = &Database{Store: store}
+	if err := db.Delete(ctx, "github.com/user/b"); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Block("github.com/spam"); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	store, err = OpenFileStore(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	for _, path := range []string{"github.com/user/b", "github.com/spam/c"} {
+		if ok, err := store.Exists(path); ok || err != nil {
+			t.Errorf("Exists(%q) after reopen = %v, %v; want false, nil", path, ok, err)
+		}
+	}
+	if blocked, err := store.IsBlocked("github.com/spam/c"); !blocked || err != nil {
+		t.Errorf("IsBlocked(github.com/spam/c) after reopen = %v, %v; want true, nil", blocked, err)
+	}
+	ranks, err := store.ImportRanks([]string{"github.com/user/a", "github.com/user/b"})
+	if err != nil || !cmp.Equal(ranks, []float64{2, 0}) {
+		t.Errorf("ImportRanks() after reopen = %v, %v; want [2 0]", ranks, err)
+	}
+}
+
+func TestFileStoreValues(t *testing.T) {
+	ctx := context.Background()
+	db, _, cleanup := newFileDB(t)
+	defer cleanup()
+	s := db.Store.(*fileStore)
+
+	// count returns the number of values of the path in the bucket.
+	count := func(bucket, path string) int {
+		n := 0
+		err := s.db.View(func(tx *bolt.Tx) error {
+			return tx.Bucket([]byte(bucket)).ForEach(func(k, v []byte) error {
+				if string(k) == path || strings.HasPrefix(string(k), path+"\x00") {
+					n++
+				}
+				return nil
+			})
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return n
+	}
+
+	pdoc := &doc.Package{
+		ImportPath:  "github.com/spam/a",
+		Name:        "a",
+		ProjectRoot: "github.com/spam/a",
+		Etag:        doc.PackageVersion + "-1",
+		Files:       []*doc.File{{Name: "a.go", Source: doc.Code{Text: "package a"}, HasSource: true}},
+	}
+	if err := db.Put(ctx, pdoc, time.Time{}, false); err != nil {
+		t.Fatal(err)
+	}
+
+	// The values are in the file before the records are flushed.
+	for _, bucket := range []string{docsBucket, sourcesBucket, versionsBucket} {
+		if n := count(bucket, pdoc.ImportPath); n != 1 {
+			t.Errorf("%d values in the %s bucket after Put, want 1", n, bucket)
+		}
+	}
+
+	if err := db.Delete(ctx, pdoc.ImportPath); err != nil {
+		t.Fatal(err)
+	}
+	for _, bucket := range []string{docsBucket, sourcesBucket} {
+		if n := count(bucket, pdoc.ImportPath); n != 0 {
+			t.Errorf("%d values in the %s bucket after Delete, want 0", n, bucket)
+		}
+	}
+	if n := count(versionsBucket, pdoc.ImportPath); n != 1 {
+		t.Errorf("%d versions after Delete, want 1", n)
+	}
+
+	if err := db.Block("github.com/spam"); err != nil {
+		t.Fatal(err)
+	}
+	if n := count(versionsBucket, pdoc.ImportPath); n != 0 {
+		t.Errorf("%d versions after Block, want 0", n)
+	}
+}
+
+func TestFileStoreLocked(t *testing.T) {
+	_, name, cleanup := newFileDB(t)
+	defer cleanup()
+	if store, err := OpenFileStore(name); err == nil {
+		store.Close()
+		t.Errorf("OpenFileStore(%q) of an open store returned nil error", name)
+	}
+}
+
+func TestFileStoreSources(t *testing.T) {
+	ctx := context.Background()
+	db, _, cleanup := newFileDB(t)
+	defer cleanup()
+
+	src := doc.Code{
+		Text:        "package repo\n\nvar V int\n",
+		Annotations: []doc.Annotation{{Kind: doc.LinkAnnotation, Pos: 19, End: 20, PathIndex: 0}},
+		Paths:       []string{"github.com/user/repo"},
+	}
+	pdoc := &doc.Package{
+		ImportPath:  "github.com/user/repo",
+		Name:        "repo",
+		ProjectRoot: "github.com/user/repo",
+		Files: []*doc.File{
+			{Name: "repo.go", Source: src, HasSource: true},
+			{Name: "big.go"},
+		},
+	}
+	if err := db.Put(ctx, pdoc, time.Time{}, false); err != nil {
+		t.Fatal(err)
+	}
+
+	got, _, _, err := db.Get(ctx, pdoc.ImportPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*doc.File{{Name: "repo.go", HasSource: true}, {Name: "big.go"}}
+	if diff := cmp.Diff(want, got.Files); diff != "" {
+		t.Errorf("db.Get() files differ (-want +got):\n%s", diff)
+	}
+	if gotSrc, err := db.Source(pdoc.ImportPath, "repo.go"); err != nil || !cmp.Equal(gotSrc, &src) {
+		t.Errorf("db.Source(%q, %q) = %v, %v; want %v", pdoc.ImportPath, "repo.go", gotSrc, err, &src)
+	}
+	if gotSrc, err := db.Source(pdoc.ImportPath, "big.go"); gotSrc != nil || err != nil {
+		t.Errorf("db.Source(%q, %q) = %v, %v; want nil, nil", pdoc.ImportPath, "big.go", gotSrc, err)
+	}
+
+	// A new document replaces the sources.
+	pdoc.Files = pdoc.Files[1:]
+	if err := db.Put(ctx, pdoc, time.Time{}, false); err != nil {
+		t.Fatal(err)
+	}
+	if gotSrc, err := db.Source(pdoc.ImportPath, "repo.go"); gotSrc != nil || err != nil {
+		t.Errorf("db.Source(%q, %q) after Put = %v, %v; want nil, nil", pdoc.ImportPath, "repo.go", gotSrc, err)
+	}
+}
+
+func TestFileStoreHistory(t *testing.T) {
+	ctx := context.Background()
+	db, _, cleanup := newFileDB(t)
+	defer cleanup()
+
+	pdoc := &doc.Package{
+		ImportPath:  "github.com/user/repo",
+		Name:        "repo",
+		ProjectRoot: "github.com/user/repo",
+		Files:       []*doc.File{{Name: "repo.go", Source: doc.Code{Text: "package repo"}, HasSource: true}},
+	}
+	for i := 0; i < maxHistory+2; i++ {
+		pdoc.Synopsis = "version " + strconv.Itoa(i)
+		pdoc.Etag = doc.PackageVersion + "-" + strconv.Itoa(i)
+		if err := db.Put(ctx, pdoc, time.Time{}, false); err != nil {
+			t.Fatal(err)
+		}
+	}
+	versions, err := db.Versions(pdoc.ImportPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var keys []string
+	for _, v := range versions {
+		keys = append(keys, v.Key)
+	}
+	var want []string
+	for i := maxHistory + 1; i >= 2; i-- {
+		want = append(want, strconv.Itoa(i))
+	}
+	if !cmp.Equal(keys, want) {
+		t.Errorf("db.Versions() returned keys %v, want %v", keys, want)
+	}
+
+	// A crawl without a new revision does not add a version.
+	pdoc.Synopsis = "recrawled"
+	if err := db.Put(ctx, pdoc, time.Time{}, false); err != nil {
+		t.Fatal(err)
+	}
+	again, err := db.Versions(pdoc.ImportPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cmp.Equal(again, versions) {
+		t.Errorf("db.Versions() after a crawl of the same revision = %v, want %v", again, versions)
+	}
+
+	old, err := db.GetVersion(pdoc.ImportPath, "3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if old == nil || old.Synopsis != "version 3" || old.Files[0].Source.Text != "" || old.Files[0].HasSource {
+		t.Errorf("db.GetVersion(%q, %q) = %+v, want version 3 without the file sources", pdoc.ImportPath, "3", old)
+	}
+	if old, err := db.GetVersion(pdoc.ImportPath, "0"); old != nil || err != nil {
+		t.Errorf("db.GetVersion(%q, %q) = %v, %v; want nil, nil", pdoc.ImportPath, "0", old, err)
+	}
+
+	// Versions older than since are removed.
+	now := time.Now()
+	if err := db.Store.PutVersion(pdoc.ImportPath, &VersionRecord{Key: "new", Updated: now.Add(time.Hour)}, maxHistory, now.Add(time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	if versions, err := db.Versions(pdoc.ImportPath); err != nil || len(versions) != 1 || versions[0].Key != "new" {
+		t.Errorf("db.Versions() after PutVersion with since = %v, %v; want [new]", versions, err)
+	}
+
+	if err := db.Block("github.com/user"); err != nil {
+		t.Fatal(err)
+	}
+	if versions, err := db.Versions(pdoc.ImportPath); err != nil || versions != nil {
+		t.Errorf("db.Versions() after Block = %v, %v; want nil, nil", versions, err)
+	}
+}
+
+func TestFileStoreProject(t *testing.T) {
+	ctx := context.Background()
+	db, _, cleanup := newFileDB(t)
+	defer cleanup()
+
+	for _, pdoc := range []*doc.Package{
+		{ImportPath: "C", Name: "C"},
+		{ImportPath: "errors", Name: "errors", Synopsis: "Package errors implements functions to manipulate errors.",
+			Coverage: &doc.Coverage{Funcs: doc.CoverageCount{Total: 2, Documented: 1}}},
+	} {
+		if err := db.Put(ctx, pdoc, time.Time{}, false); err != nil {
+			t.Fatal(err)
+		}
+	}
+	pkgs, err := db.Project("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Package{
+		{Path: "C", Synopsis: "Package C is a \"pseudo-package\" used to access the C namespace from a cgo source file."},
+		{Path: "errors", Synopsis: "Package errors implements functions to manipulate errors.", Coverage: &doc.CoverageCount{Total: 2, Documented: 1}},
+	}
+	if diff := cmp.Diff(want, pkgs); diff != "" {
+		t.Errorf("db.Project() differs (-want +got):\n%s", diff)
+	}
+}
+
+func TestFileStoreLargeVersion(t *testing.T) {
+	ctx := context.Background()
+	db, _, cleanup := newFileDB(t)
+	defer cleanup()
+
+	// The documentation of the types does not compress, so the types are
+	// stored separately.
+	r := rand.New(rand.NewSource(1))
+	pdoc := &doc.Package{
+		ImportPath:  "github.com/user/repo",
+		Name:        "repo",
+		ProjectRoot: "github.com/user/repo",
+		Etag:        doc.PackageVersion + "-1",
+		Funcs:       []*doc.Func{{Name: "F", Decl: doc.Code{Text: "func F()"}}},
+	}
+	for i := 0; i < 20; i++ {
+		b := make([]byte, 75000)
+		r.Read(b)
+		pdoc.Types = append(pdoc.Types, &doc.Type{
+			Name: "T" + strconv.Itoa(i),
+			Decl: doc.Code{Text: "type T" + strconv.Itoa(i) + " int"},
+			Doc:  base64.StdEncoding.EncodeToString(b),
+		})
+	}
+	if err := db.Put(ctx, pdoc, time.Time{}, false); err != nil {
+		t.Fatal(err)
+	}
+
+	got, _, _, err := db.Get(ctx, pdoc.ImportPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Types) == 0 || !got.Types[0].Stub {
+		t.Fatalf("db.Get() returned types %v, want stubs", got.Types)
+	}
+	if err := db.LoadTypes(got); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(pdoc.Types, got.Types); diff != "" {
+		t.Errorf("db.LoadTypes() types differ (-want +got):\n%s", diff)
+	}
+
+	got, err = db.GetVersion(pdoc.ImportPath, "1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got == nil {
+		t.Fatalf("db.GetVersion(%q, %q) = nil", pdoc.ImportPath, "1")
+	}
+	if got.Truncated || len(got.Funcs) != 1 {
+		t.Errorf("db.GetVersion() returned a truncated document")
+	}
+	if diff := cmp.Diff(pdoc.Types, got.Types); diff != "" {
+		t.Errorf("db.GetVersion() types differ (-want +got):\n%s", diff)
+	}
+}
+
+func TestFileStoreTransitiveImporters(t *testing.T) {
+	ctx := context.Background()
+	db, _, cleanup := newFileDB(t)
+	defer cleanup()
+
+	imports := map[string][]string{
+		"example.com/a": nil,
+		"example.com/b": {"example.com/a", "example.com/c"},
+		"example.com/c": {"example.com/b"},
+		"example.com/d": {"example.com/a", "example.com/c"},
+		"example.com/e": {"example.com/c"},
+	}
+	for path, imports := range imports {
+		pdoc := &doc.Package{ImportPath: path, Name: filepath.Base(path), Imports: imports}
+		if err := db.Put(ctx, pdoc, time.Time{}, false); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	pkg := func(path string) Package { return Package{Path: path} }
+	want := []Importer{
+		{Package: pkg("example.com/b"), Depth: 1},
+		{Package: pkg("example.com/d"), Depth: 1},
+		{Package: pkg("example.com/c"), Depth: 2, Via: []string{"example.com/b"}},
+		{Package: pkg("example.com/e"), Depth: 3, Via: []string{"example.com/c", "example.com/b"}},
+	}
+	for depth := 0; depth <= 4; depth++ {
+		got, truncated, err := db.TransitiveImporters("example.com/a", depth, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var w []Importer
+		for _, i := range want {
+			if i.Depth <= depth {
+				w = append(w, i)
+			}
+		}
+		if diff := cmp.Diff(w, got); diff != "" || truncated {
+			t.Errorf("db.TransitiveImporters(%q, %d, 10) differs (-want +got):\n%s\ntruncated: %v", "example.com/a", depth, diff, truncated)
+		}
+	}
+
+	for max := 0; max <= len(want); max++ {
+		got, truncated, err := db.TransitiveImporters("example.com/a", 4, max)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w := append([]Importer(nil), want[:max]...)
+		if diff := cmp.Diff(w, got); diff != "" || truncated != (max < len(want)) {
+			t.Errorf("db.TransitiveImporters(%q, 4, %d) differs (-want +got):\n%s\ntruncated: %v", "example.com/a", max, diff, truncated)
+		}
+	}
+}
+
+func TestFileStorePopular(t *testing.T) {
+	ctx := context.Background()
+	db, _, cleanup := newFileDB(t)
+	defer cleanup()
+
+	// Add scores for packages. On each iteration, add half-life to time and
+	// divide the score by two. All packages should have the same score.
+
+	now := time.Now()
+	score := float64(4048)
+	for id := 12; id >= 0; id-- {
+		path := "github.com/user/repo/p" + strconv.Itoa(id)
+		if err := db.Put(ctx, &doc.Package{ImportPath: path, Name: "p"}, time.Time{}, false); err != nil {
+			t.Fatal(err)
+		}
+		err := db.incrementPopularScoreInternal(path, score, now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		now = now.Add(popularHalfLife)
+		score /= 2
+	}
+
+	pkgs, err := db.PopularWithScores()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pkgs) != 13 {
+		t.Fatalf("Expected 13 packages, got %d", len(pkgs))
+	}
+
+	// Check for equal scores.
+	for i := 1; i < len(pkgs); i++ {
+		if math.Abs(pkgs[0].Score-pkgs[i].Score)/pkgs[0].Score > epsilon {
+			t.Errorf("Bad score, score[0]=%g, score[%d]=%g", pkgs[0].Score, i, pkgs[i].Score)
+		}
+	}
+
+	pkgs, err = db.Popular(5)
+	if err != nil || len(pkgs) != 5 {
+		t.Errorf("db.Popular(5) returned %d packages, %v; want 5", len(pkgs), err)
+	}
+}
+
+func TestFileStoreCounter(t *testing.T) {
+	db, _, cleanup := newFileDB(t)
+	defer cleanup()
+
+	const key = "127.0.0.1"
+
+	now := time.Now()
+	for i, want := range []float64{1, 2} {
+		n, err := db.incrementCounterInternal(key, 1, now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if math.Abs(n-want)/want > epsilon {
+			t.Errorf("%d: got n=%g, want %g", i+1, n, want)
+		}
+	}
+	now = now.Add(counterHalflife)
+	n, err := db.incrementCounterInternal(key, 1, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(n-2.0)/2.0 > epsilon {
+		t.Errorf("3: got n=%g, want 2", n)
+	}
+}
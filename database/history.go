@@ -0,0 +1,94 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file or at
+// https://developers.google.com/open-source/licenses/bsd.
+
+package database
+
+import (
+	"strings"
+	"time"
+
+	"github.com/golang/gddo/doc"
+)
+
+const (
+	// maxHistory is the maximum number of versions in the history of a
+	// package.
+	maxHistory = 10
+
+	// maxHistoryAge is the maximum age of a version in the history of a
+	// package.
+	maxHistoryAge = 90 * 24 * time.Hour
+)
+
+// A Version is a version of a package document in the history of the
+// package.
+type Version struct {
+	Key     string    `json:"key"`
+	Updated time.Time `json:"updated"`
+}
+
+// historyKey returns the key of the version of pdoc in the history: the
+// revision of the source in the etag or, if the etag does not have a
+// revision, the update time.
+func historyKey(pdoc *doc.Package) string {
+	if i := strings.IndexByte(pdoc.Etag, '-'); i >= 0 && i+1 < len(pdoc.Etag) {
+		return pdoc.Etag[i+1:]
+	}
+	return pdoc.Updated.UTC().Format(time.RFC3339)
+}
+
+// putVersion adds pdoc to the history of the package unless the newest
+// version has the same key, that is the package was crawled again without a
+// change of the revision. The file sources are dropped and the types of
+// large documents are stored separately as in Put.
+func (db *Database) putVersion(pdoc *doc.Package, now time.Time) error {
+	key := historyKey(pdoc)
+	records, err := db.Store.Versions(pdoc.ImportPath)
+	if err != nil {
+		return err
+	}
+	if len(records) > 0 && records[0].Key == key {
+		return nil
+	}
+	pdoc, p, typePages, err := encodeDocPages(dropFileSources(pdoc))
+	if err != nil {
+		return err
+	}
+	v := &VersionRecord{Key: key, Updated: now, Doc: p, Types: typePages}
+	return db.Store.PutVersion(pdoc.ImportPath, v, maxHistory, now.Add(-maxHistoryAge))
+}
+
+// Versions returns the versions in the history of the package with the
+// given path, newest first.
+func (db *Database) Versions(path string) ([]Version, error) {
+	records, err := db.Store.Versions(path)
+	if err != nil {
+		return nil, err
+	}
+	var versions []Version
+	for _, r := range records {
+		versions = append(versions, Version{Key: r.Key, Updated: r.Updated})
+	}
+	return versions, nil
+}
+
+// GetVersion returns the version of the package document with the given
+// key in the history of the package, with the types stored separately
+// loaded. GetVersion returns nil if there is no such version.
+func (db *Database) GetVersion(path, key string) (*doc.Package, error) {
+	r, err := db.Store.GetVersion(path, key)
+	if r == nil || err != nil {
+		return nil, err
+	}
+	pdoc, err := decodeDoc(r.Doc, false)
+	if err != nil {
+		return nil, err
+	}
+	if err := loadTypePages(pdoc, r.Types); err != nil {
+		return nil, err
+	}
+	return pdoc, nil
+}
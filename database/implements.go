@@ -0,0 +1,274 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file or at
+// https://developers.google.com/open-source/licenses/bsd.
+
+package database
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/golang/gddo/doc"
+	"github.com/golang/gddo/gosrc"
+)
+
+// Implementation is a type in an importing package that implements an
+// interface.
+type Implementation struct {
+	Interface string `json:"interface"` // Name of the interface.
+	Path      string `json:"path"`      // Import path of the implementing package.
+	Type      string `json:"type"`      // Name of the implementing type.
+	Ptr       bool   `json:"ptr,omitempty"`
+}
+
+var interfacePat = regexp.MustCompile(`^type [^ ]+ interface`)
+
+// A method set term records the method set of a named type of a package
+// computed by the doc package. The term has the form
+// "kind\tName\tsig\tsig..." where kind is "i" for interfaces and "t" for
+// other types. Method signatures do not contain tabs.
+
+// methodSet is a decoded method set term.
+type methodSet struct {
+	name  string
+	iface bool
+	sigs  []string
+}
+
+// methodSetTerms returns the method set terms of the types of pdoc with
+// methods.
+func methodSetTerms(pdoc *doc.Package) []string {
+	var terms []string
+	for _, t := range pdoc.Types {
+		if len(t.MethodSigs) == 0 {
+			continue
+		}
+		kind := "t"
+		if interfacePat.MatchString(t.Decl.Text) {
+			kind = "i"
+		}
+		terms = append(terms, kind+"\t"+t.Name+"\t"+strings.Join(t.MethodSigs, "\t"))
+	}
+	return terms
+}
+
+func parseMethodSets(terms []string) []methodSet {
+	sets := make([]methodSet, 0, len(terms))
+	for _, term := range terms {
+		parts := strings.Split(term, "\t")
+		if len(parts) < 3 {
+			continue
+		}
+		sets = append(sets, methodSet{name: parts[1], iface: parts[0] == "i", sigs: parts[2:]})
+	}
+	return sets
+}
+
+// interfaceTerms returns the terms of the interfaces in the method set
+// terms.
+func interfaceTerms(terms []string) []string {
+	var ifaces []string
+	for _, term := range terms {
+		if strings.HasPrefix(term, "i\t") {
+			ifaces = append(ifaces, term)
+		}
+	}
+	sort.Strings(ifaces)
+	return ifaces
+}
+
+// implements reports whether type t implements interface iface. If ptr is
+// true, only *t implements the interface.
+func implements(t, iface methodSet) (ok, ptr bool) {
+	if !iface.iface || t.iface || len(iface.sigs) == 0 {
+		return false, false
+	}
+	sigs := make(map[string]bool, len(t.sigs))
+	for _, sig := range t.sigs {
+		sigs[sig] = true
+	}
+	for _, sig := range iface.sigs {
+		switch {
+		case sigs[sig]:
+		case sigs["*"+sig]:
+			ptr = true
+		default:
+			return false, false
+		}
+	}
+	return true, ptr
+}
+
+// implementationTerms returns the implementations of the interfaces in the
+// method sets of imports by the types in the method sets of the package
+// with the given path. Each term has the form "importPath#Interface#Type"
+// where Type is prefixed by "*" if only the pointer type implements the
+// interface.
+func implementationTerms(path string, sets []string, imports []string, importSets [][]string) []string {
+	types := parseMethodSets(sets)
+	var terms []string
+	for i, p := range imports {
+		if p == path {
+			continue
+		}
+		for _, iface := range parseMethodSets(importSets[i]) {
+			for _, t := range types {
+				ok, ptr := implements(t, iface)
+				if !ok {
+					continue
+				}
+				name := t.name
+				if ptr {
+					name = "*" + name
+				}
+				terms = append(terms, p+"#"+iface.name+"#"+name)
+			}
+		}
+	}
+	return terms
+}
+
+// implementationImports returns the imports of a package that may declare
+// interfaces implemented by the package.
+func implementationImports(path string, imports []string) []string {
+	var result []string
+	for _, p := range imports {
+		if isStandardPackage(p) || !gosrc.IsValidRemotePath(p) || p == path {
+			continue
+		}
+		result = append(result, p)
+	}
+	return result
+}
+
+// updateImplementations replaces the implementation terms of the package
+// with the given path, method set terms and imports.
+func (db *Database) updateImplementations(path string, sets []string, imports []string) error {
+	imports = implementationImports(path, imports)
+	var terms []string
+	if len(sets) > 0 && len(imports) > 0 {
+		importSets, err := db.Store.MethodSets(imports)
+		if err != nil {
+			return err
+		}
+		terms = implementationTerms(path, sets, imports, importSets)
+	}
+	return db.Store.PutImplementations(path, terms)
+}
+
+// maxImplementationUpdates is the maximum number of importers whose
+// implementation terms are updated when the interfaces of a package change.
+// The terms of other importers are updated when they are stored again.
+const maxImplementationUpdates = 1000
+
+// updateImplementationsIndex stores the method sets of pdoc and records
+// the types in pdoc that implement interfaces from the packages it
+// imports. If the interfaces of pdoc changed from the old method set terms,
+// the implementations by the importers of pdoc are updated too.
+func (db *Database) updateImplementationsIndex(pdoc *doc.Package, oldSets []string) error {
+	sets := methodSetTerms(pdoc)
+	if err := db.Store.PutMethodSets(pdoc.ImportPath, sets); err != nil {
+		return err
+	}
+	if err := db.updateImplementations(pdoc.ImportPath, sets, pdoc.Imports); err != nil {
+		return err
+	}
+
+	if equalStrings(interfaceTerms(oldSets), interfaceTerms(sets)) {
+		return nil
+	}
+	records, err := db.Store.TermPackages("import:" + pdoc.ImportPath)
+	if err != nil {
+		return err
+	}
+	var importers []string
+	for _, r := range records {
+		if r.Path != pdoc.ImportPath {
+			importers = append(importers, r.Path)
+		}
+	}
+	sort.Strings(importers)
+	if len(importers) > maxImplementationUpdates {
+		importers = importers[:maxImplementationUpdates]
+	}
+	if len(importers) == 0 {
+		return nil
+	}
+	importerSets, err := db.Store.MethodSets(importers)
+	if err != nil {
+		return err
+	}
+	packages, err := db.Store.Packages(importers)
+	if err != nil {
+		return err
+	}
+	for i, r := range packages {
+		if len(importerSets[i]) == 0 {
+			continue
+		}
+		var imports []string
+		for _, term := range r.Terms {
+			if strings.HasPrefix(term, "import:") {
+				imports = append(imports, term[len("import:"):])
+			}
+		}
+		if err := db.updateImplementations(r.Path, importerSets[i], imports); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+type byInterface []Implementation
+
+func (p byInterface) Len() int      { return len(p) }
+func (p byInterface) Swap(i, j int) { p[i], p[j] = p[j], p[i] }
+func (p byInterface) Less(i, j int) bool {
+	switch {
+	case p[i].Interface != p[j].Interface:
+		return p[i].Interface < p[j].Interface
+	case p[i].Path != p[j].Path:
+		return p[i].Path < p[j].Path
+	}
+	return p[i].Type < p[j].Type
+}
+
+// Implementations returns the known implementations of the interfaces
+// declared in the package with the given import path by types in packages
+// that import it.
+func (db *Database) Implementations(path string) ([]Implementation, error) {
+	members, err := db.Store.Implementations(path)
+	if err != nil {
+		return nil, err
+	}
+	impls := make([]Implementation, 0, len(members))
+	for _, m := range members {
+		parts := strings.SplitN(m, "#", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		impl := Implementation{Path: parts[0], Interface: parts[1], Type: parts[2]}
+		if strings.HasPrefix(impl.Type, "*") {
+			impl.Type = impl.Type[1:]
+			impl.Ptr = true
+		}
+		impls = append(impls, impl)
+	}
+	sort.Sort(byInterface(impls))
+	return impls, nil
+}
@@ -0,0 +1,145 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file or at
+// https://developers.google.com/open-source/licenses/bsd.
+
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/golang/gddo/doc"
+)
+
+var pluginInterface = methodSet{
+	name:  "Plugin",
+	iface: true,
+	sigs: []string{
+		"Name func() string",
+		"Run func(example.com/plugin.Context) error",
+	},
+}
+
+var implementsTests = []struct {
+	t       methodSet
+	ok, ptr bool
+}{
+	{methodSet{sigs: []string{
+		"Name func() string",
+		"Run func(example.com/plugin.Context) error",
+	}}, true, false},
+	{methodSet{sigs: []string{
+		"Close func() error",
+		"Name func() string",
+		"*Run func(example.com/plugin.Context) error",
+	}}, true, true},
+	{methodSet{sigs: []string{
+		"Name func() string",
+		"Run func(example.com/other.Context) error",
+	}}, false, false},
+	{methodSet{iface: true, sigs: pluginInterface.sigs}, false, false},
+	{methodSet{}, false, false},
+}
+
+func TestImplements(t *testing.T) {
+	for i, tt := range implementsTests {
+		ok, ptr := implements(tt.t, pluginInterface)
+		if ok != tt.ok || ptr != tt.ptr {
+			t.Errorf("%d: implements() = %v, %v, want %v, %v", i, ok, ptr, tt.ok, tt.ptr)
+		}
+	}
+	// A type does not implement an interface with no methods.
+	if ok, _ := implements(implementsTests[0].t, methodSet{iface: true}); ok {
+		t.Error("type implements the empty interface")
+	}
+}
+
+var methodSetTermsTests = []struct {
+	t    *doc.Type
+	want []string
+}{
+	{&doc.Type{
+		Name:       "Plugin",
+		Decl:       doc.Code{Text: "type Plugin interface {\n    Name() string\n}"},
+		MethodSigs: []string{"Name func() string"},
+	}, []string{"i\tPlugin\tName func() string"}},
+	{&doc.Type{
+		Name:       "T",
+		Decl:       doc.Code{Text: "type T struct{ a struct{ x, y int } }"},
+		MethodSigs: []string{"*Name func() string", "Pair func() struct{x int; y int}"},
+	}, []string{"t\tT\t*Name func() string\tPair func() struct{x int; y int}"}},
+	{&doc.Type{Name: "E", Decl: doc.Code{Text: "type E int"}}, nil},
+}
+
+func TestMethodSetTerms(t *testing.T) {
+	for _, tt := range methodSetTermsTests {
+		terms := methodSetTerms(&doc.Package{Types: []*doc.Type{tt.t}})
+		if diff := cmp.Diff(tt.want, terms); diff != "" {
+			t.Errorf("methodSetTerms(%s) differs (-want +got):\n%s", tt.t.Name, diff)
+		}
+		sets := parseMethodSets(terms)
+		if len(sets) != len(tt.want) || len(sets) > 0 && !cmp.Equal(sets[0].sigs, tt.t.MethodSigs) {
+			t.Errorf("parseMethodSets(methodSetTerms(%s)) = %+v", tt.t.Name, sets)
+		}
+	}
+}
+
+func TestFileStoreImplementations(t *testing.T) {
+	ctx := context.Background()
+	db, _, cleanup := newFileDB(t)
+	defer cleanup()
+
+	put := func(pdoc *doc.Package) {
+		t.Helper()
+		if err := db.Put(ctx, pdoc, time.Time{}, false); err != nil {
+			t.Fatal(err)
+		}
+	}
+	plugin := func(sigs ...string) *doc.Package {
+		return &doc.Package{
+			ImportPath: "example.com/plugin",
+			Name:       "plugin",
+			Types: []*doc.Type{{
+				Name:       "Plugin",
+				Decl:       doc.Code{Text: "type Plugin interface {\n    Run() error\n}"},
+				MethodSigs: sigs,
+			}},
+		}
+	}
+	check := func(want []Implementation) {
+		t.Helper()
+		impls, err := db.Implementations("example.com/plugin")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(want, impls); diff != "" {
+			t.Errorf("Implementations differs (-want +got):\n%s", diff)
+		}
+	}
+
+	put(plugin("Run func() error"))
+	put(&doc.Package{
+		ImportPath: "example.com/app",
+		Name:       "app",
+		Imports:    []string{"example.com/plugin", "fmt"},
+		Types: []*doc.Type{{
+			Name:       "P",
+			Decl:       doc.Code{Text: "type P struct{}"},
+			MethodSigs: []string{"*Run func() error", "String func() string"},
+		}},
+	})
+	want := []Implementation{{Interface: "Plugin", Path: "example.com/app", Type: "P", Ptr: true}}
+	check(want)
+
+	// A change of the interface updates the implementations by the
+	// importers.
+	put(plugin("Run func() error", "Stop func()"))
+	check([]Implementation{})
+	put(plugin("Run func() error"))
+	check(want)
+}
@@ -7,10 +7,12 @@
 package database
 
 import (
+	"math"
 	"path"
 	"regexp"
 	"strings"
 	"unicode"
+	"unicode/utf8"
 
 	"github.com/golang/gddo/doc"
 	"github.com/golang/gddo/gosrc"
@@ -56,9 +58,9 @@ func collectSynopsisTerms(terms map[string]bool, synopsis string) {
 
 	synopsis = httpPat.ReplaceAllLiteralString(synopsis, "")
 
-	fields := strings.FieldsFunc(synopsis, isTermSep)
-	for i := range fields {
-		fields[i] = strings.ToLower(fields[i])
+	fields := strings.FieldsFunc(synopsis, isIdentifierSep)
+	is := func(i int, s string) bool {
+		return i < len(fields) && strings.EqualFold(fields[i], s)
 	}
 
 	// Ignore boilerplate in the following common patterns:
@@ -71,31 +73,168 @@ func collectSynopsisTerms(terms map[string]bool, synopsis string) {
 
 	checkPackageVerb := false
 	switch {
-	case len(fields) >= 1 && fields[0] == "package":
+	case is(0, "package"):
 		fields = fields[1:]
 		checkPackageVerb = true
-	case len(fields) >= 1 && fields[0] == "command":
+	case is(0, "command"):
 		fields = fields[1:]
-	case len(fields) >= 3 && fields[0] == "the" && fields[2] == "package":
+	case is(0, "the") && is(2, "package"):
 		fields[2] = fields[1]
 		fields = fields[2:]
 		checkPackageVerb = true
-	case len(fields) >= 3 && fields[0] == "the" && fields[2] == "command":
+	case is(0, "the") && is(2, "command"):
 		fields[2] = fields[1]
 		fields = fields[2:]
 	}
 
-	if checkPackageVerb && len(fields) >= 2 &&
-		(fields[1] == "implements" || fields[1] == "provides" || fields[1] == "contains") {
+	if checkPackageVerb && (is(1, "implements") || is(1, "provides") || is(1, "contains")) {
 		fields[1] = fields[0]
 		fields = fields[1:]
 	}
 
 	for _, s := range fields {
-		if !stopWord[s] {
-			terms[term(s)] = true
+		for _, t := range identifierTerms(s) {
+			terms[t] = true
+		}
+	}
+}
+
+// isIdentifierSep is isTermSep for text with identifiers. Identifiers
+// are not split at underscores.
+func isIdentifierSep(r rune) bool {
+	return r != '_' && isTermSep(r)
+}
+
+// goAbbreviations maps abbreviations common in Go identifiers to the words
+// they abbreviate. The words are indexed with the abbreviations.
+var goAbbreviations = map[string]string{
+	"addr":   "address",
+	"arg":    "argument",
+	"args":   "arguments",
+	"auth":   "authentication",
+	"buf":    "buffer",
+	"cfg":    "configuration",
+	"cmd":    "command",
+	"conf":   "configuration",
+	"config": "configuration",
+	"conn":   "connection",
+	"ctx":    "context",
+	"db":     "database",
+	"dir":    "directory",
+	"env":    "environment",
+	"err":    "error",
+	"fmt":    "format",
+	"fn":     "function",
+	"func":   "function",
+	"impl":   "implementation",
+	"k8s":    "kubernetes",
+	"len":    "length",
+	"lib":    "library",
+	"msg":    "message",
+	"mux":    "multiplexer",
+	"num":    "number",
+	"pkg":    "package",
+	"ptr":    "pointer",
+	"req":    "request",
+	"resp":   "response",
+	"srv":    "server",
+	"str":    "string",
+	"tmpl":   "template",
+	"util":   "utility",
+	"val":    "value",
+}
+
+// identifierTerms returns the terms of a word that may be an identifier:
+// the term of the word as in parseQuery and, if the word is made of parts
+// split at case changes, underscores, dots and digits, the terms of the
+// parts. For example, HTTP2Server has the terms of http2server, http2,
+// http and server. Abbreviations in goAbbreviations also have the term of
+// the abbreviated word.
+func identifierTerms(w string) []string {
+	var terms []string
+	seen := make(map[string]bool)
+	add := func(s string) {
+		s = strings.ToLower(s)
+		if stopWord[s] {
+			return
+		}
+		for _, s := range []string{s, goAbbreviations[s]} {
+			if s == "" {
+				continue
+			}
+			if t := term(s); t != "" && !seen[t] {
+				seen[t] = true
+				terms = append(terms, t)
+			}
+		}
+	}
+
+	w = strings.TrimSuffix(w, ".")
+	add(w)
+	var parts []string
+	for _, s := range strings.FieldsFunc(w, func(r rune) bool { return r == '_' || r == '.' }) {
+		for _, word := range splitCamelCase(s) {
+			parts = append(parts, word)
+			if digits := splitDigits(word); len(digits) > 1 {
+				parts = append(parts, digits...)
+			}
+		}
+	}
+	if len(parts) > 1 {
+		for _, p := range parts {
+			if utf8.RuneCountInString(p) > 1 {
+				add(p)
+			}
+		}
+	}
+	return terms
+}
+
+// splitCamelCase splits s before an upper case letter following a lower
+// case letter or a digit, before the last letter of a sequence of upper case
+// letters followed by a lower case letter, and before a lower case letter
+// following a digit. Digits following letters are not split from the
+// letters, so that HTTP2Server is split into HTTP2 and Server.
+func splitCamelCase(s string) []string {
+	var words []string
+	runes := []rune(s)
+	start := 0
+	for i := 1; i < len(runes); i++ {
+		prev, r := runes[i-1], runes[i]
+		if unicode.IsUpper(r) && (unicode.IsLower(prev) || unicode.IsDigit(prev)) ||
+			unicode.IsUpper(r) && unicode.IsUpper(prev) && i+1 < len(runes) && unicode.IsLower(runes[i+1]) ||
+			unicode.IsLower(r) && unicode.IsDigit(prev) {
+			words = append(words, string(runes[start:i]))
+			start = i
 		}
 	}
+	return append(words, string(runes[start:]))
+}
+
+// splitDigits splits s at the boundaries between digits and other
+// characters.
+func splitDigits(s string) []string {
+	var parts []string
+	start := 0
+	prevDigit := false
+	for i, r := range s {
+		digit := unicode.IsDigit(r)
+		if i > 0 && digit != prevDigit {
+			parts = append(parts, s[start:i])
+			start = i
+		}
+		prevDigit = digit
+	}
+	return append(parts, s[start:])
+}
+
+// textTerms returns the terms of the words in s. See identifierTerms.
+func textTerms(s string) []string {
+	var terms []string
+	for _, w := range strings.FieldsFunc(s, isIdentifierSep) {
+		terms = append(terms, identifierTerms(w)...)
+	}
+	return terms
 }
 
 func termSlice(terms map[string]bool) []string {
@@ -127,6 +266,12 @@ func documentTerms(pdoc *doc.Package, score float64) []string {
 		}
 	}
 
+	// License
+
+	if pdoc.License != "" {
+		terms["license:"+strings.ToLower(pdoc.License)] = true
+	}
+
 	if score > 0 {
 
 		for _, term := range parseQuery(pdoc.ImportPath) {
@@ -151,6 +296,18 @@ func documentTerms(pdoc *doc.Package, score float64) []string {
 	return termSlice(terms)
 }
 
+// documentKind returns the kind of the package in the database: p for a
+// package, c for a command and d for a directory with no Go files.
+func documentKind(pdoc *doc.Package) string {
+	switch {
+	case pdoc.Name == "":
+		return "d"
+	case pdoc.IsCmd:
+		return "c"
+	}
+	return "p"
+}
+
 // vendorPat matches the path of a vendored package.
 var vendorPat = regexp.MustCompile(
 	// match directories used by tools to vendor packages.
@@ -158,6 +315,10 @@ var vendorPat = regexp.MustCompile(
 		// match a domain name.
 		`[^./]+\.[^/]+`)
 
+// maxScoredTests is the number of test functions above which more tests do
+// not improve the score of a package.
+const maxScoredTests = 20
+
 func documentScore(pdoc *doc.Package) float64 {
 	if pdoc.Name == "" ||
 		pdoc.Status != gosrc.Active ||
@@ -200,6 +361,12 @@ func documentScore(pdoc *doc.Package) float64 {
 			// Penalty for no documentation.
 			r *= 0.95
 		}
+		if pdoc.Coverage != nil {
+			// Penalty for undocumented declarations.
+			if sum := pdoc.Coverage.Sum(); sum.Total > 0 {
+				r *= 0.9 + 0.1*float64(sum.Documented)/float64(sum.Total)
+			}
+		}
 		if path.Base(pdoc.ImportPath) != pdoc.Name {
 			// Penalty for last element of path != package name.
 			r *= 0.9
@@ -219,6 +386,8 @@ func documentScore(pdoc *doc.Package) float64 {
 			}
 		}
 	}
+	// Penalty for few tests, benchmarks and fuzz targets.
+	r *= 0.95 + 0.05*math.Min(float64(len(pdoc.Tests)), maxScoredTests)/maxScoredTests
 	return r
 }
 
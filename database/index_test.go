@@ -7,6 +7,7 @@
 package database
 
 import (
+	"math"
 	"sort"
 	"testing"
 
@@ -78,6 +79,9 @@ var indexTests = []struct {
 		[]string{
 			"all:",
 			"5849", "cly", "defin", "dir", "github.com", "go",
+			// OAuth is split into O and Auth, and auth and dir are
+			// abbreviations of authentication and directory.
+			"au", "auth", "direct",
 			"import:bytes", "import:crypto/hmac", "import:crypto/sha1",
 			"import:encoding/base64", "import:encoding/binary", "import:errors",
 			"import:fmt", "import:io", "import:io/ioutil", "import:net/http",
@@ -200,3 +204,66 @@ func TestSynopsisTerms(t *testing.T) {
 		}
 	}
 }
+
+func TestDocumentScoreCoverage(t *testing.T) {
+	pdoc := &doc.Package{
+		ImportPath:  "github.com/user/repo/foo",
+		ProjectRoot: "github.com/user/repo",
+		Name:        "foo",
+		Doc:         "Package foo does things.",
+		Funcs:       []*doc.Func{{}},
+	}
+	full := documentScore(pdoc)
+	pdoc.Coverage = &doc.Coverage{Funcs: doc.CoverageCount{Total: 4, Documented: 4}}
+	if score := documentScore(pdoc); score != full {
+		t.Errorf("documentScore with full coverage = %v, want %v", score, full)
+	}
+	pdoc.Coverage = &doc.Coverage{Funcs: doc.CoverageCount{Total: 4, Documented: 2}}
+	if score, want := documentScore(pdoc), full*0.95; score != want {
+		t.Errorf("documentScore with half coverage = %v, want %v", score, want)
+	}
+}
+
+func TestDocumentScoreTests(t *testing.T) {
+	pdoc := &doc.Package{
+		ImportPath:  "github.com/user/repo/foo",
+		ProjectRoot: "github.com/user/repo",
+		Name:        "foo",
+		Doc:         "Package foo does things.",
+		Funcs:       []*doc.Func{{}},
+	}
+	none := documentScore(pdoc)
+	pdoc.Tests = []*doc.TestFunc{{Name: "TestFoo", Kind: "Test"}, {Name: "BenchmarkFoo", Kind: "Benchmark"}}
+	some := documentScore(pdoc)
+	for i := 0; i < 2*maxScoredTests; i++ {
+		pdoc.Tests = append(pdoc.Tests, &doc.TestFunc{Name: "TestFoo", Kind: "Test"})
+	}
+	many := documentScore(pdoc)
+	if !(none < some && some < many) {
+		t.Errorf("documentScore with 0, 2 and %d tests = %v, %v, %v; want increasing scores", len(pdoc.Tests), none, some, many)
+	}
+	if want := none / 0.95; math.Abs(many-want) > 1e-9 {
+		t.Errorf("documentScore with many tests = %v, want %v", many, want)
+	}
+}
+
+var identifierTermsTests = []struct {
+	word  string
+	terms []string
+}{
+	{"http2", []string{"http2", "http"}},
+	{"ReadAll", []string{term("readall"), term("read")}},
+	{"HTTP2Server", []string{term("http2server"), "http2", "http", term("server")}},
+	{"grpc_gateway", []string{term("grpc_gateway"), "grpc", term("gateway")}},
+	{"base64URL", []string{term("base64url"), term("base64"), term("base"), "64", "url"}},
+	{"ctx.", []string{"ctx", term("context")}},
+	{"the", nil},
+}
+
+func TestIdentifierTerms(t *testing.T) {
+	for _, tt := range identifierTermsTests {
+		if terms := identifierTerms(tt.word); !cmp.Equal(terms, tt.terms) {
+			t.Errorf("identifierTerms(%q) = %#v, want %#v", tt.word, terms, tt.terms)
+		}
+	}
+}
@@ -48,6 +48,10 @@ func (p *Package) Load(fields []search.Field, meta *search.DocumentMetadata) err
 			if v, ok := f.Value.(float64); ok {
 				p.Score = v
 			}
+		case "ImportRank":
+			if v, ok := f.Value.(float64); ok {
+				p.ImportRank = v
+			}
 		}
 	}
 	if p.Path == "" {
@@ -69,14 +73,15 @@ func (p *Package) Save() ([]search.Field, *search.DocumentMetadata, error) {
 		{Name: "Score", Value: p.Score},
 		{Name: "ImportCount", Value: float64(p.ImportCount)},
 		{Name: "Stars", Value: float64(p.Stars)},
+		{Name: "ImportRank", Value: p.ImportRank},
 	}
 	fork := fmt.Sprint(p.Fork) // "true" or "false"
 	meta := &search.DocumentMetadata{
 		// Customize the rank property by the product of the package score and
-		// natural logarithm of the import count. Rank must be a positive integer.
-		// Use 1 as minimum rank and keep 3 digits of precision to distinguish
-		// close ranks.
-		Rank: int(math.Max(1, 1000*p.Score*math.Log(math.E+float64(p.ImportCount)))),
+		// natural logarithm of the import rank or count. Rank must be a positive
+		// integer. Use 1 as minimum rank and keep 3 digits of precision to
+		// distinguish close ranks.
+		Rank: int(math.Max(1, 1000*p.Score*math.Log(math.E+importWeight(p.ImportCount, p.ImportRank)))),
 		Facets: []search.Facet{
 			{Name: "Fork", Value: search.Atom(fork)},
 		},
@@ -87,8 +92,8 @@ func (p *Package) Save() ([]search.Field, *search.DocumentMetadata, error) {
 // putIndex creates or updates a package entry in the search index. id identifies the document in the index.
 // If pdoc is non-nil, putIndex will update the package's name, path and synopsis supplied by pdoc.
 // pdoc must be non-nil for a package's first call to putIndex.
-// putIndex updates the Score to score, if non-negative.
-func putIndex(c context.Context, pdoc *doc.Package, id string, score float64, importCount int) error {
+// putIndex updates the Score to score and the ImportRank to importRank, if non-negative.
+func putIndex(c context.Context, pdoc *doc.Package, id string, score float64, importCount int, importRank float64) error {
 	if id == "" {
 		return errors.New("indexae: no id assigned")
 	}
@@ -119,6 +124,9 @@ func putIndex(c context.Context, pdoc *doc.Package, id string, score float64, im
 	if score >= 0 {
 		pkg.Score = score
 	}
+	if importRank >= 0 {
+		pkg.ImportRank = importRank
+	}
 	pkg.ImportCount = importCount
 
 	if _, err := idx.Put(c, id, &pkg); err != nil {
@@ -128,8 +136,9 @@ func putIndex(c context.Context, pdoc *doc.Package, id string, score float64, im
 }
 
 // searchAE searches the packages index for a given query. A path-like query string
-// will be passed in unchanged, whereas single words will be stemmed.
-func searchAE(c context.Context, q string) ([]Package, error) {
+// will be passed in unchanged, whereas single words will be stemmed. The name,
+// stars, importers and fork operators of the query are applied by the index.
+func searchAE(c context.Context, sq *searchQuery) ([]Package, error) {
 	index, err := search.Open("packages")
 	if err != nil {
 		return nil, err
@@ -138,7 +147,10 @@ func searchAE(c context.Context, q string) ([]Package, error) {
 	opt := &search.SearchOptions{
 		Limit: 100,
 	}
-	for it := index.Search(c, parseQuery2(q), opt); ; {
+	if sq.noFork {
+		opt.Refinements = []search.Facet{{Name: "Fork", Value: search.Atom("false")}}
+	}
+	for it := index.Search(c, searchAEQuery(sq), opt); ; {
 		var p Package
 		_, err := it.Next(&p)
 		if err == search.Done {
@@ -152,6 +164,21 @@ func searchAE(c context.Context, q string) ([]Package, error) {
 	return pkgs, nil
 }
 
+// searchAEQuery returns the query in the App Engine search syntax.
+func searchAEQuery(sq *searchQuery) string {
+	q := parseQuery2(sq.text)
+	if sq.name != "" {
+		q += fmt.Sprintf("Name:%q ", sq.name)
+	}
+	if sq.minStars > 0 {
+		q += fmt.Sprintf("Stars >= %d ", sq.minStars)
+	}
+	if sq.minImporters > 0 {
+		q += fmt.Sprintf("ImportCount >= %d ", sq.minImporters)
+	}
+	return q
+}
+
 func parseQuery2(q string) string {
 	var buf bytes.Buffer
 	for _, s := range strings.FieldsFunc(q, isTermSep2) {
@@ -0,0 +1,264 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file or at
+// https://developers.google.com/open-source/licenses/bsd.
+
+package database
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"strings"
+
+	"github.com/golang/snappy"
+
+	"github.com/golang/gddo/doc"
+)
+
+// maxDocSize is the maximum size of an encoded document. The types of
+// larger documents are stored separately and the document has a stub for
+// each type. The stubs have the fields needed for the package index and the
+// implementations index.
+const maxDocSize = 1200000
+
+// encodeDoc returns the snappy compressed versioned JSON encoding of pdoc.
+func encodeDoc(pdoc *doc.Package) ([]byte, error) {
+	p, err := doc.EncodePackage(pdoc)
+	if err != nil {
+		return nil, err
+	}
+	return snappy.Encode(nil, p), nil
+}
+
+// decodeDoc decodes a document encoded by encodeDoc or, if isGob is true, a
+// snappy compressed gob written before documents were encoded with JSON.
+func decodeDoc(p []byte, isGob bool) (*doc.Package, error) {
+	p, err := snappy.Decode(nil, p)
+	if err != nil {
+		return nil, err
+	}
+	if isGob {
+		var pdoc doc.Package
+		if err := gob.NewDecoder(bytes.NewReader(p)).Decode(&pdoc); err != nil {
+			return nil, err
+		}
+		return &pdoc, nil
+	}
+	return doc.DecodePackage(p)
+}
+
+// encodeDocPages returns the encoding of pdoc and, if pdoc is too large to
+// store in one value, the pages of its types keyed by type name. The types
+// of large documents are replaced with stubs, and documents that are still
+// too large are truncated. The returned document is the one encoded. The
+// file sources are expected to be removed from pdoc.
+func encodeDocPages(pdoc *doc.Package) (*doc.Package, []byte, map[string][]byte, error) {
+	p, err := encodeDoc(pdoc)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	// Store the types of large documents separately.
+	var typePages map[string][]byte
+	if len(p) > maxDocSize {
+		types := pdoc.Types
+		typePages = make(map[string][]byte, len(types))
+		pdocNew := *pdoc
+		pdoc = &pdocNew
+		pdoc.Types = make([]*doc.Type, len(types))
+		for i, t := range types {
+			page, err := encodeDoc(&doc.Package{Types: []*doc.Type{t}})
+			if err != nil {
+				return nil, nil, nil, err
+			}
+			typePages[t.Name] = page
+			pdoc.Types[i] = stubType(t)
+		}
+		if p, err = encodeDoc(pdoc); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+
+	// Truncate large documents.
+	if len(p) > maxDocSize {
+		pdocNew := *pdoc
+		pdoc = &pdocNew
+		pdoc.Truncated = true
+		pdoc.Vars = nil
+		pdoc.Funcs = nil
+		if typePages == nil {
+			pdoc.Types = nil
+		}
+		pdoc.Consts = nil
+		pdoc.Examples = nil
+		if p, err = encodeDoc(pdoc); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+	return pdoc, p, typePages, nil
+}
+
+// dropFileSources returns a copy of pdoc with the names and URLs of the
+// files but not their sources.
+func dropFileSources(pdoc *doc.Package) *doc.Package {
+	pdocNew := *pdoc
+	pdocNew.Files = make([]*doc.File, len(pdoc.Files))
+	for i, f := range pdoc.Files {
+		pdocNew.Files[i] = &doc.File{Name: f.Name, URL: f.URL}
+	}
+	return &pdocNew
+}
+
+// splitFileSources returns a copy of pdoc without the file sources and the
+// encoded sources keyed by file name. The sources are stored apart from the
+// document so that only the source view reads them.
+func splitFileSources(pdoc *doc.Package) (*doc.Package, map[string][]byte, error) {
+	var sources map[string][]byte
+	pdocNew := *pdoc
+	pdocNew.Files = nil
+	for _, f := range pdoc.Files {
+		fNew := *f
+		fNew.Source = doc.Code{}
+		pdocNew.Files = append(pdocNew.Files, &fNew)
+		if !f.HasSource {
+			continue
+		}
+		p, err := encodeSource(&f.Source)
+		if err != nil {
+			return nil, nil, err
+		}
+		if sources == nil {
+			sources = make(map[string][]byte)
+		}
+		sources[f.Name] = p
+	}
+	return &pdocNew, sources, nil
+}
+
+// encodeSource returns the snappy compressed JSON encoding of an annotated
+// file source.
+func encodeSource(src *doc.Code) ([]byte, error) {
+	p, err := json.Marshal(src)
+	if err != nil {
+		return nil, err
+	}
+	return snappy.Encode(nil, p), nil
+}
+
+func decodeSource(p []byte) (*doc.Code, error) {
+	p, err := snappy.Decode(nil, p)
+	if err != nil {
+		return nil, err
+	}
+	var src doc.Code
+	if err := json.Unmarshal(p, &src); err != nil {
+		return nil, err
+	}
+	return &src, nil
+}
+
+// loadTypePages replaces the type stubs in pdoc with the types in the
+// pages keyed by type name.
+func loadTypePages(pdoc *doc.Package, pages map[string][]byte) error {
+	for i, t := range pdoc.Types {
+		p := pages[t.Name]
+		if !t.Stub || p == nil {
+			continue
+		}
+		page, err := decodeDoc(p, false)
+		if err != nil {
+			return err
+		}
+		if len(page.Types) == 1 {
+			pdoc.Types[i] = page.Types[0]
+		}
+	}
+	return nil
+}
+
+// stubType returns the stub stored in the document for type t.
+func stubType(t *doc.Type) *doc.Type {
+	stubFuncs := func(funcs []*doc.Func) []*doc.Func {
+		stubs := make([]*doc.Func, len(funcs))
+		for i, f := range funcs {
+			stubs[i] = &doc.Func{
+				Name:      f.Name,
+				Recv:      f.Recv,
+				Decl:      doc.Code{Text: f.Decl.Text},
+				Pos:       f.Pos,
+				Platforms: f.Platforms,
+			}
+		}
+		return stubs
+	}
+	decl := t.Decl.Text
+	if i := strings.IndexByte(decl, '\n'); i >= 0 {
+		decl = decl[:i]
+	}
+	return &doc.Type{
+		Name:       t.Name,
+		Decl:       doc.Code{Text: decl},
+		Pos:        t.Pos,
+		Platforms:  t.Platforms,
+		MethodSigs: t.MethodSigs,
+		Funcs:      stubFuncs(t.Funcs),
+		Methods:    stubFuncs(t.Methods),
+		Stub:       true,
+	}
+}
+
+// LoadTypes replaces the type stubs in pdoc with the types stored
+// separately. If names are given, only the types with these names are
+// loaded.
+func (db *Database) LoadTypes(pdoc *doc.Package, names ...string) error {
+	load := make(map[string]bool)
+	for _, name := range names {
+		load[name] = true
+	}
+	var indexes []int
+	var typeNames []string
+	for i, t := range pdoc.Types {
+		if t.Stub && (len(names) == 0 || load[t.Name]) {
+			indexes = append(indexes, i)
+			typeNames = append(typeNames, t.Name)
+		}
+	}
+	if len(indexes) == 0 {
+		return nil
+	}
+
+	pages, err := db.Store.Types(pdoc.ImportPath, typeNames)
+	if err != nil {
+		return err
+	}
+
+	types := make([]*doc.Type, len(pdoc.Types))
+	copy(types, pdoc.Types)
+	for i, p := range pages {
+		if p == nil {
+			// The document was replaced after it was read.
+			continue
+		}
+		page, err := decodeDoc(p, false)
+		if err != nil {
+			return err
+		}
+		if len(page.Types) == 1 {
+			types[indexes[i]] = page.Types[0]
+		}
+	}
+	pdoc.Types = types
+	return nil
+}
+
+// Source returns the annotated source of the named file of the package
+// with the given import path or nil if the file has no stored source.
+func (db *Database) Source(path, name string) (*doc.Code, error) {
+	p, err := db.Store.Source(path, name)
+	if err != nil || p == nil {
+		return nil, err
+	}
+	return decodeSource(p)
+}
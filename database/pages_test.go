@@ -0,0 +1,54 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file or at
+// https://developers.google.com/open-source/licenses/bsd.
+
+package database
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/golang/gddo/doc"
+)
+
+func TestStubType(t *testing.T) {
+	typ := &doc.Type{
+		Doc:        "Buffer is a buffer.",
+		Name:       "Buffer",
+		Decl:       doc.Code{Text: "type Buffer struct {\n\tN int\n}", Annotations: []doc.Annotation{{Pos: 5, End: 11}}},
+		Pos:        doc.Pos{Line: 10, N: 3},
+		MethodSigs: []string{"Len:-:int"},
+		Platforms:  []string{"linux/amd64"},
+		Funcs:      []*doc.Func{{Name: "NewBuffer", Doc: "NewBuffer returns a buffer.", Decl: doc.Code{Text: "func NewBuffer() *Buffer"}}},
+		Methods:    []*doc.Func{{Name: "Len", Recv: "b *Buffer", Doc: "Len returns the length.", Decl: doc.Code{Text: "func (b *Buffer) Len() int"}, Pos: doc.Pos{Line: 20}}},
+		Fields:     []*doc.Member{{Name: "N", Text: "int"}},
+	}
+	want := &doc.Type{
+		Name:       "Buffer",
+		Decl:       doc.Code{Text: "type Buffer struct {"},
+		Pos:        doc.Pos{Line: 10, N: 3},
+		MethodSigs: []string{"Len:-:int"},
+		Platforms:  []string{"linux/amd64"},
+		Funcs:      []*doc.Func{{Name: "NewBuffer", Decl: doc.Code{Text: "func NewBuffer() *Buffer"}}},
+		Methods:    []*doc.Func{{Name: "Len", Recv: "b *Buffer", Decl: doc.Code{Text: "func (b *Buffer) Len() int"}, Pos: doc.Pos{Line: 20}}},
+		Stub:       true,
+	}
+	if diff := cmp.Diff(want, stubType(typ)); diff != "" {
+		t.Errorf("stubType differs (-want +got):\n%s", diff)
+	}
+
+	p, err := encodeDoc(&doc.Package{Types: []*doc.Type{typ}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	page, err := decodeDoc(p, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]*doc.Type{typ}, page.Types); diff != "" {
+		t.Errorf("decoded types differ (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,176 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file or at
+// https://developers.google.com/open-source/licenses/bsd.
+
+package database
+
+import (
+	"strconv"
+	"strings"
+)
+
+// searchQuery is a search query split into the text and the operators.
+//
+// The operators are:
+//
+//	import:<path>    packages importing path
+//	project:<root>   packages in the project with the given root
+//	name:<name>      packages with the given name
+//	license:<id>     packages with a license whose SPDX identifier starts with id
+//	cmd:             commands
+//	pkg:             packages that are not commands
+//	-fork            packages that are not forks
+//	stars:>N         packages with more than N stars; >=N and N mean at least N
+//	importers:>N     packages with more than N importers
+//
+// The last import:, project:, name: and license: operator wins. Words that
+// are not valid operators are part of the text. The last word of the text
+// matches as a prefix when it ends with *.
+type searchQuery struct {
+	text   string   // Query without the operators.
+	terms  []string // Terms of text. See parseQuery.
+	prefix bool     // The last term is a prefix.
+
+	importPath   string
+	projectRoot  string
+	name         string
+	license      string // Lower case.
+	kind         string // Kind of the packages: p, c or "" for any kind.
+	noFork       bool
+	minStars     int
+	minImporters int
+}
+
+// parseSearchQuery parses the search query q.
+func parseSearchQuery(q string) *searchQuery {
+	sq := &searchQuery{}
+	var text []string
+	for _, s := range strings.Fields(q) {
+		if !sq.parseOperator(s) {
+			text = append(text, s)
+		}
+	}
+	sq.text = strings.Join(text, " ")
+	if strings.HasSuffix(sq.text, "*") {
+		sq.text = strings.TrimRight(sq.text, "*")
+		sq.prefix = true
+	}
+	sq.terms = parseQuery(sq.text)
+	return sq
+}
+
+// parseOperator sets the operator s in the query. It returns false if s is
+// not a valid operator.
+func (sq *searchQuery) parseOperator(s string) bool {
+	if strings.EqualFold(s, "-fork") {
+		sq.noFork = true
+		return true
+	}
+	i := strings.IndexByte(s, ':')
+	if i <= 0 {
+		return false
+	}
+	op, v := strings.ToLower(s[:i]), s[i+1:]
+	switch op {
+	case "cmd", "pkg":
+		if v != "" {
+			return false
+		}
+		sq.kind = op[:1] // c or p
+	case "import", "project", "name", "license":
+		if v == "" {
+			return false
+		}
+		switch op {
+		case "import":
+			sq.importPath = v
+		case "project":
+			sq.projectRoot = strings.TrimSuffix(v, "/")
+		case "name":
+			sq.name = v
+		case "license":
+			sq.license = strings.ToLower(v)
+		}
+	case "stars", "importers":
+		n, ok := parseMinimum(v)
+		if !ok {
+			return false
+		}
+		if op == "stars" {
+			sq.minStars = n
+		} else {
+			sq.minImporters = n
+		}
+	default:
+		return false
+	}
+	return true
+}
+
+// parseMinimum returns the inclusive lower bound of a comparison >N, >=N or
+// N.
+func parseMinimum(s string) (int, bool) {
+	inc := 0
+	switch {
+	case strings.HasPrefix(s, ">="):
+		s = s[2:]
+	case strings.HasPrefix(s, ">"):
+		s = s[1:]
+		inc = 1
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 0 {
+		return 0, false
+	}
+	return n + inc, true
+}
+
+// hasFilter returns true if the query has operators.
+func (sq *searchQuery) hasFilter() bool {
+	return sq.importPath != "" || sq.projectRoot != "" || sq.name != "" ||
+		sq.license != "" || sq.kind != "" || sq.noFork ||
+		sq.minStars > 0 || sq.minImporters > 0
+}
+
+// match returns true if the package satisfies the operators of the query.
+// kind and terms are the kind and index terms of the package. See
+// documentKind and documentTerms.
+func (sq *searchQuery) match(pkg Package, kind string, terms []string) bool {
+	if sq.noFork && pkg.Fork ||
+		pkg.Stars < sq.minStars ||
+		pkg.ImportCount < sq.minImporters ||
+		sq.name != "" && !strings.EqualFold(pkg.Name, sq.name) ||
+		sq.kind != "" && kind != sq.kind {
+		return false
+	}
+	if sq.projectRoot != "" && !isUnder(pkg.Path, sq.projectRoot) && !hasTerm(terms, "project:"+sq.projectRoot) {
+		return false
+	}
+	if sq.importPath != "" && !hasTerm(terms, "import:"+sq.importPath) {
+		return false
+	}
+	if sq.license != "" {
+		found := false
+		for _, t := range terms {
+			if strings.HasPrefix(t, "license:"+sq.license) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func hasTerm(terms []string, term string) bool {
+	for _, t := range terms {
+		if t == term {
+			return true
+		}
+	}
+	return false
+}
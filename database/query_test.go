@@ -0,0 +1,35 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file or at
+// https://developers.google.com/open-source/licenses/bsd.
+
+package database
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+var parseSearchQueryTests = []struct {
+	q    string
+	want searchQuery
+}{
+	{"http router", searchQuery{text: "http router"}},
+	{"http router -fork importers:>50", searchQuery{text: "http router", noFork: true, minImporters: 51}},
+	{"stars:>=10 stars:5 importers:>x", searchQuery{text: "importers:>x", minStars: 5}},
+	{"import:github.com/a/b project:github.com/a/ name:Foo", searchQuery{importPath: "github.com/a/b", projectRoot: "github.com/a", name: "Foo"}},
+	{"CMD: license:MIT", searchQuery{kind: "c", license: "mit"}},
+	{"pkg: cmd:x name: fork", searchQuery{text: "cmd:x name: fork", kind: "p"}},
+}
+
+func TestParseSearchQuery(t *testing.T) {
+	for _, tt := range parseSearchQueryTests {
+		got := parseSearchQuery(tt.q)
+		tt.want.terms = parseQuery(tt.want.text)
+		if diff := cmp.Diff(&tt.want, got, cmp.AllowUnexported(searchQuery{})); diff != "" {
+			t.Errorf("parseSearchQuery(%q) differs (-want +got):\n%s", tt.q, diff)
+		}
+	}
+}
@@ -0,0 +1,237 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file or at
+// https://developers.google.com/open-source/licenses/bsd.
+
+package database
+
+import (
+	"context"
+	"log"
+	"math"
+	"strings"
+)
+
+// The import rank of a package is its PageRank in the import graph, where
+// each package links to the packages it imports. A package imported by many
+// packages that are themselves imported a lot ranks higher than a package
+// imported by the same number of packages nobody imports.
+//
+// The ranks are scaled to be comparable to import counts: a package
+// imported only by n packages of different owners that have no importers and
+// import nothing else has rank n, and a package with no importers has rank
+// 0. Imports within a project do not count.
+//
+// The packages of one owner, see importOwner, that import a package share
+// one vote: each of m such importers passes 1/m of its share of rank to the
+// package and the rest is spread over all packages. An author who publishes
+// many tiny repositories importing a package raises its rank as much as a
+// single repository would.
+const (
+	importRankDamping = 0.85
+
+	// The iteration stops when no scaled rank changes by more than
+	// importRankTolerance or after maxImportRankIterations.
+	importRankTolerance     = 0.01
+	maxImportRankIterations = 100
+)
+
+// importGraph is the import graph of the stored packages.
+type importGraph struct {
+	ids     map[string]int32 // import path to node
+	paths   []string         // by node
+	stored  []bool           // by node; false for imported packages not stored
+	imports [][]int32        // by node
+}
+
+// importOwner returns the owner of the package with the given import path:
+// the host and the first path element, for example github.com/user for
+// github.com/user/repo/pkg. Paths without a host, like the paths of the
+// standard library, have no owner and their packages do not share votes.
+func importOwner(path string) string {
+	i := strings.IndexByte(path, '/')
+	if i < 0 || !strings.Contains(path[:i], ".") {
+		return ""
+	}
+	if j := strings.IndexByte(path[i+1:], '/'); j >= 0 {
+		return path[:i+1+j]
+	}
+	return path
+}
+
+// weights returns the fraction of the rank of each node passed along each
+// of its imports.
+func (g *importGraph) weights() [][]float64 {
+	type vote struct {
+		owner string
+		node  int32
+	}
+	voters := make(map[vote]int)
+	for i, imports := range g.imports {
+		if owner := importOwner(g.paths[i]); owner != "" {
+			for _, j := range imports {
+				voters[vote{owner, j}]++
+			}
+		}
+	}
+	weights := make([][]float64, len(g.imports))
+	for i, imports := range g.imports {
+		owner := importOwner(g.paths[i])
+		weights[i] = make([]float64, len(imports))
+		for k, j := range imports {
+			w := 1 / float64(len(imports))
+			if owner != "" {
+				w /= float64(voters[vote{owner, j}])
+			}
+			weights[i][k] = w
+		}
+	}
+	return weights
+}
+
+func newImportGraph() *importGraph {
+	return &importGraph{ids: make(map[string]int32)}
+}
+
+// node returns the node of the package with the given path, adding the
+// node if needed.
+func (g *importGraph) node(path string) int32 {
+	id, ok := g.ids[path]
+	if !ok {
+		id = int32(len(g.paths))
+		g.ids[path] = id
+		g.paths = append(g.paths, path)
+		g.stored = append(g.stored, false)
+		g.imports = append(g.imports, nil)
+	}
+	return id
+}
+
+// add adds the stored package with the given path and index terms.
+func (g *importGraph) add(path string, terms []string) {
+	var root string
+	for _, t := range terms {
+		if strings.HasPrefix(t, "project:") && isUnder(path, t[len("project:"):]) {
+			root = t[len("project:"):]
+			break
+		}
+	}
+	id := g.node(path)
+	g.stored[id] = true
+	for _, t := range terms {
+		if !strings.HasPrefix(t, "import:") {
+			continue
+		}
+		p := t[len("import:"):]
+		if p == path || root != "" && isUnder(p, root) {
+			continue
+		}
+		g.imports[id] = append(g.imports[id], g.node(p))
+	}
+}
+
+// ranks returns the scaled import ranks by node.
+func (g *importGraph) ranks() []float64 {
+	const d = importRankDamping
+	n := len(g.paths)
+	if n == 0 {
+		return nil
+	}
+	r := make([]float64, n)
+	next := make([]float64, n)
+	for i := range r {
+		r[i] = 1 / float64(n)
+	}
+	weights := g.weights()
+	// unused is the fraction of the rank of each node not passed to its
+	// imports.
+	unused := make([]float64, n)
+	for i := range unused {
+		unused[i] = 1
+		for _, w := range weights[i] {
+			unused[i] -= w
+		}
+	}
+	// base is the rank of a package with no importers.
+	var base float64
+	for iter := 0; iter < maxImportRankIterations; iter++ {
+		// The rank not passed to imports, all of it for packages that
+		// import nothing, is spread over all packages.
+		dangling := 0.0
+		for i := range r {
+			dangling += unused[i] * r[i]
+		}
+		base = (1 - d + d*dangling) / float64(n)
+		for i := range next {
+			next[i] = base
+		}
+		for i, imports := range g.imports {
+			for k, j := range imports {
+				next[j] += d * r[i] * weights[i][k]
+			}
+		}
+		maxDelta := 0.0
+		for i := range r {
+			maxDelta = math.Max(maxDelta, math.Abs(next[i]-r[i]))
+		}
+		r, next = next, r
+		if maxDelta < importRankTolerance*d*base {
+			break
+		}
+	}
+	for i := range r {
+		r[i] = math.Max(0, (r[i]-base)/(d*base))
+	}
+	return r
+}
+
+// UpdateImportRanks computes the import ranks of the stored packages from
+// the import terms of the packages and stores the ranks. The ranks are used
+// to rank the search results and the top ranked packages.
+func (db *Database) UpdateImportRanks(ctx context.Context) error {
+	g := newImportGraph()
+	err := db.Store.PackageTerms(func(r *Record) error {
+		g.add(r.Path, r.Terms)
+		return ctx.Err()
+	})
+	if err != nil {
+		return err
+	}
+	ranks := make(map[string]float64)
+	for i, rank := range g.ranks() {
+		if g.stored[i] && rank > 0 {
+			ranks[g.paths[i]] = rank
+		}
+	}
+	if err := db.Store.PutImportRanks(ranks); err != nil {
+		return err
+	}
+	db.searchIndex().setImportRanks(ranks)
+	log.Printf("Import ranks of %d packages updated", len(ranks))
+	return nil
+}
+
+// importWeight returns the weight of the importers of a package in the
+// ranking of search results: the import rank of the package or, if the
+// package has no rank, the import count.
+func importWeight(importCount int, importRank float64) float64 {
+	if importRank > 0 {
+		return importRank
+	}
+	return float64(importCount)
+}
+
+// TopImportRanks returns the count packages with the highest import rank
+// with their ranks in the Score field.
+func (db *Database) TopImportRanks(count int) ([]Package, error) {
+	records, err := db.Store.TopImportRanks(count)
+	if err != nil {
+		return nil, err
+	}
+	pkgs := make([]Package, len(records))
+	for i, r := range records {
+		pkgs[i] = Package{Path: r.Path, Synopsis: r.Synopsis, Score: r.Score}
+	}
+	return pkgs, nil
+}
@@ -0,0 +1,135 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file or at
+// https://developers.google.com/open-source/licenses/bsd.
+
+package database
+
+import (
+	"context"
+	"math"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/golang/gddo/doc"
+)
+
+func TestUpdateImportRanks(t *testing.T) {
+	ctx := context.Background()
+	db, _, cleanup := newFileDB(t)
+	defer cleanup()
+
+	put := func(path, root string, imports ...string) {
+		pdoc := &doc.Package{
+			ImportPath:  path,
+			ProjectRoot: root,
+			Name:        "p",
+			Imports:     imports,
+			Funcs:       []*doc.Func{{Name: "F"}},
+		}
+		if err := db.Put(ctx, pdoc, time.Time{}, false); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// lib is imported by three packages nobody imports and core by one
+	// package imported by five.
+	put("example.com/lib", "example.com/lib", "example.com/lib/internal/x")
+	put("example.com/lib/internal/x", "example.com/lib")
+	put("example.com/core", "example.com/core")
+	put("example.com/mid", "example.com/mid", "example.com/core")
+	for i := 0; i < 3; i++ {
+		put("example.com/app"+strconv.Itoa(i), "", "example.com/lib")
+	}
+	for i := 0; i < 5; i++ {
+		put("example.com/tool"+strconv.Itoa(i), "", "example.com/mid")
+	}
+
+	if err := db.UpdateImportRanks(ctx); err != nil {
+		t.Fatal(err)
+	}
+	paths := []string{"example.com/core", "example.com/mid", "example.com/lib", "example.com/lib/internal/x", "example.com/app0"}
+	want := []float64{1 + 5*importRankDamping, 5, 3, 0, 0}
+	ranks, err := db.Store.ImportRanks(paths)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, path := range paths {
+		if math.Abs(ranks[i]-want[i]) > 2*importRankTolerance {
+			t.Errorf("import rank of %s is %g, want %g", path, ranks[i], want[i])
+		}
+	}
+
+	pkgs, err := db.TopImportRanks(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pkgs) != 2 || pkgs[0].Path != "example.com/core" || pkgs[1].Path != "example.com/mid" {
+		t.Errorf("TopImportRanks(2) = %v, want core and mid", pkgs)
+	}
+
+	// The search index has the ranks of the packages.
+	id, err := db.Store.ID("example.com/lib")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pkg, ok := db.searchIndex().get(id); !ok || math.Abs(pkg.ImportRank-3) > 2*importRankTolerance {
+		t.Errorf("import rank of example.com/lib in the search index is %g, %v; want 3", pkg.ImportRank, ok)
+	}
+}
+
+func TestImportRanksOwnerVotes(t *testing.T) {
+	g := newImportGraph()
+	add := func(path string, imports ...string) {
+		terms := []string{"project:" + path}
+		for _, p := range imports {
+			terms = append(terms, "import:"+p)
+		}
+		g.add(path, terms)
+	}
+	// One author publishes ten tiny repositories importing spam. Three
+	// authors import lib.
+	add("github.com/spammer/spam")
+	for i := 0; i < 10; i++ {
+		add("github.com/spammer/tiny"+strconv.Itoa(i), "github.com/spammer/spam")
+	}
+	add("github.com/other/lib")
+	for _, owner := range []string{"alice", "bob", "carol"} {
+		add("github.com/"+owner+"/app", "github.com/other/lib")
+	}
+	// Packages of the standard library do not share votes.
+	add("errors")
+	add("fmt", "errors")
+	add("os", "errors")
+
+	ranks := g.ranks()
+	for path, want := range map[string]float64{
+		"github.com/spammer/spam": 1,
+		"github.com/other/lib":    3,
+		"errors":                  2,
+	} {
+		if got := ranks[g.ids[path]]; math.Abs(got-want) > 2*importRankTolerance {
+			t.Errorf("import rank of %s is %g, want %g", path, got, want)
+		}
+	}
+}
+
+var importOwnerTests = []struct {
+	path, owner string
+}{
+	{"github.com/user/repo/pkg", "github.com/user"},
+	{"github.com/user", "github.com/user"},
+	{"example.com/lib", "example.com/lib"},
+	{"gopkg.in/yaml.v2", "gopkg.in/yaml.v2"},
+	{"net/http", ""},
+	{"fmt", ""},
+}
+
+func TestImportOwner(t *testing.T) {
+	for _, tt := range importOwnerTests {
+		if owner := importOwner(tt.path); owner != tt.owner {
+			t.Errorf("importOwner(%q) = %q, want %q", tt.path, owner, tt.owner)
+		}
+	}
+}
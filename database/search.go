@@ -0,0 +1,664 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file or at
+// https://developers.google.com/open-source/licenses/bsd.
+
+package database
+
+import (
+	"context"
+	"log"
+	"math"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/golang/gddo/doc"
+)
+
+// The fields of a package in the search index.
+const (
+	fieldName = iota
+	fieldPath
+	fieldSynopsis
+	fieldDoc
+	fieldSymbols
+	numFields
+)
+
+// fieldWeights are the BM25F weights of the fields.
+var fieldWeights = [numFields]float64{
+	fieldName:     4,
+	fieldPath:     2,
+	fieldSynopsis: 3,
+	fieldDoc:      1,
+	fieldSymbols:  1.5,
+}
+
+// BM25 parameters.
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+// maxSearchResults is the maximum number of packages returned by a search.
+const maxSearchResults = 100
+
+// maxNewTerms is the number of new terms above which the new terms are
+// merged into the sorted terms of the index. See searchIndex.addTerm.
+const maxNewTerms = 1024
+
+// maxExpansions is the maximum number of index terms matched by a prefix
+// or misspelled query term.
+const maxExpansions = 50
+
+// The weights of index terms matched by a query term relative to the term
+// itself.
+const (
+	prefixWeight = 0.8
+	fuzzyWeight  = 0.5 // per edit
+)
+
+// searchIndex is an inverted index of the packages with BM25F ranking. The
+// index is kept in memory. It is built from the store by
+// Database.BuildSearchIndex and kept current by Database.PutIndex and
+// Database.DeleteIndex.
+type searchIndex struct {
+	mu       sync.RWMutex
+	docs     map[string]*searchDoc                 // by package id
+	postings map[string]map[string]*[numFields]int // term to package id to term frequencies
+	totalLen [numFields]int                        // sum of the field lengths of all documents
+
+	// The terms of the postings for prefix and fuzzy matching: the sorted
+	// terms and the terms added since the terms were sorted. The sorted
+	// terms may include terms removed from the postings.
+	sortedTerms []string
+	newTerms    map[string]bool
+
+	// ready is set once Database.BuildSearchIndex added the stored
+	// documents.
+	ready bool
+}
+
+type searchDoc struct {
+	pkg     Package
+	kind    string   // See documentKind.
+	tags    []string // Terms of the query operators. See searchQuery.match.
+	symbols []string // Exported symbols. See symbolNames.
+	terms   []string // Distinct terms of the document.
+	len     [numFields]int
+}
+
+func newSearchIndex() *searchIndex {
+	return &searchIndex{
+		docs:     make(map[string]*searchDoc),
+		postings: make(map[string]map[string]*[numFields]int),
+		newTerms: make(map[string]bool),
+	}
+}
+
+// symbolNames returns the names of the exported declarations in pdoc.
+// Methods and fields are named Type.Name as in doc.Symbol.
+func symbolNames(pdoc *doc.Package) []string {
+	var names []string
+	if pdoc.Symbols != nil {
+		for _, s := range pdoc.Symbols {
+			names = append(names, s.Name)
+		}
+		return names
+	}
+	values := func(prefix string, values []*doc.Value) {
+		for _, v := range values {
+			for _, name := range v.Names {
+				names = append(names, prefix+name)
+			}
+		}
+	}
+	funcs := func(prefix string, funcs []*doc.Func) {
+		for _, f := range funcs {
+			names = append(names, prefix+f.Name)
+		}
+	}
+	values("", pdoc.Consts)
+	values("", pdoc.Vars)
+	funcs("", pdoc.Funcs)
+	for _, t := range pdoc.Types {
+		names = append(names, t.Name)
+		values("", t.Consts)
+		values("", t.Vars)
+		funcs("", t.Funcs)
+		funcs(t.Name+".", t.Methods)
+	}
+	return names
+}
+
+// documentFields returns the terms in each field of pdoc. The words in the
+// fields other than the import path are split as identifiers. See
+// identifierTerms.
+func documentFields(pdoc *doc.Package) [numFields][]string {
+	var fields [numFields][]string
+	fields[fieldName] = textTerms(pdoc.Name)
+	fields[fieldPath] = parseQuery(pdoc.ImportPath)
+	fields[fieldSynopsis] = textTerms(httpPat.ReplaceAllLiteralString(pdoc.Synopsis, ""))
+	fields[fieldDoc] = textTerms(httpPat.ReplaceAllLiteralString(pdoc.Doc, ""))
+	for _, name := range symbolNames(pdoc) {
+		if i := strings.LastIndexByte(name, '.'); i >= 0 {
+			name = name[i+1:]
+		}
+		fields[fieldSymbols] = append(fields[fieldSymbols], identifierTerms(name)...)
+	}
+	return fields
+}
+
+// put adds or replaces the package with the given id. If fields is nil,
+// only the package metadata in d.pkg is replaced. If replace is false, a
+// package already in the index is not replaced.
+func (x *searchIndex) put(id string, d *searchDoc, fields *[numFields][]string, replace bool) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	if !replace && x.docs[id] != nil {
+		return
+	}
+	if fields == nil {
+		if old := x.docs[id]; old != nil {
+			old.pkg = d.pkg
+		}
+		return
+	}
+	x.remove(id)
+	d.terms = nil
+	for f, terms := range fields {
+		d.len[f] = len(terms)
+		x.totalLen[f] += len(terms)
+		for _, term := range terms {
+			ids := x.postings[term]
+			if ids == nil {
+				ids = make(map[string]*[numFields]int)
+				x.postings[term] = ids
+				x.addTerm(term)
+			}
+			tf := ids[id]
+			if tf == nil {
+				tf = new([numFields]int)
+				ids[id] = tf
+				d.terms = append(d.terms, term)
+			}
+			tf[f]++
+		}
+	}
+	x.docs[id] = d
+}
+
+// addTerm adds a new term of the postings to the terms for prefix and
+// fuzzy matching. The new terms are merged into the sorted terms when there
+// are more than maxNewTerms of them or more than an eighth of the sorted
+// terms, so that the cost of sorting is amortized over the terms added. The
+// caller holds x.mu.
+func (x *searchIndex) addTerm(term string) {
+	if i := sort.SearchStrings(x.sortedTerms, term); i < len(x.sortedTerms) && x.sortedTerms[i] == term {
+		return
+	}
+	x.newTerms[term] = true
+	if len(x.newTerms) <= maxNewTerms || len(x.newTerms) <= len(x.sortedTerms)/8 {
+		return
+	}
+	terms := make([]string, 0, len(x.postings))
+	for term := range x.postings {
+		terms = append(terms, term)
+	}
+	sort.Strings(terms)
+	x.sortedTerms = terms
+	x.newTerms = make(map[string]bool)
+}
+
+// get returns the package with the given id.
+func (x *searchIndex) get(id string) (Package, bool) {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+	d := x.docs[id]
+	if d == nil {
+		return Package{}, false
+	}
+	return d.pkg, true
+}
+
+// setImportRanks sets the import ranks of the packages in the index to the
+// ranks by import path.
+func (x *searchIndex) setImportRanks(ranks map[string]float64) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	for _, d := range x.docs {
+		d.pkg.ImportRank = ranks[d.pkg.Path]
+	}
+}
+
+func (x *searchIndex) delete(id string) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	x.remove(id)
+}
+
+// remove removes a package. The caller holds x.mu.
+func (x *searchIndex) remove(id string) {
+	d := x.docs[id]
+	if d == nil {
+		return
+	}
+	for _, term := range d.terms {
+		ids := x.postings[term]
+		delete(ids, id)
+		if len(ids) == 0 {
+			delete(x.postings, term)
+		}
+	}
+	for f := range d.len {
+		x.totalLen[f] -= d.len[f]
+	}
+	delete(x.docs, id)
+}
+
+// A termMatch is an index term matched by a query term.
+type termMatch struct {
+	term   string
+	weight float64
+}
+
+// prefixTerms returns the terms of the postings with the given prefix,
+// other than the prefix itself, in decreasing order of the number of
+// packages with the term. The caller holds x.mu.
+func (x *searchIndex) prefixTerms(prefix string) []string {
+	var terms []string
+	for i := sort.SearchStrings(x.sortedTerms, prefix); i < len(x.sortedTerms) && strings.HasPrefix(x.sortedTerms[i], prefix); i++ {
+		terms = append(terms, x.sortedTerms[i])
+	}
+	for term := range x.newTerms {
+		if strings.HasPrefix(term, prefix) {
+			terms = append(terms, term)
+		}
+	}
+	result := terms[:0]
+	for _, term := range terms {
+		if term != prefix && x.postings[term] != nil {
+			result = append(result, term)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		ni, nj := len(x.postings[result[i]]), len(x.postings[result[j]])
+		if ni != nj {
+			return ni > nj
+		}
+		return result[i] < result[j]
+	})
+	return result
+}
+
+// maxEdits returns the maximum edit distance of the index terms matched by
+// a misspelled query term.
+func maxEdits(term string) int {
+	switch n := utf8.RuneCountInString(term); {
+	case n < 4:
+		return 0
+	case n < 8:
+		return 1
+	}
+	return 2
+}
+
+// fuzzyTerms returns the terms of the postings within maxEdits(term) edits
+// of the term. The terms start with the same letter as the term. The caller
+// holds x.mu.
+func (x *searchIndex) fuzzyTerms(term string) []termMatch {
+	k := maxEdits(term)
+	if k == 0 {
+		return nil
+	}
+	var matches []termMatch
+	match := func(t string) {
+		if d := editDistance(term, t, k); d <= k && x.postings[t] != nil {
+			matches = append(matches, termMatch{t, math.Pow(fuzzyWeight, float64(d))})
+		}
+	}
+	_, size := utf8.DecodeRuneInString(term)
+	first := term[:size]
+	for i := sort.SearchStrings(x.sortedTerms, first); i < len(x.sortedTerms) && strings.HasPrefix(x.sortedTerms[i], first); i++ {
+		match(x.sortedTerms[i])
+	}
+	for t := range x.newTerms {
+		if strings.HasPrefix(t, first) {
+			match(t)
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].weight != matches[j].weight {
+			return matches[i].weight > matches[j].weight
+		}
+		return len(x.postings[matches[i].term]) > len(x.postings[matches[j].term])
+	})
+	return matches
+}
+
+// editDistance returns the optimal string alignment distance of a and b:
+// the number of insertions, deletions, substitutions and transpositions of
+// adjacent characters that change a into b. The computation stops when the
+// distance is known to be greater than max, in which case max+1 is
+// returned.
+func editDistance(a, b string, max int) int {
+	s, t := []rune(a), []rune(b)
+	if d := len(s) - len(t); d > max || -d > max {
+		return max + 1
+	}
+	// prev2, prev and cur are rows i-2, i-1 and i of the distance matrix.
+	prev2 := make([]int, len(t)+1)
+	prev := make([]int, len(t)+1)
+	cur := make([]int, len(t)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(s); i++ {
+		cur[0] = i
+		rowMin := cur[0]
+		for j := 1; j <= len(t); j++ {
+			cost := 1
+			if s[i-1] == t[j-1] {
+				cost = 0
+			}
+			d := prev[j-1] + cost
+			if v := prev[j] + 1; v < d {
+				d = v
+			}
+			if v := cur[j-1] + 1; v < d {
+				d = v
+			}
+			if i > 1 && j > 1 && s[i-1] == t[j-2] && s[i-2] == t[j-1] {
+				if v := prev2[j-2] + 1; v < d {
+					d = v
+				}
+			}
+			cur[j] = d
+			if d < rowMin {
+				rowMin = d
+			}
+		}
+		if rowMin > max {
+			return max + 1
+		}
+		prev2, prev, cur = prev, cur, prev2
+	}
+	if d := prev[len(t)]; d <= max {
+		return d
+	}
+	return max + 1
+}
+
+// expandTerm returns the index terms matched by a query term: the term
+// itself and, if prefix is true, the terms starting with the term. If no
+// term matches, the terms within a few edits of the term match. The caller
+// holds x.mu.
+func (x *searchIndex) expandTerm(term string, prefix bool) []termMatch {
+	var matches []termMatch
+	if x.postings[term] != nil {
+		matches = append(matches, termMatch{term, 1})
+	}
+	if prefix {
+		for _, t := range x.prefixTerms(term) {
+			matches = append(matches, termMatch{t, prefixWeight})
+		}
+	}
+	if len(matches) == 0 {
+		matches = x.fuzzyTerms(term)
+	}
+	if len(matches) > maxExpansions {
+		matches = matches[:maxExpansions]
+	}
+	return matches
+}
+
+// A searchResult is a package matching a query.
+type searchResult struct {
+	id    string
+	doc   *searchDoc
+	score float64
+}
+
+// search returns the packages matching the query. See searchIndex.find.
+func (x *searchIndex) search(sq *searchQuery) []Package {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+	results := x.find(sq)
+	pkgs := make([]Package, len(results))
+	for i, r := range results {
+		pkgs[i] = r.doc.pkg
+	}
+	return pkgs
+}
+
+// find returns the packages matching the query ranked by the BM25F score
+// of the terms times the package score, the import count boost and the
+// standard library boosts. A package matches if it satisfies the operators
+// and matches all the terms. See expandTerm for the index terms matched by
+// a term. If the query has operators and no terms, all the packages
+// satisfying the operators match. The caller holds x.mu.
+func (x *searchIndex) find(sq *searchQuery) []searchResult {
+	if len(sq.terms) == 0 && !sq.hasFilter() || len(x.docs) == 0 {
+		return nil
+	}
+
+	groups := make([][]termMatch, len(sq.terms))
+	for i, term := range sq.terms {
+		groups[i] = x.expandTerm(term, sq.prefix && i == len(sq.terms)-1)
+		if len(groups[i]) == 0 {
+			return nil
+		}
+	}
+
+	var avgLen [numFields]float64
+	for f := range avgLen {
+		avgLen[f] = math.Max(1, float64(x.totalLen[f])/float64(len(x.docs)))
+	}
+
+	var results []searchResult
+	add := func(id string, d *searchDoc) {
+		if !sq.match(d.pkg, d.kind, d.tags) {
+			return
+		}
+		if score, ok := x.textScore(id, d, groups, &avgLen); ok {
+			results = append(results, searchResult{id, d, score * searchBoost(sq.text, d.pkg)})
+		}
+	}
+	if len(groups) == 0 {
+		for id, d := range x.docs {
+			add(id, d)
+		}
+	} else {
+		// Match the documents of the least frequent term against the others.
+		smallest, size := groups[0], math.MaxInt32
+		for _, g := range groups {
+			n := 0
+			for _, m := range g {
+				n += len(x.postings[m.term])
+			}
+			if n < size {
+				smallest, size = g, n
+			}
+		}
+		seen := make(map[string]bool, size)
+		for _, m := range smallest {
+			for id := range x.postings[m.term] {
+				if !seen[id] {
+					seen[id] = true
+					add(id, x.docs[id])
+				}
+			}
+		}
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].score != results[j].score {
+			return results[i].score > results[j].score
+		}
+		return results[i].doc.pkg.Path < results[j].doc.pkg.Path
+	})
+	if len(results) > maxSearchResults {
+		results = results[:maxSearchResults]
+	}
+	return results
+}
+
+// textScore returns the BM25F score of the term groups in the document.
+// The score of a group is the highest weighted score of its terms. It
+// returns false if the document does not have a term of each group. The
+// score of no groups is 1. The caller holds x.mu.
+func (x *searchIndex) textScore(id string, d *searchDoc, groups [][]termMatch, avgLen *[numFields]float64) (float64, bool) {
+	if len(groups) == 0 {
+		return 1, true
+	}
+	n := float64(len(x.docs))
+	score := 0.0
+	for _, g := range groups {
+		best := -1.0
+		for _, m := range g {
+			ids := x.postings[m.term]
+			tf := ids[id]
+			if tf == nil {
+				continue
+			}
+			w := 0.0
+			for f := range tf {
+				if tf[f] > 0 {
+					w += fieldWeights[f] * float64(tf[f]) / (1 - bm25B + bm25B*float64(d.len[f])/avgLen[f])
+				}
+			}
+			df := float64(len(ids))
+			idf := math.Log(1 + (n-df+0.5)/(df+0.5))
+			if s := m.weight * idf * w / (bm25K1 + w); s > best {
+				best = s
+			}
+		}
+		if best < 0 {
+			return 0, false
+		}
+		score += best
+	}
+	return score, true
+}
+
+// searchBoost returns the factor applied to the text score of a package for
+// the query q. Packages are boosted by their document score and import rank
+// or count as in the rank of the App Engine index, and standard packages
+// and packages named by the query are boosted as in Query.
+func searchBoost(q string, pkg Package) float64 {
+	r := pkg.Score * math.Log(math.E+importWeight(pkg.ImportCount, pkg.ImportRank))
+	if isStandardPackage(pkg.Path) {
+		if q != "" && strings.HasSuffix(pkg.Path, q) {
+			// Big bump for exact match on standard package name.
+			r *= 10000
+		} else {
+			r *= 1.2
+		}
+	}
+	if q == path.Base(pkg.Path) {
+		r *= 1.1
+	}
+	return r
+}
+
+// searchIndex returns the local search index of the database.
+func (db *Database) searchIndex() *searchIndex {
+	db.indexOnce.Do(func() { db.index = newSearchIndex() })
+	return db.index
+}
+
+// putLocalIndex puts a package into the local search index. The score and
+// import rank are not updated if negative. See PutIndex.
+func (db *Database) putLocalIndex(pdoc *doc.Package, id string, score float64, importCount int, importRank float64, replace bool) {
+	x := db.searchIndex()
+	pkg, ok := x.get(id)
+	if importRank >= 0 {
+		pkg.ImportRank = importRank
+	}
+	if pdoc == nil {
+		if !ok {
+			return
+		}
+		if score >= 0 {
+			pkg.Score = score
+		}
+		pkg.ImportCount = importCount
+		x.put(id, &searchDoc{pkg: pkg}, nil, true)
+		return
+	}
+	pkg.Name = pdoc.Name
+	pkg.Path = pdoc.ImportPath
+	pkg.Synopsis = pdoc.Synopsis
+	pkg.Stars = pdoc.Stars
+	pkg.Fork = pdoc.Fork
+	if score >= 0 {
+		pkg.Score = score
+	}
+	pkg.ImportCount = importCount
+	fields := documentFields(pdoc)
+	// The terms of a document with no score are the terms of the operators.
+	d := &searchDoc{
+		pkg:     pkg,
+		kind:    documentKind(pdoc),
+		tags:    documentTerms(pdoc, 0),
+		symbols: symbolNames(pdoc),
+	}
+	x.put(id, d, &fields, replace)
+}
+
+// BuildSearchIndex adds the documents in the store to the local search
+// index. Documents put while the index is built are not replaced. The index
+// is ready once BuildSearchIndex returns without an error, see
+// SearchIndexReady.
+func (db *Database) BuildSearchIndex(ctx context.Context) error {
+	x := db.searchIndex()
+	n := 0
+	err := db.Store.Docs(func(r *Record) error {
+		if r.Score <= 0 || r.ID == "" {
+			return nil
+		}
+		if _, ok := x.get(r.ID); ok {
+			return nil
+		}
+		pdoc, err := decodeDoc(r.Doc, r.IsGob)
+		if err != nil {
+			log.Printf("database.BuildSearchIndex: decoding %s: %v", r.Path, err)
+			return nil
+		}
+		importCount, err := db.ImporterCount(r.Path)
+		if err != nil {
+			return err
+		}
+		ranks, err := db.Store.ImportRanks([]string{r.Path})
+		if err != nil {
+			return err
+		}
+		db.putLocalIndex(pdoc, r.ID, r.Score, importCount, ranks[0], false)
+		n++
+		return ctx.Err()
+	})
+	log.Printf("%d packages added to the search index", n)
+	if err != nil {
+		return err
+	}
+	x.mu.Lock()
+	x.ready = true
+	x.mu.Unlock()
+	return nil
+}
+
+// SearchIndexReady returns false while the local search index is not
+// built, when Search may return incomplete results.
+func (db *Database) SearchIndexReady() bool {
+	if db.RemoteClient != nil {
+		return true
+	}
+	x := db.searchIndex()
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+	return x.ready
+}
@@ -0,0 +1,319 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file or at
+// https://developers.google.com/open-source/licenses/bsd.
+
+package database
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/golang/gddo/doc"
+)
+
+func searchPaths(pkgs []Package) []string {
+	var paths []string
+	for _, pkg := range pkgs {
+		paths = append(paths, pkg.Path)
+	}
+	return paths
+}
+
+var searchTestDocs = []struct {
+	pdoc        *doc.Package
+	id          string
+	score       float64
+	importCount int
+}{
+	{&doc.Package{
+		ImportPath:  "github.com/a/router",
+		ProjectRoot: "github.com/a/router",
+		Name:        "router",
+		Synopsis:    "Package router is a fast HTTP request router.",
+		Funcs:       []*doc.Func{{Name: "New"}},
+		Imports:     []string{"net/http"},
+		Stars:       100,
+		License:     "MIT",
+	}, "1", 1, 10},
+	{&doc.Package{
+		ImportPath: "github.com/b/web",
+		Name:       "web",
+		Synopsis:   "Package web is a web framework.",
+		Doc:        "Package web is a web framework with an HTTP router and middleware.",
+		Imports:    []string{"github.com/a/router", "net/http"},
+		Fork:       true,
+		Stars:      10,
+		License:    "Apache-2.0",
+	}, "2", 1, 10},
+	{&doc.Package{
+		ImportPath: "github.com/c/mux",
+		Name:       "mux",
+		Synopsis:   "Package mux implements a request multiplexer.",
+		Types:      []*doc.Type{{Name: "Router"}},
+		Imports:    []string{"net/http"},
+		Stars:      5000,
+		License:    "BSD-3-Clause",
+	}, "3", 1, 500},
+	{&doc.Package{
+		ImportPath: "net/http",
+		Name:       "http",
+		Synopsis:   "Package http provides HTTP client and server implementations.",
+	}, "4", 1, 1000},
+	{&doc.Package{
+		ImportPath:  "github.com/a/router/cmd/gen",
+		ProjectRoot: "github.com/a/router",
+		Name:        "main",
+		IsCmd:       true,
+		Synopsis:    "Command gen generates routes.",
+		Doc:         "Command gen generates routes.",
+		Imports:     []string{"github.com/a/router"},
+	}, "5", 1, 0},
+}
+
+// newMemoryDB returns a database with an empty file store that is never
+// saved.
+func newMemoryDB() *Database {
+	store := &fileStore{}
+	store.init()
+	return &Database{Store: store}
+}
+
+func newTestSearchDB(t *testing.T) *Database {
+	db := newMemoryDB()
+	for _, d := range searchTestDocs {
+		if err := db.PutIndex(context.Background(), d.pdoc, d.id, d.score, d.importCount); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return db
+}
+
+func TestSearchIndex(t *testing.T) {
+	ctx := context.Background()
+	db := newTestSearchDB(t)
+	for _, tt := range []struct {
+		q    string
+		want []string
+	}{
+		// The popular package with a Router type and the name match rank
+		// above the path match and the match in the documentation.
+		{"router", []string{"github.com/c/mux", "github.com/a/router", "github.com/a/router/cmd/gen", "github.com/b/web"}},
+		// All terms must match.
+		{"http router", []string{"github.com/a/router", "github.com/b/web"}},
+		{"multiplexer", []string{"github.com/c/mux"}},
+		// Standard packages are boosted.
+		{"http", []string{"net/http", "github.com/a/router", "github.com/b/web"}},
+		// Operators.
+		{"router -fork", []string{"github.com/c/mux", "github.com/a/router", "github.com/a/router/cmd/gen"}},
+		{"router importers:>10", []string{"github.com/c/mux"}},
+		{"router stars:>=100", []string{"github.com/c/mux", "github.com/a/router"}},
+		{"name:mux", []string{"github.com/c/mux"}},
+		{"import:github.com/a/router", []string{"github.com/b/web", "github.com/a/router/cmd/gen"}},
+		{"project:github.com/a/router", []string{"github.com/a/router", "github.com/a/router/cmd/gen"}},
+		{"cmd:", []string{"github.com/a/router/cmd/gen"}},
+		{"pkg: router", []string{"github.com/c/mux", "github.com/a/router", "github.com/b/web"}},
+		{"license:bsd", []string{"github.com/c/mux"}},
+		{"License:MIT router", []string{"github.com/a/router"}},
+		{"http -fork importers:>5 stars:>50", []string{"github.com/a/router"}},
+		{"the", nil},
+		{"nomatch", nil},
+	} {
+		pkgs, err := db.Search(ctx, tt.q)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(tt.want, searchPaths(pkgs)); diff != "" {
+			t.Errorf("Search(%q) differs (-want +got):\n%s", tt.q, diff)
+		}
+	}
+
+	// The import count is updated without the document.
+	if err := db.PutIndex(ctx, nil, "1", -1, 10000); err != nil {
+		t.Fatal(err)
+	}
+	pkgs, _ := db.Search(ctx, "router")
+	if got := searchPaths(pkgs); len(got) == 0 || got[0] != "github.com/a/router" {
+		t.Errorf("Search(router) after import count update = %v, want github.com/a/router first", got)
+	}
+	if pkgs[0].ImportCount != 10000 || pkgs[0].Synopsis != searchTestDocs[0].pdoc.Synopsis {
+		t.Errorf("Search(router) returned %+v", pkgs[0])
+	}
+
+	if err := db.DeleteIndex(ctx, "3"); err != nil {
+		t.Fatal(err)
+	}
+	pkgs, _ = db.Search(ctx, "multiplexer")
+	if len(pkgs) != 0 {
+		t.Errorf("Search(multiplexer) after delete = %v, want none", searchPaths(pkgs))
+	}
+}
+
+func TestBuildSearchIndex(t *testing.T) {
+	ctx := context.Background()
+	db, _, cleanup := newFileDB(t)
+	defer cleanup()
+	for _, d := range searchTestDocs {
+		if err := db.Put(ctx, d.pdoc, time.Time{}, false); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// A new database has an empty index until it is built.
+	db = &Database{Store: db.Store}
+	if pkgs, _ := db.Search(ctx, "router"); len(pkgs) != 0 {
+		t.Errorf("Search(router) before building the index = %v", searchPaths(pkgs))
+	}
+	if db.SearchIndexReady() {
+		t.Error("SearchIndexReady() = true before building the index")
+	}
+	if err := db.BuildSearchIndex(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if !db.SearchIndexReady() {
+		t.Error("SearchIndexReady() = false after building the index")
+	}
+	pkgs, err := db.Search(ctx, "multiplexer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]string{"github.com/c/mux"}, searchPaths(pkgs)); diff != "" {
+		t.Errorf("Search(multiplexer) differs (-want +got):\n%s", diff)
+	}
+}
+
+func TestSearchIdentifiers(t *testing.T) {
+	ctx := context.Background()
+	db := newMemoryDB()
+	pdoc := &doc.Package{
+		ImportPath: "github.com/a/pool",
+		Name:       "pool",
+		Synopsis:   "Package pool provides a ReadCloser pool.",
+		Types:      []*doc.Type{{Name: "HTTP2ConnPool", Methods: []*doc.Func{{Name: "ReadAll"}}}},
+	}
+	if err := db.PutIndex(ctx, pdoc, "1", 1, 0); err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []struct {
+		q     string
+		match bool
+	}{
+		{"ReadCloser", true},
+		{"read closer", true},
+		{"readall", true},
+		{"http2", true},
+		{"http2connpool", true},
+		{"connection pool", true},
+		{"grpc_gateway pool", false},
+	} {
+		pkgs, err := db.Search(ctx, tt.q)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if match := len(pkgs) == 1; match != tt.match {
+			t.Errorf("Search(%q) = %v, want match %v", tt.q, searchPaths(pkgs), tt.match)
+		}
+	}
+}
+
+var editDistanceTests = []struct {
+	a, b string
+	max  int
+	want int
+}{
+	{"router", "router", 2, 0},
+	{"router", "rotuer", 2, 1},
+	{"router", "routr", 2, 1},
+	{"router", "routers", 2, 1},
+	{"router", "ruter", 2, 1},
+	{"router", "rooter", 2, 1},
+	{"router", "rotuers", 2, 2},
+	{"router", "r", 2, 3},
+	{"router", "xxxxxx", 1, 2},
+	{"日本語", "日語本", 1, 1},
+}
+
+func TestEditDistance(t *testing.T) {
+	for _, tt := range editDistanceTests {
+		if got := editDistance(tt.a, tt.b, tt.max); got != tt.want {
+			t.Errorf("editDistance(%q, %q, %d) = %d, want %d", tt.a, tt.b, tt.max, got, tt.want)
+		}
+	}
+}
+
+func TestSearchPrefixAndTypos(t *testing.T) {
+	ctx := context.Background()
+	db := newTestSearchDB(t)
+	for _, tt := range []struct {
+		q    string
+		want []string
+	}{
+		// A misspelled term matches the terms within a few edits.
+		{"multiplexr", []string{"github.com/c/mux"}},
+		{"mutliplexer", []string{"github.com/c/mux"}},
+		{"requets multiplexer", []string{"github.com/c/mux"}},
+		// Terms with fewer than four letters must be spelled exactly.
+		{"mxu", nil},
+		// The last term matches as a prefix.
+		{"multi", nil},
+		{"multi*", []string{"github.com/c/mux"}},
+		{"request multipl*", []string{"github.com/c/mux"}},
+		{"framew*", []string{"github.com/b/web"}},
+	} {
+		pkgs, err := db.Search(ctx, tt.q)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(tt.want, searchPaths(pkgs)); diff != "" {
+			t.Errorf("Search(%q) differs (-want +got):\n%s", tt.q, diff)
+		}
+	}
+}
+
+func TestSearchSortedTerms(t *testing.T) {
+	ctx := context.Background()
+	db := newMemoryDB()
+	// Add enough terms to merge the new terms into the sorted terms.
+	for i := 0; i < 2*maxNewTerms; i++ {
+		pdoc := &doc.Package{
+			ImportPath: fmt.Sprintf("github.com/a/p%d", i),
+			Name:       "p",
+			Synopsis:   fmt.Sprintf("Package p has the term quux%d.", i),
+		}
+		if err := db.PutIndex(ctx, pdoc, strconv.Itoa(i+1), 1, 0); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if x := db.searchIndex(); len(x.sortedTerms) == 0 || len(x.newTerms) == 0 {
+		t.Fatalf("got %d sorted terms and %d new terms, want both", len(x.sortedTerms), len(x.newTerms))
+	}
+	for _, tt := range []struct {
+		q    string
+		want int
+	}{
+		{"quux12", 1},
+		{"quux204*", 9},
+		{"quux2000*", 1},
+		{"quux1999*", 1},
+	} {
+		pkgs, err := db.Search(ctx, tt.q)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(pkgs) != tt.want {
+			t.Errorf("Search(%q) returned %d packages, want %d", tt.q, len(pkgs), tt.want)
+		}
+	}
+
+	// The term one edit away ranks above the terms two edits away.
+	pkgs, _ := db.Search(ctx, "quxu1999")
+	if len(pkgs) == 0 || pkgs[0].Path != "github.com/a/p1999" {
+		t.Errorf("Search(quxu1999) = %v, want github.com/a/p1999 first", searchPaths(pkgs))
+	}
+}
@@ -0,0 +1,288 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file or at
+// https://developers.google.com/open-source/licenses/bsd.
+
+package database
+
+import "time"
+
+// Store is the storage backend of a Database. The Database encodes the
+// documents and computes the search terms and scores; the Store keeps them
+// and maintains the indexes derived from them.
+//
+// Packages are identified by import path. A Store assigns each package a
+// stable id that is used as the document id in the search index.
+type Store interface {
+	// CheckHealth returns an error if the store is not available.
+	CheckHealth() error
+
+	// Close releases the resources of the store.
+	Close() error
+
+	// Documents.
+
+	// Exists returns true if there is a document for the path.
+	Exists(path string) (bool, error)
+
+	// ID returns the id of the package with the given path or "" if the
+	// package is not stored.
+	ID(path string) (string, error)
+
+	// PutDoc stores a document. The search terms of the document replace
+	// the previous terms in the term index. If NextCrawl is zero, the
+	// crawl time of the package is not changed. The path is removed from
+	// the new and bad crawl sets.
+	PutDoc(r *Record) error
+
+	// GetDoc returns the document with the given path or nil if the
+	// document is not stored. If path is "-", GetDoc returns the document
+	// with the earliest crawl time.
+	GetDoc(path string) (*Record, error)
+
+	// DeleteDoc deletes the document and removes it from the indexes, the
+	// crawl queue and the popular packages. The history of the package is
+	// kept.
+	DeleteDoc(path string) error
+
+	// Docs calls f for each stored document.
+	Docs(f func(*Record) error) error
+
+	// PackageTerms calls f for each stored document with only the Path and
+	// Terms fields of the record set. It is cheaper than Docs because the
+	// documents are not loaded.
+	PackageTerms(f func(*Record) error) error
+
+	// Types returns the pages of the named types of a document stored
+	// with separate types. The page of a missing type is nil.
+	Types(path string, names []string) ([][]byte, error)
+
+	// Source returns the encoded source of the named file of a document
+	// or nil if the file has no stored source.
+	Source(path, name string) ([]byte, error)
+
+	// Symbols returns the encoded symbol table of a document.
+	Symbols(path string) ([]byte, error)
+
+	// PutImplementations replaces the implementation terms of a package.
+	// See implementationTerms for the format of the terms.
+	PutImplementations(path string, terms []string) error
+
+	// Implementations returns the terms of the implementations of
+	// interfaces declared in the package with the given path, with the
+	// path of the implementing package in place of the interface package.
+	Implementations(path string) ([]string, error)
+
+	// PutMethodSets replaces the method set terms of a stored package. See
+	// methodSetTerms for the format of the terms.
+	PutMethodSets(path string, terms []string) error
+
+	// MethodSets returns the method set terms of the packages with the
+	// given paths in the same order.
+	MethodSets(paths []string) ([][]string, error)
+
+	// Term index.
+
+	// Packages returns the path, synopsis, kind and search terms of the
+	// packages with the given paths in the same order. The kind of a
+	// package that is not stored is "u".
+	Packages(paths []string) ([]*Record, error)
+
+	// TermPackages returns the path, synopsis, kind and coverage of the
+	// packages with the given search term, sorted by path.
+	TermPackages(term string) ([]*Record, error)
+
+	// TermCounts returns the number of packages with each term.
+	TermCounts(terms []string) ([]int, error)
+
+	// Query returns the path, synopsis and score of the packages with all
+	// the given terms.
+	Query(terms []string) ([]*Record, error)
+
+	// Document history.
+
+	// PutVersion adds a version to the history of the package with the
+	// given path, replacing the version with the same key. The versions
+	// updated before since and the versions older than the n newest are
+	// removed from the history.
+	PutVersion(path string, v *VersionRecord, n int, since time.Time) error
+
+	// Versions returns the key and update time of the versions in the
+	// history of the package with the given path, newest first.
+	Versions(path string) ([]*VersionRecord, error)
+
+	// GetVersion returns the version with the given key in the history of
+	// the package with the given path or nil if there is no such version.
+	GetVersion(path, key string) (*VersionRecord, error)
+
+	// Crawl queue.
+
+	// AddNewCrawl adds the paths that are not stored and have no crawl
+	// failure record to the new crawl set.
+	AddNewCrawl(paths []string) error
+
+	// RetryCrawl adds the path to the new crawl set whether or not the path
+	// has a crawl failure record. RetryCrawl returns false if the path is
+	// in the set.
+	RetryCrawl(path string) (bool, error)
+
+	// PopNewCrawl removes and returns a path from the new crawl set or
+	// returns "" if the set is empty.
+	PopNewCrawl() (string, error)
+
+	// NewCrawls returns the new crawl set.
+	NewCrawls() ([]string, error)
+
+	// SetNextCrawl sets the crawl time of a stored package.
+	SetNextCrawl(path string, t time.Time) error
+
+	// BumpCrawl schedules the packages in the project for crawling at now,
+	// or a little later for packages that were crawled recently.
+	BumpCrawl(projectRoot string, now time.Time) error
+
+	// CrawlQueue returns the path, synopsis, kind and score of the packages
+	// scheduled for crawling, sorted by decreasing score.
+	CrawlQueue() ([]*Record, error)
+
+	// DueCrawls returns the paths of up to n stored packages scheduled for
+	// crawling at or before now, earliest first.
+	DueCrawls(now time.Time, n int) ([]string, error)
+
+	// GetCrawlState returns the crawl state of the path or nil if there is
+	// no state.
+	GetCrawlState(path string) (*CrawlState, error)
+
+	// PutCrawlState replaces the crawl state of the path.
+	PutCrawlState(path string, st *CrawlState) error
+
+	// Crawl failures. PutDoc deletes the crawl failure record of the
+	// package.
+
+	// GetCrawlFailure returns the crawl failure record of the path or nil
+	// if there is no record.
+	GetCrawlFailure(path string) (*CrawlFailure, error)
+
+	// PutCrawlFailure replaces the crawl failure record of f.Path.
+	PutCrawlFailure(f *CrawlFailure) error
+
+	// DeleteCrawlFailure deletes the crawl failure record of the path.
+	DeleteCrawlFailure(path string) error
+
+	// CrawlFailures returns the crawl failure records in no particular
+	// order.
+	CrawlFailures() ([]*CrawlFailure, error)
+
+	// Blocklist.
+
+	// Block adds root to the blocklist and deletes the packages, their
+	// history, the new crawls, the crawl states and the crawl failure
+	// records under root. Block returns the ids of the deleted packages.
+	Block(root string) ([]string, error)
+
+	// IsBlocked returns true if the path or one of its parent directories
+	// is blocked.
+	IsBlocked(path string) (bool, error)
+
+	// Popularity.
+
+	// IncrementPopularScore adds delta*exp(scaledTime) to the popularity
+	// score of a stored package. See Database.incrementPopularScoreInternal.
+	IncrementPopularScore(path string, delta, scaledTime float64) error
+
+	// Popular returns the path, synopsis, kind and popularity score of the
+	// count most popular packages, or all packages if count is not
+	// positive.
+	Popular(count int) ([]*Record, error)
+
+	// PopularScores returns the popularity scores of the packages with the
+	// given paths, 0 for a package that is not popular.
+	PopularScores(paths []string) ([]float64, error)
+
+	// IncrementCounter adds delta to the counter with the given key and
+	// returns the new value. The value decays with the scaled time. See
+	// Database.incrementCounterInternal. The counter is deleted when it is
+	// not incremented for the expiration duration.
+	IncrementCounter(key string, delta, scaledTime float64, expiration time.Duration) (float64, error)
+
+	// Import ranks.
+
+	// PutImportRanks replaces the import ranks of the stored packages with
+	// the ranks by import path. See Database.UpdateImportRanks.
+	PutImportRanks(ranks map[string]float64) error
+
+	// ImportRanks returns the import ranks of the packages with the given
+	// paths, 0 for a package with no rank.
+	ImportRanks(paths []string) ([]float64, error)
+
+	// TopImportRanks returns the path, synopsis, kind and import rank of
+	// the count packages with the highest import rank.
+	TopImportRanks(count int) ([]*Record, error)
+
+	// Blobs.
+
+	// PutBlob and GetBlob store and return arbitrary values. GetBlob
+	// returns nil if there is no value for the key.
+	PutBlob(key string, p []byte) error
+	GetBlob(key string) ([]byte, error)
+}
+
+// Record is a package as kept by a Store. Methods that list packages only
+// set some of the fields.
+type Record struct {
+	ID       string
+	Path     string
+	Synopsis string
+
+	// Kind of the package: p=package, c=command, d=directory with no go
+	// files, u=unknown.
+	Kind string
+
+	// Search score or, for Popular, the popularity score and, for
+	// TopImportRanks, the import rank.
+	Score float64
+
+	// Search terms, see documentTerms.
+	Terms []string
+
+	Etag string
+
+	// Documented and total exported declarations, formatted as "%d %d".
+	Coverage string
+
+	// Time of the next crawl or zero if the package is not scheduled.
+	NextCrawl time.Time
+
+	// Snappy compressed document encoded by doc.EncodePackage or, if IsGob
+	// is true, by encoding/gob.
+	Doc   []byte
+	IsGob bool
+
+	// Encoded symbol table, see encodeSymbols.
+	Symbols []byte
+
+	// Pages of the types of a document too large to store in one value,
+	// keyed by type name. Only set by PutDoc.
+	Types map[string][]byte
+
+	// Sources of the files keyed by file name, see encodeSource. Only set
+	// by PutDoc.
+	Sources map[string][]byte
+}
+
+// VersionRecord is a version of a document in the history of a package.
+type VersionRecord struct {
+	// Key of the version, see historyKey.
+	Key string
+
+	// Time the version was stored.
+	Updated time.Time
+
+	// Snappy compressed document encoded by doc.EncodePackage. Only set by
+	// PutVersion and GetVersion.
+	Doc []byte
+
+	// Pages of the types of a document too large to store in one value,
+	// keyed by type name. Only set by PutVersion and GetVersion.
+	Types map[string][]byte
+}
@@ -0,0 +1,160 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file or at
+// https://developers.google.com/open-source/licenses/bsd.
+
+package database
+
+import (
+	"context"
+	"sort"
+	"strings"
+)
+
+// A Suggestion is a completion of a search query: a package or a symbol
+// of a package.
+type Suggestion struct {
+	Text     string `json:"text"`             // Completed query.
+	Path     string `json:"path"`             // Import path of the package.
+	Symbol   string `json:"symbol,omitempty"` // Name of the symbol. See doc.Symbol.
+	Synopsis string `json:"synopsis,omitempty"`
+}
+
+// maxSuggestionCandidates is the maximum number of packages examined for
+// symbol completions.
+const maxSuggestionCandidates = 1000
+
+// maxPackageSymbolSuggestions is the maximum number of symbol completions
+// from a package unless the package is named in the query.
+const maxPackageSymbolSuggestions = 3
+
+// Suggest returns up to n completions of the query q in the local search
+// index. The last word of the query is completed as a prefix. Packages
+// matching the query come first, then the symbols starting with the last
+// word, which may be qualified by the package name as in http.Hand. The
+// search stops early with the completions found so far when ctx is done.
+func (db *Database) Suggest(ctx context.Context, q string, n int) ([]Suggestion, error) {
+	q = strings.TrimSpace(q)
+	if q == "" || n <= 0 {
+		return nil, nil
+	}
+	x := db.searchIndex()
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+
+	var suggestions []Suggestion
+	sq := parseSearchQuery(q)
+	sq.prefix = true
+	for _, r := range x.find(sq) {
+		if len(suggestions) >= (n+1)/2 {
+			break
+		}
+		suggestions = append(suggestions, Suggestion{
+			Text:     r.doc.pkg.Path,
+			Path:     r.doc.pkg.Path,
+			Synopsis: r.doc.pkg.Synopsis,
+		})
+	}
+	if err := ctx.Err(); err != nil {
+		return suggestions, nil
+	}
+
+	symbols := x.suggestSymbols(ctx, q[strings.LastIndexAny(q, " \t")+1:], n-len(suggestions))
+	return append(suggestions, symbols...), nil
+}
+
+// suggestSymbols returns up to n symbols starting with the word w ranked
+// by the rank of their packages. If w is qualified by a package name as in
+// pkg.Sym, only the symbols of the packages with that name are returned.
+// The caller holds x.mu.
+func (x *searchIndex) suggestSymbols(ctx context.Context, w string, n int) []Suggestion {
+	var pkgName string
+	if i := strings.IndexByte(w, '.'); i >= 0 {
+		pkgName, w = w[:i], w[i+1:]
+	}
+	if w == "" && pkgName == "" || n <= 0 {
+		return nil
+	}
+	prefix := strings.ToLower(w)
+
+	// Find the candidate packages by the index terms of the package name or
+	// the symbol prefix.
+	var matches []termMatch
+	field := fieldSymbols
+	switch {
+	case pkgName != "":
+		matches = []termMatch{{term: term(pkgName)}}
+		field = fieldName
+	default:
+		terms := parseQuery(w)
+		if len(terms) != 1 {
+			return nil
+		}
+		matches = x.expandTerm(terms[0], true)
+	}
+	var candidates []searchResult
+	seen := make(map[string]bool)
+	for _, m := range matches {
+		for id, tf := range x.postings[m.term] {
+			if tf[field] == 0 || seen[id] {
+				continue
+			}
+			seen[id] = true
+			d := x.docs[id]
+			if pkgName != "" && !strings.EqualFold(d.pkg.Name, pkgName) {
+				continue
+			}
+			candidates = append(candidates, searchResult{id, d, searchBoost("", d.pkg)})
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].score != candidates[j].score {
+			return candidates[i].score > candidates[j].score
+		}
+		return candidates[i].doc.pkg.Path < candidates[j].doc.pkg.Path
+	})
+	if len(candidates) > maxSuggestionCandidates {
+		candidates = candidates[:maxSuggestionCandidates]
+	}
+
+	var suggestions []Suggestion
+	for _, c := range candidates {
+		if ctx.Err() != nil {
+			break
+		}
+		var names []string
+		for _, name := range c.doc.symbols {
+			base := name
+			if pkgName == "" {
+				// Complete methods and fields by their own name.
+				base = name[strings.LastIndexByte(name, '.')+1:]
+			}
+			if strings.HasPrefix(strings.ToLower(base), prefix) {
+				names = append(names, name)
+			}
+		}
+		// Shorter names are closer to the prefix.
+		sort.Slice(names, func(i, j int) bool {
+			if len(names[i]) != len(names[j]) {
+				return len(names[i]) < len(names[j])
+			}
+			return names[i] < names[j]
+		})
+		if pkgName == "" && len(names) > maxPackageSymbolSuggestions {
+			names = names[:maxPackageSymbolSuggestions]
+		}
+		for _, name := range names {
+			if len(suggestions) >= n {
+				return suggestions
+			}
+			suggestions = append(suggestions, Suggestion{
+				Text:     c.doc.pkg.Name + "." + name,
+				Path:     c.doc.pkg.Path,
+				Symbol:   name,
+				Synopsis: c.doc.pkg.Synopsis,
+			})
+		}
+	}
+	return suggestions
+}
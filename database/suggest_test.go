@@ -0,0 +1,47 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file or at
+// https://developers.google.com/open-source/licenses/bsd.
+
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSuggest(t *testing.T) {
+	ctx := context.Background()
+	db := newTestSearchDB(t)
+	for _, tt := range []struct {
+		q    string
+		want []string
+	}{
+		{"multip", []string{"github.com/c/mux"}},
+		{"rou", []string{"github.com/c/mux", "github.com/a/router", "github.com/a/router/cmd/gen", "github.com/b/web", "mux.Router"}},
+		{"mux.", []string{"github.com/c/mux", "mux.Router"}},
+		{"http.", []string{"net/http", "github.com/a/router", "github.com/b/web"}},
+		{"router.N", []string{"router.New"}},
+		{"", nil},
+	} {
+		suggestions, err := db.Suggest(ctx, tt.q, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		for _, s := range suggestions {
+			got = append(got, s.Text)
+		}
+		if diff := cmp.Diff(tt.want, got); diff != "" {
+			t.Errorf("Suggest(%q) differs (-want +got):\n%s", tt.q, diff)
+		}
+	}
+
+	suggestions, _ := db.Suggest(ctx, "rou", 2)
+	if len(suggestions) != 2 || suggestions[1].Symbol != "Router" || suggestions[1].Path != "github.com/c/mux" {
+		t.Errorf("Suggest(rou, 2) = %+v, want a package and the Router symbol", suggestions)
+	}
+}
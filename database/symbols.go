@@ -0,0 +1,94 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file or at
+// https://developers.google.com/open-source/licenses/bsd.
+
+package database
+
+import (
+	"bytes"
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/golang/snappy"
+
+	"github.com/golang/gddo/doc"
+)
+
+// The symbol table of a package is stored in the 'symbols' field of the
+// package hash. The table has one line per symbol with the tab separated
+// fields name, kind, file index, line, line count, signature and synopsis.
+// The table is compressed with snappy.
+
+var symbolFieldCleaner = strings.NewReplacer("\t", " ", "\n", " ")
+
+// encodeSymbols returns the symbol table for symbols.
+func encodeSymbols(symbols []*doc.Symbol) []byte {
+	if len(symbols) == 0 {
+		return nil
+	}
+	var buf bytes.Buffer
+	for _, s := range symbols {
+		buf.WriteString(s.Name)
+		buf.WriteByte('\t')
+		buf.WriteString(s.Kind)
+		buf.WriteByte('\t')
+		buf.WriteString(strconv.Itoa(int(s.Pos.File)))
+		buf.WriteByte('\t')
+		buf.WriteString(strconv.Itoa(int(s.Pos.Line)))
+		buf.WriteByte('\t')
+		buf.WriteString(strconv.Itoa(int(s.Pos.N)))
+		buf.WriteByte('\t')
+		buf.WriteString(symbolFieldCleaner.Replace(s.Signature))
+		buf.WriteByte('\t')
+		buf.WriteString(symbolFieldCleaner.Replace(s.Synopsis))
+		buf.WriteByte('\n')
+	}
+	return snappy.Encode(nil, buf.Bytes())
+}
+
+var errBadSymbolTable = errors.New("database: bad symbol table")
+
+// decodeSymbols decodes a symbol table created by encodeSymbols.
+func decodeSymbols(p []byte) ([]*doc.Symbol, error) {
+	if len(p) == 0 {
+		return nil, nil
+	}
+	p, err := snappy.Decode(nil, p)
+	if err != nil {
+		return nil, err
+	}
+	var symbols []*doc.Symbol
+	for _, row := range strings.Split(strings.TrimSuffix(string(p), "\n"), "\n") {
+		f := strings.Split(row, "\t")
+		if len(f) != 7 {
+			return nil, errBadSymbolTable
+		}
+		file, err1 := strconv.ParseInt(f[2], 10, 16)
+		line, err2 := strconv.ParseInt(f[3], 10, 32)
+		n, err3 := strconv.ParseUint(f[4], 10, 16)
+		if err1 != nil || err2 != nil || err3 != nil {
+			return nil, errBadSymbolTable
+		}
+		symbols = append(symbols, &doc.Symbol{
+			Name:      f[0],
+			Kind:      f[1],
+			Pos:       doc.Pos{File: int16(file), Line: int32(line), N: uint16(n)},
+			Signature: f[5],
+			Synopsis:  f[6],
+		})
+	}
+	return symbols, nil
+}
+
+// Symbols returns the symbol table of the package with the given import
+// path. The symbols are sorted by name.
+func (db *Database) Symbols(path string) ([]*doc.Symbol, error) {
+	p, err := db.Store.Symbols(path)
+	if p == nil || err != nil {
+		return nil, err
+	}
+	return decodeSymbols(p)
+}
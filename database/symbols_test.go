@@ -0,0 +1,35 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file or at
+// https://developers.google.com/open-source/licenses/bsd.
+
+package database
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/golang/gddo/doc"
+)
+
+func TestSymbolTable(t *testing.T) {
+	symbols := []*doc.Symbol{
+		{Name: "Client", Kind: "type", Signature: "type Client struct", Synopsis: "A Client is an HTTP client.", Pos: doc.Pos{File: 1, Line: 20, N: 30}},
+		{Name: "Client.Do", Kind: "method", Signature: "func (*Client).Do(req *Request) (*Response, error)", Pos: doc.Pos{Line: 7}},
+		{Name: "Get", Kind: "func", Signature: "func Get(url string)", Synopsis: "Get issues\ta GET."},
+	}
+	got, err := decodeSymbols(encodeSymbols(symbols))
+	if err != nil {
+		t.Fatal(err)
+	}
+	symbols[2].Synopsis = "Get issues a GET."
+	if diff := cmp.Diff(symbols, got); diff != "" {
+		t.Errorf("decoded symbols differ (-want +got):\n%s", diff)
+	}
+
+	if got, err := decodeSymbols(encodeSymbols(nil)); err != nil || got != nil {
+		t.Errorf("decodeSymbols(encodeSymbols(nil)) = %v, %v; want nil, nil", got, err)
+	}
+}
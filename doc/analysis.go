@@ -0,0 +1,177 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file or at
+// https://developers.google.com/open-source/licenses/bsd.
+
+package doc
+
+import (
+	"fmt"
+	"go/types"
+	"reflect"
+	"sort"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/copylock"
+	"golang.org/x/tools/go/analysis/passes/printf"
+	"golang.org/x/tools/go/analysis/passes/shadow"
+	"golang.org/x/tools/go/analysis/passes/unusedresult"
+)
+
+// Diagnostic is a problem reported by an analyzer.
+type Diagnostic struct {
+	Analyzer string
+	Pos      Pos
+	Message  string
+}
+
+var allAnalyzers = []*analysis.Analyzer{
+	copylock.Analyzer,
+	printf.Analyzer,
+	shadow.Analyzer,
+	unusedresult.Analyzer,
+}
+
+var analyzers = allAnalyzers
+
+// SetAnalyzers sets the analyzers to run when building package documents.
+// The available analyzers are copylocks, printf, shadow and unusedresult.
+func SetAnalyzers(names []string) error {
+	var list []*analysis.Analyzer
+	for _, name := range names {
+		var a *analysis.Analyzer
+		for _, aa := range allAnalyzers {
+			if aa.Name == name {
+				a = aa
+				break
+			}
+		}
+		if a == nil {
+			return fmt.Errorf("unknown analyzer %q", name)
+		}
+		list = append(list, a)
+	}
+	analyzers = list
+	return nil
+}
+
+// objectFactKey identifies a fact exported for an object.
+type objectFactKey struct {
+	obj types.Object
+	t   reflect.Type
+}
+
+// analyze runs the analyzers on the type checked package files and sets
+// pkg.Diagnostics.
+//
+// Type information is incomplete because the dependencies of the package
+// are replaced with placeholders. Facts are only available for objects of
+// the package itself, and an analyzer that panics on the incomplete
+// information is skipped along with the analyzers that require it.
+func (b *builder) analyze(pkg *Package) {
+	if b.tpkg == nil {
+		return
+	}
+	seen := make(map[Diagnostic]bool)
+	facts := make(map[objectFactKey]analysis.Fact)
+	results := make(map[*analysis.Analyzer]interface{})
+	failed := make(map[*analysis.Analyzer]bool)
+
+	var run func(a *analysis.Analyzer) bool
+	run = func(a *analysis.Analyzer) bool {
+		if _, ok := results[a]; ok {
+			return true
+		}
+		if failed[a] {
+			return false
+		}
+		resultOf := make(map[*analysis.Analyzer]interface{})
+		for _, req := range a.Requires {
+			if !run(req) {
+				failed[a] = true
+				return false
+			}
+			resultOf[req] = results[req]
+		}
+		pass := &analysis.Pass{
+			Analyzer:   a,
+			Fset:       b.fset,
+			Files:      b.tfiles,
+			Pkg:        b.tpkg,
+			TypesInfo:  b.tinfo,
+			TypesSizes: types.SizesFor("gc", "amd64"),
+			ResultOf:   resultOf,
+			Report: func(d analysis.Diagnostic) {
+				position := b.fset.Position(d.Pos)
+				src := b.srcs[position.Filename]
+				if src == nil {
+					return
+				}
+				diag := Diagnostic{
+					Analyzer: a.Name,
+					Pos:      Pos{File: int16(src.index), Line: int32(position.Line)},
+					Message:  d.Message,
+				}
+				if !seen[diag] {
+					seen[diag] = true
+					pkg.Diagnostics = append(pkg.Diagnostics, &diag)
+				}
+			},
+			ImportObjectFact: func(obj types.Object, fact analysis.Fact) bool {
+				f, ok := facts[objectFactKey{obj, reflect.TypeOf(fact)}]
+				if ok {
+					reflect.ValueOf(fact).Elem().Set(reflect.ValueOf(f).Elem())
+				}
+				return ok
+			},
+			ExportObjectFact: func(obj types.Object, fact analysis.Fact) {
+				facts[objectFactKey{obj, reflect.TypeOf(fact)}] = fact
+			},
+			ImportPackageFact: func(*types.Package, analysis.Fact) bool { return false },
+			ExportPackageFact: func(analysis.Fact) {},
+			AllObjectFacts:    func() []analysis.ObjectFact { return nil },
+			AllPackageFacts:   func() []analysis.PackageFact { return nil },
+		}
+		result, ok := runPass(pass)
+		if !ok {
+			failed[a] = true
+			return false
+		}
+		results[a] = result
+		return true
+	}
+	for _, a := range analyzers {
+		run(a)
+	}
+	sort.Sort(byDiagnosticPos(pkg.Diagnostics))
+}
+
+// runPass runs the analyzer of the pass. The analyzers assume well typed
+// packages. The result is not ok if the analyzer returns an error or
+// panics.
+func runPass(pass *analysis.Pass) (result interface{}, ok bool) {
+	defer func() {
+		if recover() != nil {
+			result, ok = nil, false
+		}
+	}()
+	result, err := pass.Analyzer.Run(pass)
+	return result, err == nil
+}
+
+type byDiagnosticPos []*Diagnostic
+
+func (p byDiagnosticPos) Len() int      { return len(p) }
+func (p byDiagnosticPos) Swap(i, j int) { p[i], p[j] = p[j], p[i] }
+func (p byDiagnosticPos) Less(i, j int) bool {
+	switch {
+	case p[i].Pos.File != p[j].Pos.File:
+		return p[i].Pos.File < p[j].Pos.File
+	case p[i].Pos.Line != p[j].Pos.Line:
+		return p[i].Pos.Line < p[j].Pos.Line
+	case p[i].Analyzer != p[j].Analyzer:
+		return p[i].Analyzer < p[j].Analyzer
+	}
+	return p[i].Message < p[j].Message
+}
@@ -0,0 +1,133 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file or at
+// https://developers.google.com/open-source/licenses/bsd.
+
+package doc
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/printf"
+	"golang.org/x/tools/go/analysis/passes/shadow"
+)
+
+const analysisSource = `package p
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"testing"
+
+	"example.com/dep"
+)
+
+type Counter struct {
+	mu sync.Mutex
+	n  int
+}
+
+func (c Counter) Value() int { return c.n }
+
+func (c *Counter) String() string { return fmt.Sprintf("%d", c.n) }
+
+func Print(name string, n int, t *testing.T) {
+	fmt.Printf("%s has %d items\n", name)
+	fmt.Printf("%d items\n", name)
+	fmt.Printf("done\n", n)
+	fmt.Println("count: %d", n)
+	fmt.Println("done\n")
+	log.Printf("%s %v %[1]s", name, n)
+	t.Errorf("%s", n)
+	fmt.Printf("%w", errors.New("x"))
+	fmt.Printf("%s", dep.Value())
+}
+
+func Shadow(names []string) error {
+	var err error
+	for _, name := range names {
+		err := check(name)
+		if err != nil {
+			return err
+		}
+		n := len(name)
+		if n > 0 {
+			n := n + 1
+			_ = n
+		}
+	}
+	return err
+}
+
+func check(name string) error { return nil }
+
+func Unused(c *Counter) {
+	fmt.Sprintf("%d", 1)
+	errors.New("x")
+	c.String()
+	dep.Value()
+}
+
+func Copy(c *Counter) {
+	d := *c
+	var e = d
+	e.n++
+	for _, x := range []Counter{} {
+		x.n++
+	}
+	fmt.Println(*c)
+}
+`
+
+func TestAnalyzers(t *testing.T) {
+	pkg := newTestPackage(t, "example.com/p", map[string]string{"p.go": analysisSource})
+	type diagnostic struct {
+		Line     int32
+		Analyzer string
+		Message  string
+	}
+	var got []diagnostic
+	for _, d := range pkg.Diagnostics {
+		got = append(got, diagnostic{d.Pos.Line, d.Analyzer, d.Message})
+	}
+	want := []diagnostic{
+		{18, "copylocks", "Value passes lock by value: example.com/p.Counter contains sync.Mutex"},
+		{23, "printf", "fmt.Printf format %d reads arg #2, but call has 1 arg"},
+		{24, "printf", "fmt.Printf format %d has arg name of wrong type string"},
+		{25, "printf", "fmt.Printf call has arguments but no formatting directives"},
+		{26, "printf", "fmt.Println call has possible Printf formatting directive %d"},
+		{27, "printf", "fmt.Println arg list ends with redundant newline"},
+		{29, "printf", "(*testing.common).Errorf format %s has arg n of wrong type int"},
+		{30, "printf", "fmt.Printf does not support error-wrapping directive %w"},
+		{37, "shadow", `declaration of "err" shadows declaration at line 35`},
+		{43, "shadow", `declaration of "n" shadows declaration at line 41`},
+		{53, "unusedresult", "result of fmt.Sprintf call not used"},
+		{54, "unusedresult", "result of errors.New call not used"},
+		{55, "unusedresult", "result of (*example.com/p.Counter).String call not used"},
+		{60, "copylocks", "assignment copies lock value to d: example.com/p.Counter contains sync.Mutex"},
+		{61, "copylocks", "variable declaration copies lock value to e: example.com/p.Counter contains sync.Mutex"},
+		{63, "copylocks", "range var x copies lock: example.com/p.Counter contains sync.Mutex"},
+		{66, "copylocks", "call of fmt.Println copies lock value: example.com/p.Counter contains sync.Mutex"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Diagnostics mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestSetAnalyzers(t *testing.T) {
+	defer func(saved []*analysis.Analyzer) { analyzers = saved }(analyzers)
+	if err := SetAnalyzers([]string{"printf", "shadow"}); err != nil {
+		t.Fatalf("SetAnalyzers returned error %v", err)
+	}
+	if len(analyzers) != 2 || analyzers[0] != printf.Analyzer || analyzers[1] != shadow.Analyzer {
+		t.Errorf("SetAnalyzers did not set the printf and shadow analyzers")
+	}
+	if err := SetAnalyzers([]string{"nilness"}); err == nil {
+		t.Errorf("SetAnalyzers with unknown analyzer did not return an error")
+	}
+}
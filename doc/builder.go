@@ -15,6 +15,7 @@ import (
 	"go/format"
 	"go/parser"
 	"go/token"
+	"go/types"
 	"regexp"
 	"sort"
 	"strings"
@@ -149,21 +150,40 @@ type builder struct {
 	fset     *token.FileSet
 	examples []*doc.Example
 	buf      []byte // scratch space for printNode method.
+	tpkg     *types.Package
+	tinfo    *types.Info
+	tfiles   []*ast.File // files passed to the type checker
+	importer *typeImporter
+	coverage []coverageItem // exported declarations
 }
 
 type Value struct {
-	Decl Code
-	Pos  Pos
-	Doc  string
+	Decl  Code
+	Pos   Pos
+	Doc   string
+	Names []string
+
+	// Values of constants computed by the type checker. Nil for variables
+	// and for constants declared with literals.
+	Evaluated []*ConstValue
+
+	// Platforms where the declaration exists or nil for all platforms.
+	Platforms []string
+
+	// Declarations by platform if the declaration or its documentation
+	// differs between platforms, otherwise nil. See PlatformDecl.
+	PlatformDecls []*PlatformDecl
 }
 
 func (b *builder) values(vdocs []*doc.Value) []*Value {
 	var result []*Value
 	for _, d := range vdocs {
 		result = append(result, &Value{
-			Decl: b.printDecl(d.Decl),
-			Pos:  b.position(d.Decl),
-			Doc:  d.Doc,
+			Decl:      b.printDecl(d.Decl),
+			Pos:       b.position(d.Decl),
+			Doc:       d.Doc,
+			Names:     d.Names,
+			Evaluated: b.constValues(d.Decl),
 		})
 	}
 	return result
@@ -257,6 +277,13 @@ type Func struct {
 	Recv     string // Actual receiver "T" or "*T".
 	Orig     string // Original receiver "T" or "*T". This can be different from Recv due to embedding.
 	Examples []*Example
+
+	// Platforms where the declaration exists or nil for all platforms.
+	Platforms []string
+
+	// Declarations by platform if the declaration or its documentation
+	// differs between platforms, otherwise nil. See PlatformDecl.
+	PlatformDecls []*PlatformDecl
 }
 
 func (b *builder) funcs(fdocs []*doc.Func) []*Func {
@@ -294,6 +321,39 @@ type Type struct {
 	Funcs    []*Func
 	Methods  []*Func
 	Examples []*Example
+
+	// Interfaces implemented by the type.
+	Implements []*TypeRef
+
+	// Types in the package that implement the interface.
+	ImplementedBy []*TypeRef
+
+	// Method signatures used to find implementations of interfaces in
+	// other packages. See methodSigs for the format.
+	MethodSigs []string
+
+	// Exported fields of a struct type.
+	Fields []*Member
+
+	// Methods and fields promoted from embedded types declared in the
+	// package or in the standard library stubs.
+	Promoted []*Member
+
+	// Embedded types declared in other packages. Their members are resolved
+	// from the documentation of those packages when the page is displayed.
+	ExternalEmbeds []*TypeRef
+
+	// Platforms where the declaration exists or nil for all platforms.
+	Platforms []string
+
+	// Declarations by platform if the declaration or its documentation
+	// differs between platforms, otherwise nil. See PlatformDecl.
+	PlatformDecls []*PlatformDecl
+
+	// Stub is true if the type documentation is stored separately from the
+	// package. A stub has the name, position, method signatures and the
+	// names of the functions and methods of the type.
+	Stub bool
 }
 
 func (b *builder) types(tdocs []*doc.Type) []*Type {
@@ -357,6 +417,14 @@ func simpleImporter(imports map[string]*ast.Object, path string) (*ast.Object, e
 type File struct {
 	Name string
 	URL  string
+
+	// Annotated contents of the file. Source.Text is empty for test files,
+	// files that cannot be parsed and large files.
+	Source Code
+
+	// HasSource is true if the builder set Source. The database stores the
+	// sources apart from the package document and clears Source.
+	HasSource bool
 }
 
 type Pos struct {
@@ -373,7 +441,7 @@ type source struct {
 }
 
 // PackageVersion is modified when previously stored packages are invalid.
-const PackageVersion = "8"
+const PackageVersion = "20"
 
 type Package struct {
 	// The import path for this package.
@@ -391,9 +459,29 @@ type Package struct {
 	// Errors found when fetching or parsing this package.
 	Errors []string
 
+	// Exported sentinel errors and error types. The field is not named
+	// Errors because Errors holds the problems found when fetching.
+	ErrorCatalog []*ErrorDecl
+
+	// Problems reported by the static analyzers.
+	Diagnostics []*Diagnostic
+
+	// Documentation coverage of the exported declarations or nil if there
+	// is no package for this import path.
+	Coverage *Coverage
+
 	// Packages referenced in README files.
 	References []string
 
+	// README file of the directory or nil if the directory does not have a
+	// README file in a supported format.
+	README *README
+
+	// SPDX identifier of the license in the license file of the directory,
+	// OtherLicense if the license is not recognized or "" if the directory
+	// does not have a license file.
+	License string
+
 	// Version control system: git, hg, bzr, ...
 	VCS string
 
@@ -428,12 +516,21 @@ type Package struct {
 	// Format this package as a command.
 	IsCmd bool
 
+	// Command line flags defined by a command.
+	Flags []*Flag
+
 	// True if package documentation is incomplete.
 	Truncated bool
 
-	// Environment
+	// Environment used for declarations that exist on all platforms.
 	GOOS, GOARCH string
 
+	// Platforms, in GOOS/GOARCH form, that the documentation is built for.
+	Platforms []string
+
+	// Exported symbols sorted by name.
+	Symbols []*Symbol
+
 	// Top-level declarations.
 	Consts []*Value
 	Funcs  []*Func
@@ -451,6 +548,12 @@ type Package struct {
 	Files     []*File
 	TestFiles []*File
 
+	// Tests, benchmarks and fuzz targets declared in the test files.
+	Tests []*TestFunc
+
+	// Go files in the directory and how they are selected for the package.
+	SourceFiles []*SourceFile
+
 	// Source size in bytes.
 	SourceSize     int
 	TestSourceSize int
@@ -466,12 +569,12 @@ var goEnvs = []struct{ GOOS, GOARCH string }{
 	{"darwin", "amd64"},
 	{"windows", "amd64"},
 	{"js", "wasm"},
-	{"linux", "js"},
 }
 
 // SetDefaultGOOS sets given GOOS value as default one to use when building
-// package documents. SetDefaultGOOS has no effect on some windows-only
-// packages.
+// package documents. The default environment is listed first in the
+// documentation and its declarations take precedence over the declarations
+// of other platforms.
 func SetDefaultGOOS(goos string) {
 	if goos == "" {
 		return
@@ -493,15 +596,6 @@ func SetDefaultGOOS(goos string) {
 	goEnvs[0], goEnvs[i] = goEnvs[i], goEnvs[0]
 }
 
-var windowsOnlyPackages = map[string]bool{
-	"internal/syscall/windows":                     true,
-	"internal/syscall/windows/registry":            true,
-	"golang.org/x/exp/shiny/driver/internal/win32": true,
-	"golang.org/x/exp/shiny/driver/windriver":      true,
-	"golang.org/x/sys/windows":                     true,
-	"golang.org/x/sys/windows/registry":            true,
-}
-
 func newPackage(dir *gosrc.Directory) (*Package, error) {
 
 	pkg := &Package{
@@ -535,14 +629,15 @@ func newPackage(dir *gosrc.Directory) (*Package, error) {
 	for r := range references {
 		pkg.References = append(pkg.References, r)
 	}
+	pkg.README = newREADME(dir)
+	pkg.License = newLicense(dir)
 
 	if len(b.srcs) == 0 {
 		return pkg, nil
 	}
 
-	b.fset = token.NewFileSet()
-
-	// Find the package and associated files.
+	// Find the package files for each environment. Environments with the
+	// same files are built together.
 
 	ctxt := build.Context{
 		GOOS:        "linux",
@@ -553,54 +648,107 @@ func newPackage(dir *gosrc.Directory) (*Package, error) {
 		Compiler:    "gc",
 	}
 
-	var err error
-	var bpkg *build.Package
-
+	var targets []*target
+	var imported []platformPackage
+	var importErr error
 	for _, env := range goEnvs {
-		// Some packages should be always displayed as GOOS=windows (see issue #16509 for details).
-		// TODO: remove this once issue #16509 is resolved.
-		if windowsOnlyPackages[dir.ImportPath] && env.GOOS != "windows" {
-			continue
-		}
-
 		ctxt.GOOS = env.GOOS
 		ctxt.GOARCH = env.GOARCH
-		bpkg, err = dir.Import(&ctxt, build.ImportComment)
-		if _, ok := err.(*build.NoGoError); !ok {
-			break
+		bpkg, err := dir.Import(&ctxt, build.ImportComment)
+		imported = append(imported, platformPackage{env.GOOS + "/" + env.GOARCH, bpkg})
+		if importErr != nil {
+			// Import the remaining environments for the file report only.
+			continue
 		}
-	}
-	if err != nil {
-		if _, ok := err.(*build.NoGoError); !ok {
-			pkg.Errors = append(pkg.Errors, err.Error())
+		if _, ok := err.(*build.NoGoError); ok {
+			continue
+		}
+		if err != nil {
+			if len(targets) == 0 {
+				importErr = err
+			}
+			continue
 		}
+		targets = addTarget(targets, bpkg, env.GOOS, env.GOARCH)
+	}
+	pkg.SourceFiles = b.sourceFiles(imported)
+	if importErr != nil {
+		pkg.Errors = append(pkg.Errors, importErr.Error())
+		return pkg, nil
+	}
+	if len(targets) == 0 {
 		return pkg, nil
 	}
+	primary := targets[0]
 
 	// Use information we have by now (import comment and resolved GitHub path)
 	// to redirect to a canonical import path, when it's possible to do so reliably.
-	err = gosrc.MaybeRedirect(dir.ImportPath, bpkg.ImportComment, dir.ResolvedGitHubPath)
+	err := gosrc.MaybeRedirect(dir.ImportPath, primary.bpkg.ImportComment, dir.ResolvedGitHubPath)
 	if err != nil {
 		return nil, err
 	}
 
-	// Parse the Go files
+	// Index the Go files of all targets so that positions in the
+	// declarations of each target refer to the same files.
 
-	files := make(map[string]*ast.File)
-	names := append(bpkg.GoFiles, bpkg.CgoFiles...)
+	var names []string
+	seen := make(map[string]bool)
+	for _, t := range targets {
+		for _, name := range t.goFiles() {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
 	sort.Strings(names)
 	pkg.Files = make([]*File, len(names))
 	for i, name := range names {
+		src := b.srcs[name]
+		src.index = i
+		pkg.Files[i] = &File{Name: name, URL: src.browseURL}
+		pkg.SourceSize += len(src.data)
+	}
+
+	pkg.GOOS, pkg.GOARCH = primary.goos, primary.goarch
+	b.build(pkg, primary.bpkg)
+	coverage := b.coverage
+	for _, t := range targets {
+		pkg.Platforms = append(pkg.Platforms, t.platforms...)
+	}
+	if len(targets) > 1 {
+		setPlatforms(pkg, primary.platforms)
+		for _, t := range targets[1:] {
+			tpkg := &Package{ImportPath: pkg.ImportPath, Files: pkg.Files}
+			tb := &builder{srcs: b.srcs}
+			tb.build(tpkg, t.bpkg)
+			setPlatforms(tpkg, t.platforms)
+			mergePackage(pkg, tpkg)
+			coverage = mergeCoverageItems(coverage, tb.coverage)
+		}
+		clearPlatforms(pkg, len(pkg.Platforms))
+	}
+	pkg.Coverage = newCoverage(coverage)
+
+	return pkg, nil
+}
+
+// build builds the documentation for the files of bpkg. The package files
+// must be set.
+func (b *builder) build(pkg *Package, bpkg *build.Package) {
+	b.fset = token.NewFileSet()
+
+	// Parse the Go files
+
+	files := make(map[string]*ast.File)
+	names := append(bpkg.GoFiles, bpkg.CgoFiles...)
+	for _, name := range names {
 		file, err := parser.ParseFile(b.fset, name, b.srcs[name].data, parser.ParseComments)
 		if err != nil {
 			pkg.Errors = append(pkg.Errors, err.Error())
 		} else {
 			files[name] = file
 		}
-		src := b.srcs[name]
-		src.index = i
-		pkg.Files[i] = &File{Name: name, URL: src.browseURL}
-		pkg.SourceSize += len(src.data)
 	}
 
 	apkg, _ := ast.NewPackage(b.fset, files, simpleImporter, nil)
@@ -616,12 +764,20 @@ func newPackage(dir *gosrc.Directory) (*Package, error) {
 			pkg.Errors = append(pkg.Errors, err.Error())
 		} else {
 			b.examples = append(b.examples, doc.Examples(file)...)
+			pkg.Tests = append(pkg.Tests, b.testFuncs(file, i)...)
 		}
 		pkg.TestFiles[i] = &File{Name: name, URL: b.srcs[name].browseURL}
 		pkg.TestSourceSize += len(b.srcs[name].data)
 	}
 
 	b.vetPackage(pkg, apkg)
+	b.typeCheck(pkg.ImportPath, files)
+	b.analyze(pkg)
+	b.annotateSource(pkg, files)
+	if bpkg.IsCommand() {
+		// Find the flags before doc.New removes the function bodies.
+		pkg.Flags = b.flags(b.tfiles)
+	}
 
 	mode := doc.Mode(0)
 	if pkg.ImportPath == "builtin" {
@@ -633,6 +789,8 @@ func newPackage(dir *gosrc.Directory) (*Package, error) {
 	if pkg.ImportPath == "builtin" {
 		removeAssociations(dpkg)
 	}
+	b.coverage = coverageItems(dpkg)
+	pkg.Symbols = b.symbols(dpkg)
 
 	pkg.Name = dpkg.Name
 	pkg.Doc = strings.TrimRight(dpkg.Doc, " \t\n\r")
@@ -640,18 +798,17 @@ func newPackage(dir *gosrc.Directory) (*Package, error) {
 
 	pkg.Examples = b.getExamples("")
 	pkg.IsCmd = bpkg.IsCommand()
-	pkg.GOOS = ctxt.GOOS
-	pkg.GOARCH = ctxt.GOARCH
 
 	pkg.Consts = b.values(dpkg.Consts)
 	pkg.Funcs = b.funcs(dpkg.Funcs)
 	pkg.Types = b.types(dpkg.Types)
+	b.implementations(pkg.ImportPath, pkg.Types)
+	b.members(pkg.Types)
 	pkg.Vars = b.values(dpkg.Vars)
 	pkg.Notes = b.notes(dpkg.Notes)
+	pkg.ErrorCatalog = b.errorCatalog(dpkg, pkg)
 
 	pkg.Imports = bpkg.Imports
 	pkg.TestImports = bpkg.TestImports
 	pkg.XTestImports = bpkg.XTestImports
-
-	return pkg, nil
 }
@@ -9,6 +9,8 @@ package doc
 import (
 	"go/ast"
 	"testing"
+
+	"github.com/golang/gddo/gosrc"
 )
 
 var badSynopsis = []string{
@@ -112,3 +114,46 @@ func TestSimpleImporter(t *testing.T) {
 		}
 	}
 }
+
+var badImportTests = []struct {
+	name string
+	src  string
+}{
+	{"trailing slash", "package p\n\nimport x \"foo/\"\n\nvar V x.T\n"},
+	{"trailing slash without name", "package p\n\nimport \"foo/\"\n\nvar V foo.T\n"},
+	{"not an identifier", "package p\n\nimport x \"example.com/a-b/\"\n\nvar V x.T\n"},
+	{"dot element", "package p\n\nimport \"example.com/.\"\n"},
+}
+
+func TestNewPackageBadImports(t *testing.T) {
+	for _, tt := range badImportTests {
+		dir := &gosrc.Directory{
+			ImportPath:  "example.com/p",
+			ProjectRoot: "example.com/p",
+			Files:       []*gosrc.File{{Name: "p.go", Data: []byte(tt.src)}},
+		}
+		pkg, err := newPackage(dir)
+		if err != nil {
+			t.Errorf("%s: newPackage returned error %v", tt.name, err)
+			continue
+		}
+		if pkg.Name != "p" {
+			t.Errorf("%s: package name is %q, want p", tt.name, pkg.Name)
+		}
+	}
+}
+
+func TestPlaceholderName(t *testing.T) {
+	for _, tt := range []struct {
+		path, name string
+	}{
+		{"example.com/user/go-name", "name"},
+		{"foo/", "foo"},
+		{"example.com/a-b/", "_"},
+		{"", "_"},
+	} {
+		if got := placeholderName(tt.path); got != tt.name {
+			t.Errorf("placeholderName(%q) = %q, want %q", tt.path, got, tt.name)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file or at
+// https://developers.google.com/open-source/licenses/bsd.
+
+package doc
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// ConstValue is the value of a constant computed by the type checker.
+type ConstValue struct {
+	Name  string
+	Value string // Go literal for the value.
+	Type  string // Type of the constant, "untyped int", "untyped string", ...
+}
+
+// maxConstValueLen is the maximum length of a string constant value.
+const maxConstValueLen = 100
+
+// constValues returns the values of the constants declared by decl. The
+// values are omitted if all constants are declared with literals.
+func (b *builder) constValues(decl *ast.GenDecl) []*ConstValue {
+	if b.tpkg == nil || decl.Tok != token.CONST {
+		return nil
+	}
+	var (
+		values   []*ConstValue
+		computed bool
+		last     []ast.Expr // values of the last spec with values
+	)
+	for _, spec := range decl.Specs {
+		vs := spec.(*ast.ValueSpec)
+		if vs.Values != nil {
+			last = vs.Values
+		}
+		for i, id := range vs.Names {
+			if id.Name == "_" {
+				continue
+			}
+			var expr ast.Expr
+			if i < len(last) {
+				expr = last[i]
+			}
+			if !isLiteral(expr) || vs.Values == nil {
+				computed = true
+			}
+			obj, ok := lookupConst(b.tpkg, id.Name)
+			if !ok {
+				continue
+			}
+			values = append(values, &ConstValue{
+				Name:  id.Name,
+				Value: formatConst(obj.Val(), expr != nil && isBitExpr(expr)),
+				Type:  types.TypeString(obj.Type(), types.RelativeTo(b.tpkg)),
+			})
+		}
+	}
+	if !computed {
+		return nil
+	}
+	return values
+}
+
+// lookupConst returns the package-level constant with the given name if
+// the type checker was able to compute its value.
+func lookupConst(pkg *types.Package, name string) (*types.Const, bool) {
+	obj, ok := pkg.Scope().Lookup(name).(*types.Const)
+	if !ok || obj.Val().Kind() == constant.Unknown {
+		return nil, false
+	}
+	return obj, true
+}
+
+// isLiteral returns true if expr is a literal, possibly negated.
+func isLiteral(expr ast.Expr) bool {
+	if u, ok := expr.(*ast.UnaryExpr); ok && (u.Op == token.SUB || u.Op == token.ADD) {
+		expr = u.X
+	}
+	_, ok := expr.(*ast.BasicLit)
+	return ok
+}
+
+// isBitExpr returns true if expr uses shifts, bitwise operators or
+// hexadecimal literals, which indicates that the value is a bit mask.
+func isBitExpr(expr ast.Expr) bool {
+	found := false
+	ast.Inspect(expr, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.BinaryExpr:
+			switch n.Op {
+			case token.SHL, token.OR, token.AND, token.AND_NOT, token.XOR:
+				found = true
+			}
+		case *ast.BasicLit:
+			if n.Kind == token.INT && (strings.HasPrefix(n.Value, "0x") || strings.HasPrefix(n.Value, "0X")) {
+				found = true
+			}
+		}
+		return !found
+	})
+	return found
+}
+
+// formatConst formats a constant value as a Go literal. Non-negative
+// integers are formatted in hexadecimal if hex is true.
+func formatConst(v constant.Value, hex bool) string {
+	switch v.Kind() {
+	case constant.Int:
+		if hex && constant.Sign(v) >= 0 {
+			if n, ok := constant.Uint64Val(v); ok {
+				return "0x" + strconv.FormatUint(n, 16)
+			}
+		}
+		return v.ExactString()
+	case constant.String:
+		s := constant.StringVal(v)
+		if len(s) > maxConstValueLen {
+			return strconv.Quote(truncateString(s, maxConstValueLen)) + "..."
+		}
+		return strconv.Quote(s)
+	}
+	return v.String()
+}
+
+// truncateString truncates s to at most n bytes without splitting a UTF-8
+// encoded rune.
+func truncateString(s string, n int) string {
+	for n > 0 && n < len(s) && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
@@ -0,0 +1,99 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file or at
+// https://developers.google.com/open-source/licenses/bsd.
+
+package doc
+
+import (
+	"go/constant"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+const constValueSource = `package p
+
+import "os"
+
+type Kind int
+
+const (
+	Invalid Kind = iota
+	Bool
+	_
+	Int
+)
+
+type Mode uint32
+
+const (
+	Read Mode = 1 << iota
+	Write
+	Exec
+	All = Read | Write | Exec
+)
+
+const (
+	Mask = 0xff
+	One  = 1
+)
+
+const Size = 4 * 1024
+
+const Name = "p" + "kg"
+
+const Perm = os.ModePerm
+
+const Pi = 3.14159
+`
+
+func TestConstValues(t *testing.T) {
+	pkg := newTestPackage(t, "example.com/p", map[string]string{"p.go": constValueSource})
+	got := make(map[string][]*ConstValue)
+	for _, v := range pkg.Consts {
+		got[v.Names[0]] = v.Evaluated
+	}
+	for _, typ := range pkg.Types {
+		for _, v := range typ.Consts {
+			got[v.Names[0]] = v.Evaluated
+		}
+	}
+	want := map[string][]*ConstValue{
+		"Invalid": {
+			{Name: "Invalid", Value: "0", Type: "Kind"},
+			{Name: "Bool", Value: "1", Type: "Kind"},
+			{Name: "Int", Value: "3", Type: "Kind"},
+		},
+		"Read": {
+			{Name: "Read", Value: "0x1", Type: "Mode"},
+			{Name: "Write", Value: "0x2", Type: "Mode"},
+			{Name: "Exec", Value: "0x4", Type: "Mode"},
+			{Name: "All", Value: "0x7", Type: "Mode"},
+		},
+		"Mask": nil,
+		"Size": {{Name: "Size", Value: "4096", Type: "untyped int"}},
+		"Name": {{Name: "Name", Value: `"pkg"`, Type: "untyped string"}},
+		"Perm": nil,
+		"Pi":   nil,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("evaluated constants differ (-want +got):\n%s", diff)
+	}
+}
+
+func TestFormatConst(t *testing.T) {
+	long := make([]byte, maxConstValueLen+10)
+	for i := range long {
+		long[i] = 'x'
+	}
+	long[maxConstValueLen-1] = 0xc3 // First byte of a two byte rune.
+	long[maxConstValueLen] = 0xa9
+	if got, want := formatConst(constant.MakeString(string(long)), false), `"`+string(long[:maxConstValueLen-1])+`"...`; got != want {
+		t.Errorf("formatConst(long string) = %s, want %s", got, want)
+	}
+	if got, want := formatConst(constant.MakeInt64(-1), true), "-1"; got != want {
+		t.Errorf("formatConst(-1, true) = %s, want %s", got, want)
+	}
+}
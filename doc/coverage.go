@@ -0,0 +1,169 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file or at
+// https://developers.google.com/open-source/licenses/bsd.
+
+package doc
+
+import (
+	"go/ast"
+	"go/doc"
+	"sort"
+)
+
+// Coverage describes how many exported declarations of a package have doc
+// comments.
+type Coverage struct {
+	Consts, Vars, Funcs, Types, Methods, Fields CoverageCount
+
+	// Exported declarations without doc comments. Methods and fields are
+	// qualified by the type name.
+	Undocumented []string
+}
+
+// CoverageCount is the number of declarations and the number of those
+// declarations with doc comments.
+type CoverageCount struct {
+	Total, Documented int
+}
+
+// Add adds the declarations counted in c2 to c.
+func (c *CoverageCount) Add(c2 CoverageCount) {
+	c.Total += c2.Total
+	c.Documented += c2.Documented
+}
+
+// Percent returns the percentage of documented declarations rounded down.
+// Percent returns 100 if there are no declarations.
+func (c CoverageCount) Percent() int {
+	if c.Total == 0 {
+		return 100
+	}
+	return 100 * c.Documented / c.Total
+}
+
+// Sum returns the count of all declarations.
+func (c *Coverage) Sum() CoverageCount {
+	var sum CoverageCount
+	for _, n := range []CoverageCount{c.Consts, c.Vars, c.Funcs, c.Types, c.Methods, c.Fields} {
+		sum.Add(n)
+	}
+	return sum
+}
+
+// Kinds of declarations counted by Coverage.
+const (
+	constDecl = iota
+	varDecl
+	funcDecl
+	typeDecl
+	methodDecl
+	fieldDecl
+)
+
+// coverageItem is an exported declaration.
+type coverageItem struct {
+	kind       int
+	name       string
+	documented bool
+}
+
+// coverageItems returns the exported declarations of dpkg.
+func coverageItems(dpkg *doc.Package) []coverageItem {
+	var items []coverageItem
+	values := func(kind int, values []*doc.Value) {
+		for _, v := range values {
+			for _, spec := range v.Decl.Specs {
+				vs := spec.(*ast.ValueSpec)
+				documented := v.Doc != "" || vs.Doc != nil || vs.Comment != nil
+				for _, id := range vs.Names {
+					if id.IsExported() {
+						items = append(items, coverageItem{kind, id.Name, documented})
+					}
+				}
+			}
+		}
+	}
+	funcs := func(funcs []*doc.Func) {
+		for _, f := range funcs {
+			if ast.IsExported(f.Name) {
+				items = append(items, coverageItem{funcDecl, f.Name, f.Doc != ""})
+			}
+		}
+	}
+
+	values(constDecl, dpkg.Consts)
+	values(varDecl, dpkg.Vars)
+	funcs(dpkg.Funcs)
+	for _, t := range dpkg.Types {
+		if ast.IsExported(t.Name) {
+			items = append(items, coverageItem{typeDecl, t.Name, t.Doc != ""})
+		}
+		values(constDecl, t.Consts)
+		values(varDecl, t.Vars)
+		funcs(t.Funcs)
+		for _, m := range t.Methods {
+			if m.Level == 0 && ast.IsExported(m.Name) {
+				items = append(items, coverageItem{methodDecl, t.Name + "." + m.Name, m.Doc != ""})
+			}
+		}
+		for _, spec := range t.Decl.Specs {
+			ts, ok := spec.(*ast.TypeSpec)
+			if !ok || ts.Name.Name != t.Name {
+				continue
+			}
+			switch typ := ts.Type.(type) {
+			case *ast.StructType:
+				for _, f := range typ.Fields.List {
+					for _, id := range f.Names {
+						if id.IsExported() {
+							items = append(items, coverageItem{fieldDecl, t.Name + "." + id.Name, f.Doc != nil || f.Comment != nil})
+						}
+					}
+				}
+			case *ast.InterfaceType:
+				for _, f := range typ.Methods.List {
+					for _, id := range f.Names {
+						if id.IsExported() {
+							items = append(items, coverageItem{methodDecl, t.Name + "." + id.Name, f.Doc != nil || f.Comment != nil})
+						}
+					}
+				}
+			}
+		}
+	}
+	return items
+}
+
+// newCoverage returns the coverage of the declarations.
+func newCoverage(items []coverageItem) *Coverage {
+	c := &Coverage{}
+	counts := []*CoverageCount{&c.Consts, &c.Vars, &c.Funcs, &c.Types, &c.Methods, &c.Fields}
+	for _, item := range items {
+		n := counts[item.kind]
+		n.Total++
+		if item.documented {
+			n.Documented++
+		} else {
+			c.Undocumented = append(c.Undocumented, item.name)
+		}
+	}
+	sort.Strings(c.Undocumented)
+	return c
+}
+
+// mergeCoverageItems appends the declarations in src that are not in dst.
+func mergeCoverageItems(dst, src []coverageItem) []coverageItem {
+	seen := make(map[string]bool)
+	for _, item := range dst {
+		seen[item.name] = true
+	}
+	for _, item := range src {
+		if !seen[item.name] {
+			seen[item.name] = true
+			dst = append(dst, item)
+		}
+	}
+	return dst
+}
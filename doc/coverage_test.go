@@ -0,0 +1,104 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file or at
+// https://developers.google.com/open-source/licenses/bsd.
+
+package doc
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+const coverageSource = `
+// Package p is documented.
+package p
+
+// Documented group.
+const (
+	A = iota
+	B
+)
+
+const (
+	C = 1 // Line comment.
+	D = 2
+	e = 3
+)
+
+var V int
+
+// F is documented.
+func F() {}
+
+func G() {}
+
+// T is documented.
+type T struct {
+	// X is documented.
+	X int
+	Y int // Line comment.
+	Z int
+	z int
+}
+
+// NewT is documented.
+func NewT() *T { return nil }
+
+func (T) M() {}
+
+// N is documented.
+func (T) N() {}
+
+func (T) n() {}
+
+type I interface {
+	// Get is documented.
+	Get() int
+	Put(int)
+}
+
+type u struct{}
+
+func (u) M() {}
+`
+
+const coverageWindowsSource = `// +build windows
+
+package p
+
+// W is documented.
+func W() {}
+
+func H() {}
+`
+
+func TestCoverage(t *testing.T) {
+	pkg := newTestPackage(t, "example.com/p", map[string]string{
+		"p.go":         coverageSource,
+		"p_windows.go": coverageWindowsSource,
+	})
+	want := &Coverage{
+		Consts:       CoverageCount{Total: 4, Documented: 3},
+		Vars:         CoverageCount{Total: 1},
+		Funcs:        CoverageCount{Total: 5, Documented: 3},
+		Types:        CoverageCount{Total: 2, Documented: 1},
+		Methods:      CoverageCount{Total: 4, Documented: 2},
+		Fields:       CoverageCount{Total: 3, Documented: 2},
+		Undocumented: []string{"D", "G", "H", "I", "I.Put", "T.M", "T.Z", "V"},
+	}
+	if diff := cmp.Diff(want, pkg.Coverage); diff != "" {
+		t.Errorf("coverage differs (-want +got):\n%s", diff)
+	}
+	if got, want := pkg.Coverage.Sum(), (CoverageCount{Total: 19, Documented: 11}); got != want {
+		t.Errorf("Sum() = %+v, want %+v", got, want)
+	}
+	if got, want := pkg.Coverage.Sum().Percent(), 57; got != want {
+		t.Errorf("Percent() = %d, want %d", got, want)
+	}
+	if got := (CoverageCount{}).Percent(); got != 100 {
+		t.Errorf("Percent() of empty count = %d, want 100", got)
+	}
+}
@@ -0,0 +1,193 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file or at
+// https://developers.google.com/open-source/licenses/bsd.
+
+package doc
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// SchemaVersion is the version of the JSON encoding of Package. The version
+// is incremented with every change to the fields of Package or the types it
+// refers to, and a migration for the change is appended to migrations.
+//
+// PackageVersion is different: it is modified when the documents must be
+// built again from the source. A change to the fields is not a reason to
+// modify PackageVersion.
+const SchemaVersion = 6
+
+// encodedPackage is the encoding of a package document. The package is the
+// JSON encoding of Package with the Go field names as member names, except
+// where the field has a json tag. See PackageSchema for the JSON schema.
+type encodedPackage struct {
+	Schema  int             `json:"schema"`
+	Package json.RawMessage `json:"package"`
+}
+
+// migrations[i] upgrades a package encoded with schema version i+1 to
+// version i+2. A migration edits the decoded JSON object of the package in
+// place. A migration for a new field sets the field from the data in the
+// document where it can; otherwise the field is left unset until the
+// package is crawled again. Stored documents are upgraded when they are
+// read and rewritten by the gddo-admin reindex command.
+var migrations = []func(pkg map[string]interface{}) error{
+	migrateSourceFiles,   // 2: SourceFiles
+	migrateLicense,       // 3: License
+	migratePlatformDecls, // 4: PlatformDecls
+	migrateMembers,       // 5: Member.Platforms
+	migrateFileSources,   // 6: File.HasSource
+}
+
+// EncodePackage returns the versioned JSON encoding of pdoc.
+func EncodePackage(pdoc *Package) ([]byte, error) {
+	p, err := json.Marshal(pdoc)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(&encodedPackage{Schema: SchemaVersion, Package: p})
+}
+
+// DecodePackage decodes a package encoded by EncodePackage with the current
+// or an earlier schema version.
+func DecodePackage(p []byte) (*Package, error) {
+	return decodePackage(p, SchemaVersion, migrations)
+}
+
+func decodePackage(p []byte, version int, migrations []func(map[string]interface{}) error) (*Package, error) {
+	var e encodedPackage
+	if err := json.Unmarshal(p, &e); err != nil {
+		return nil, err
+	}
+	if e.Schema < 1 || e.Schema > version {
+		return nil, fmt.Errorf("doc: unsupported schema version %d", e.Schema)
+	}
+	if e.Schema < version {
+		d := json.NewDecoder(bytes.NewReader(e.Package))
+		d.UseNumber()
+		var m map[string]interface{}
+		if err := d.Decode(&m); err != nil {
+			return nil, err
+		}
+		for v := e.Schema; v < version; v++ {
+			if err := migrations[v-1](m); err != nil {
+				return nil, fmt.Errorf("doc: migrating schema version %d: %v", v, err)
+			}
+		}
+		var err error
+		e.Package, err = json.Marshal(m)
+		if err != nil {
+			return nil, err
+		}
+	}
+	var pdoc Package
+	if err := json.Unmarshal(e.Package, &pdoc); err != nil {
+		return nil, err
+	}
+	return &pdoc, nil
+}
+
+// PackageSchema returns the JSON schema of the encoding returned by
+// EncodePackage.
+func PackageSchema() ([]byte, error) {
+	defs := make(map[string]interface{})
+	pkg := jsonSchema(reflect.TypeOf(Package{}), defs)
+	return json.MarshalIndent(map[string]interface{}{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"title":   "Package document",
+		"type":    "object",
+		"properties": map[string]interface{}{
+			"schema":  map[string]interface{}{"const": SchemaVersion},
+			"package": pkg,
+		},
+		"required": []string{"schema", "package"},
+		"$defs":    defs,
+	}, "", "  ")
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// jsonSchema returns the schema of the JSON encoding of t. The schemas of
+// named struct types are added to defs.
+func jsonSchema(t reflect.Type, defs map[string]interface{}) map[string]interface{} {
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Ptr:
+		return nullable(jsonSchema(t.Elem(), defs))
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return map[string]interface{}{"type": []string{"string", "null"}, "contentEncoding": "base64"}
+		}
+		return map[string]interface{}{"type": []string{"array", "null"}, "items": jsonSchema(t.Elem(), defs)}
+	case reflect.Map:
+		return map[string]interface{}{"type": []string{"object", "null"}, "additionalProperties": jsonSchema(t.Elem(), defs)}
+	case reflect.Struct:
+		if t == timeType {
+			return map[string]interface{}{"type": "string", "format": "date-time"}
+		}
+		if t.Name() == "" {
+			return structSchema(t, defs)
+		}
+		if _, ok := defs[t.Name()]; !ok {
+			defs[t.Name()] = nil // Break cycles.
+			defs[t.Name()] = structSchema(t, defs)
+		}
+		return map[string]interface{}{"$ref": "#/$defs/" + t.Name()}
+	}
+	panic("doc: no JSON schema for " + t.String())
+}
+
+func nullable(s map[string]interface{}) map[string]interface{} {
+	if typ, ok := s["type"].(string); ok {
+		n := map[string]interface{}{"type": []string{typ, "null"}}
+		for k, v := range s {
+			if k != "type" {
+				n[k] = v
+			}
+		}
+		return n
+	}
+	if _, ok := s["$ref"]; ok {
+		return map[string]interface{}{"anyOf": []interface{}{s, map[string]interface{}{"type": "null"}}}
+	}
+	return s
+}
+
+func structSchema(t reflect.Type, defs map[string]interface{}) map[string]interface{} {
+	props := make(map[string]interface{})
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+		name := f.Name
+		if tag := f.Tag.Get("json"); tag != "" {
+			if tag == "-" {
+				continue
+			}
+			if i := strings.IndexByte(tag, ','); i >= 0 {
+				tag = tag[:i]
+			}
+			if tag != "" {
+				name = tag
+			}
+		}
+		props[name] = jsonSchema(f.Type, defs)
+	}
+	return map[string]interface{}{"type": "object", "properties": props}
+}
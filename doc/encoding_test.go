@@ -0,0 +1,140 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file or at
+// https://developers.google.com/open-source/licenses/bsd.
+
+package doc
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestEncodePackage(t *testing.T) {
+	pkg := newTestPackage(t, "example.com/p", map[string]string{
+		"p.go":      symbolsSource,
+		"p_test.go": "package p\n\nimport \"testing\"\n\nfunc TestKind(t *testing.T) {}\n",
+	})
+	p, err := EncodePackage(pkg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := DecodePackage(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(pkg, got); diff != "" {
+		t.Errorf("decoded package differs (-want +got):\n%s", diff)
+	}
+
+	if _, err := DecodePackage([]byte(`{"schema":1000,"package":{}}`)); err == nil {
+		t.Error("DecodePackage did not return an error for a future schema version")
+	}
+}
+
+func TestMigrations(t *testing.T) {
+	if len(migrations) != SchemaVersion-1 {
+		t.Fatalf("%d migrations for schema version %d", len(migrations), SchemaVersion)
+	}
+
+	// Version 2 renames Title to Name and version 3 adds the Stars.
+	migrations := []func(map[string]interface{}) error{
+		func(m map[string]interface{}) error {
+			m["Name"] = m["Title"]
+			delete(m, "Title")
+			return nil
+		},
+		func(m map[string]interface{}) error {
+			if m["Name"] == nil {
+				return errors.New("no name")
+			}
+			m["Stars"] = 7
+			return nil
+		},
+	}
+	got, err := decodePackage([]byte(`{"schema":1,"package":{"Title":"p","ImportPath":"example.com/p"}}`), 3, migrations)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &Package{Name: "p", ImportPath: "example.com/p", Stars: 7}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("migrated package differs (-want +got):\n%s", diff)
+	}
+
+	got, err = decodePackage([]byte(`{"schema":2,"package":{"Name":"p","Stars":1}}`), 3, migrations)
+	if err != nil || got.Stars != 7 {
+		t.Errorf("decoding version 2 returned %v, %v; want 7 stars", got, err)
+	}
+
+	_, err = decodePackage([]byte(`{"schema":2,"package":{}}`), 3, migrations)
+	if err == nil || !strings.Contains(err.Error(), "no name") {
+		t.Errorf("decoding failed migration returned error %v", err)
+	}
+}
+
+func TestDecodeSchema1(t *testing.T) {
+	// A document stored with the first schema version is upgraded by all
+	// of the migrations.
+	got, err := DecodePackage([]byte(`{"schema":1,"package":{"ImportPath":"example.com/p","Name":"p","Files":[{"Name":"p.go","Source":{"Text":"package p"}}],"Types":[{"Name":"T","Fields":[{"Name":"F","Text":"F int"}]}]}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &Package{
+		ImportPath:  "example.com/p",
+		Name:        "p",
+		Files:       []*File{{Name: "p.go"}},
+		SourceFiles: []*SourceFile{{Name: "p.go", Package: "p"}},
+		Types:       []*Type{{Name: "T", Fields: []*Member{{Name: "F", Text: "F int"}}}},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("decoded package differs (-want +got):\n%s", diff)
+	}
+}
+
+func TestPackageSchema(t *testing.T) {
+	p, err := PackageSchema()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var schema struct {
+		Defs map[string]struct {
+			Properties map[string]json.RawMessage `json:"properties"`
+		} `json:"$defs"`
+	}
+	if err := json.Unmarshal(p, &schema); err != nil {
+		t.Fatal(err)
+	}
+
+	// Every member of an encoded package is in the schema.
+	pkg := &Package{
+		Types:        []*Type{{Funcs: []*Func{{}}}},
+		ErrorCatalog: []*ErrorDecl{{}},
+	}
+	p, err = json.Marshal(pkg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	check := func(def string, p []byte) {
+		var m map[string]json.RawMessage
+		if err := json.Unmarshal(p, &m); err != nil {
+			t.Fatal(err)
+		}
+		for name := range m {
+			if _, ok := schema.Defs[def].Properties[name]; !ok {
+				t.Errorf("%s.%s not in schema", def, name)
+			}
+		}
+	}
+	check("Package", p)
+	p, _ = json.Marshal(pkg.Types[0])
+	check("Type", p)
+	p, _ = json.Marshal(pkg.Types[0].Funcs[0])
+	check("Func", p)
+	p, _ = json.Marshal(pkg.ErrorCatalog[0])
+	check("ErrorDecl", p)
+}
@@ -0,0 +1,214 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file or at
+// https://developers.google.com/open-source/licenses/bsd.
+
+package doc
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/doc"
+	"go/types"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ErrorDecl is an exported error declared by a package: a sentinel error
+// variable or a type that implements the error interface.
+type ErrorDecl struct {
+	Name string `json:"name"`
+
+	// Kind is "var" for sentinel errors and "type" for error types.
+	Kind string `json:"kind"`
+
+	// Message passed to errors.New or fmt.Errorf to create a sentinel
+	// error or "" if the message is not known.
+	Message string `json:"message,omitempty"`
+
+	// True if only the pointer type *Name implements error.
+	Ptr bool `json:"ptr,omitempty"`
+
+	// Synopsis of the declaration documentation.
+	Synopsis string `json:"synopsis,omitempty"`
+
+	// Functions and methods, in Name or Type.Name form, that mention the
+	// error in their documentation.
+	MentionedBy []string `json:"mentionedBy,omitempty"`
+
+	Pos Pos `json:"pos"`
+}
+
+// errorFuncs are the functions that create sentinel errors with a message.
+var errorFuncs = map[string]map[string]bool{
+	"errors":                      {"New": true},
+	"fmt":                         {"Errorf": true},
+	"golang.org/x/xerrors":        {"New": true, "Errorf": true},
+	"github.com/pkg/errors":       {"New": true, "Errorf": true},
+	"github.com/go-errors/errors": {"New": true, "Errorf": true},
+}
+
+// errorCatalog returns the exported errors declared by dpkg. The functions
+// and types of pkg must be set.
+func (b *builder) errorCatalog(dpkg *doc.Package, pkg *Package) []*ErrorDecl {
+	if b.tpkg == nil {
+		return nil
+	}
+	errorType := types.Universe.Lookup("error").Type()
+
+	var errs []*ErrorDecl
+	values := func(values []*doc.Value) {
+		for _, v := range values {
+			for _, spec := range v.Decl.Specs {
+				vs := spec.(*ast.ValueSpec)
+				for i, id := range vs.Names {
+					if !id.IsExported() {
+						continue
+					}
+					obj, ok := b.tpkg.Scope().Lookup(id.Name).(*types.Var)
+					if !ok {
+						continue
+					}
+					var message string
+					isError := isValidType(obj.Type()) && types.AssignableTo(obj.Type(), errorType)
+					if i < len(vs.Values) {
+						// The type of errors created with functions in
+						// packages that are not type checked is not known.
+						var ok bool
+						message, ok = b.errorMessage(vs.Values[i])
+						isError = isError || ok
+					}
+					if !isError {
+						continue
+					}
+					e := &ErrorDecl{
+						Name:     id.Name,
+						Kind:     "var",
+						Message:  message,
+						Synopsis: synopsis(v.Doc),
+						Pos:      b.position(vs),
+					}
+					if vs.Doc != nil {
+						e.Synopsis = synopsis(vs.Doc.Text())
+					}
+					errs = append(errs, e)
+				}
+			}
+		}
+	}
+	values(dpkg.Vars)
+	for _, t := range dpkg.Types {
+		values(t.Vars)
+	}
+
+	for _, t := range pkg.Types {
+		if n := b.lookupType(t.Name); n == nil || types.IsInterface(n) {
+			continue
+		}
+		for _, ref := range t.Implements {
+			if ref.Path == "" && ref.Name == "error" {
+				errs = append(errs, &ErrorDecl{
+					Name:     t.Name,
+					Kind:     "type",
+					Ptr:      ref.Ptr,
+					Synopsis: synopsis(t.Doc),
+					Pos:      t.Pos,
+				})
+			}
+		}
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+
+	mentions := func(name, text string) {
+		for _, e := range errs {
+			if containsWord(text, e.Name) {
+				e.MentionedBy = append(e.MentionedBy, name)
+			}
+		}
+	}
+	for _, f := range pkg.Funcs {
+		mentions(f.Name, f.Doc)
+	}
+	for _, t := range pkg.Types {
+		for _, f := range t.Funcs {
+			mentions(f.Name, f.Doc)
+		}
+		for _, m := range t.Methods {
+			mentions(t.Name+"."+m.Name, m.Doc)
+		}
+	}
+
+	sort.Slice(errs, func(i, j int) bool { return errs[i].Name < errs[j].Name })
+	return errs
+}
+
+// errorMessage returns the message of the error created by expr. The
+// returned bool is true if expr is a call to a function in errorFuncs. The
+// message is "" if it is not constant or if it is formatted with arguments.
+func (b *builder) errorMessage(expr ast.Expr) (string, bool) {
+	call, ok := expr.(*ast.CallExpr)
+	if !ok || len(call.Args) == 0 {
+		return "", false
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return "", false
+	}
+	x, ok := sel.X.(*ast.Ident)
+	if !ok {
+		return "", false
+	}
+	pn, ok := b.tinfo.Uses[x].(*types.PkgName)
+	if !ok || !errorFuncs[pn.Imported().Path()][sel.Sel.Name] {
+		return "", false
+	}
+	tv := b.tinfo.Types[call.Args[0]]
+	if len(call.Args) > 1 || tv.Value == nil || tv.Value.Kind() != constant.String {
+		return "", true
+	}
+	return constant.StringVal(tv.Value), true
+}
+
+// containsWord returns true if s contains word delimited by characters that
+// cannot be part of an identifier.
+func containsWord(s, word string) bool {
+	for {
+		i := strings.Index(s, word)
+		if i < 0 {
+			return false
+		}
+		before, _ := utf8.DecodeLastRuneInString(s[:i])
+		after, _ := utf8.DecodeRuneInString(s[i+len(word):])
+		if !isIdentRune(before) && !isIdentRune(after) {
+			return true
+		}
+		s = s[i+len(word):]
+	}
+}
+
+func isIdentRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// mergeErrorCatalog appends the errors in src that are not in dst.
+func mergeErrorCatalog(dst, src []*ErrorDecl) []*ErrorDecl {
+	seen := make(map[string]bool)
+	for _, e := range dst {
+		seen[e.Name] = true
+	}
+	n := len(dst)
+	for _, e := range src {
+		if !seen[e.Name] {
+			dst = append(dst, e)
+		}
+	}
+	if len(dst) > n {
+		sort.Slice(dst, func(i, j int) bool { return dst[i].Name < dst[j].Name })
+	}
+	return dst
+}
@@ -0,0 +1,78 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file or at
+// https://developers.google.com/open-source/licenses/bsd.
+
+package doc
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+const errorsSource = `package p
+
+import (
+	"errors"
+	"fmt"
+
+	pkgerrors "github.com/pkg/errors"
+)
+
+// Errors returned by Open.
+var (
+	// ErrNotFound is returned when the file does not exist.
+	ErrNotFound = errors.New("p: not " + "found")
+	ErrClosed   = fmt.Errorf("p: closed %d", 1)
+	errPrivate  = errors.New("private")
+)
+
+var ErrWrapped = pkgerrors.New("wrapped")
+
+var ErrCustom error = &PathError{}
+
+var NotAnError = 1
+
+// PathError records an error and the path.
+type PathError struct{ Path string }
+
+func (e *PathError) Error() string { return e.Path }
+
+type Code int
+
+func (c Code) Error() string { return "code" }
+
+type Checker interface {
+	error
+	Check()
+}
+
+// Open opens the file. It returns ErrNotFound or a *PathError.
+func Open() {}
+
+// Close returns ErrClosed if the file is closed. ErrNotFoundX is unrelated.
+func (e *PathError) Close() {}
+`
+
+func TestErrorCatalog(t *testing.T) {
+	pkg := newTestPackage(t, "example.com/p", map[string]string{"p.go": errorsSource})
+	for _, e := range pkg.ErrorCatalog {
+		if e.Pos.Line == 0 {
+			t.Errorf("error %s has no position", e.Name)
+		}
+		e.Pos = Pos{}
+	}
+	want := []*ErrorDecl{
+		{Name: "Code", Kind: "type"},
+		{Name: "ErrClosed", Kind: "var", Synopsis: "Errors returned by Open.", MentionedBy: []string{"PathError.Close"}},
+		{Name: "ErrCustom", Kind: "var"},
+		{Name: "ErrNotFound", Kind: "var", Message: "p: not found", Synopsis: "ErrNotFound is returned when the file does not exist.", MentionedBy: []string{"Open"}},
+		{Name: "ErrWrapped", Kind: "var", Message: "wrapped"},
+		{Name: "PathError", Kind: "type", Ptr: true, Synopsis: "PathError records an error and the path.", MentionedBy: []string{"Open"}},
+	}
+	if diff := cmp.Diff(want, pkg.ErrorCatalog); diff != "" {
+		t.Errorf("error catalog differs (-want +got):\n%s", diff)
+	}
+}
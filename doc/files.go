@@ -0,0 +1,283 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file or at
+// https://developers.google.com/open-source/licenses/bsd.
+
+package doc
+
+import (
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/build/constraint"
+	"go/parser"
+	"go/token"
+	"sort"
+	"strings"
+)
+
+// SourceFile reports how a Go file in the package directory is selected for
+// the package.
+type SourceFile struct {
+	Name string
+	URL  string
+
+	// Package name in the package clause or "" if the file does not parse.
+	Package string
+
+	// Build constraint from the //go:build or // +build lines in the file
+	// header, in //go:build form.
+	Constraint string
+
+	// Constraint implied by a GOOS or GOARCH suffix of the file name, for
+	// example "windows" or "linux && arm64".
+	NameConstraint string
+
+	// Platforms where the file is part of the package. The platforms are
+	// those the documentation is built for.
+	Platforms []string
+
+	// Reasons the file is excluded from the other platforms or "" if the
+	// file is part of the package on all platforms.
+	Reason string
+
+	Test bool
+
+	// Commands in //go:generate directives and patterns in //go:embed
+	// directives.
+	Generate []string
+	Embed    []string
+}
+
+// platformPackage is a package imported for a platform in GOOS/GOARCH form.
+type platformPackage struct {
+	platform string
+	bpkg     *build.Package
+}
+
+// sourceFiles returns the report of the Go files for the packages imported
+// for each platform.
+func (b *builder) sourceFiles(pkgs []platformPackage) []*SourceFile {
+	var name string
+	for _, p := range pkgs {
+		if p.bpkg.Name != "" {
+			name = p.bpkg.Name
+			break
+		}
+	}
+
+	var files []*SourceFile
+	fset := token.NewFileSet()
+	for _, src := range b.srcs {
+		sf := &SourceFile{
+			Name: src.name,
+			URL:  src.browseURL,
+			Test: strings.HasSuffix(src.name, "_test.go"),
+		}
+		sf.NameConstraint = nameConstraint(src.name)
+		var parseErr error
+		if file, err := parser.ParseFile(fset, src.name, src.data, parser.PackageClauseOnly|parser.ParseComments); err != nil {
+			parseErr = err
+		} else {
+			sf.Package = file.Name.Name
+			sf.Constraint = fileConstraint(file)
+		}
+		sf.Generate, sf.Embed = directives(src.data)
+
+		// Reasons in order and the platforms they apply to. Reasons that
+		// do not depend on the platform have no platforms.
+		var reasons []string
+		platforms := make(map[string][]string)
+		addReason := func(r, platform string) {
+			if _, ok := platforms[r]; !ok {
+				reasons = append(reasons, r)
+				platforms[r] = nil
+			}
+			if platform != "" {
+				platforms[r] = append(platforms[r], platform)
+			}
+		}
+		for _, p := range pkgs {
+			// The go/build package lists invalid files with the valid
+			// files.
+			invalid := containsString(p.bpkg.InvalidGoFiles, src.name)
+			if !invalid && (containsString(p.bpkg.GoFiles, src.name) ||
+				containsString(p.bpkg.CgoFiles, src.name) ||
+				containsString(p.bpkg.TestGoFiles, src.name) ||
+				containsString(p.bpkg.XTestGoFiles, src.name)) {
+				sf.Platforms = append(sf.Platforms, p.platform)
+				continue
+			}
+			i := strings.IndexByte(p.platform, '/')
+			goos, goarch := p.platform[:i], p.platform[i+1:]
+			switch {
+			case strings.HasPrefix(src.name, "_") || strings.HasPrefix(src.name, "."):
+				addReason("file name begins with _ or .", "")
+			case parseErr != nil:
+				addReason(parseErr.Error(), "")
+			case name != "" && sf.Package != name && !(sf.Test && sf.Package == name+"_test"):
+				addReason("package "+sf.Package+" differs from package "+name, "")
+			case !matchFileName(src.name, goos, goarch):
+				addReason("file name suffix excludes", p.platform)
+			case invalid:
+				addReason("invalid file", "")
+			default:
+				addReason("build constraint excludes", p.platform)
+			}
+		}
+		for i, r := range reasons {
+			if p := platforms[r]; p != nil {
+				reasons[i] = r + " " + strings.Join(p, ", ")
+			}
+		}
+		sf.Reason = strings.Join(reasons, "; ")
+		files = append(files, sf)
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].Name < files[j].Name })
+	return files
+}
+
+// fileConstraint returns the build constraint in the header of file.
+func fileConstraint(file *ast.File) string {
+	var plusBuild []string
+	for _, cg := range file.Comments {
+		if cg.Pos() >= file.Package {
+			break
+		}
+		for _, c := range cg.List {
+			if constraint.IsGoBuild(c.Text) {
+				if x, err := constraint.Parse(c.Text); err == nil {
+					return x.String()
+				}
+				return strings.TrimSpace(strings.TrimPrefix(c.Text, "//go:build"))
+			}
+			if constraint.IsPlusBuild(c.Text) {
+				plusBuild = append(plusBuild, c.Text)
+			}
+		}
+	}
+	var exprs []string
+	for _, line := range plusBuild {
+		x, err := constraint.Parse(line)
+		if err != nil {
+			continue
+		}
+		s := x.String()
+		if len(plusBuild) > 1 {
+			if _, ok := x.(*constraint.OrExpr); ok {
+				s = "(" + s + ")"
+			}
+		}
+		exprs = append(exprs, s)
+	}
+	return strings.Join(exprs, " && ")
+}
+
+// directives returns the arguments of the //go:generate and //go:embed
+// directives in src.
+func directives(src []byte) (generate, embed []string) {
+	for _, line := range strings.Split(string(src), "\n") {
+		line = strings.TrimSuffix(line, "\r")
+		if s := strings.TrimPrefix(line, "//go:generate "); s != line {
+			generate = append(generate, strings.TrimSpace(s))
+		} else if s := strings.TrimPrefix(line, "//go:embed "); s != line {
+			embed = append(embed, strings.TrimSpace(s))
+		}
+	}
+	return generate, embed
+}
+
+// The GOOS and GOARCH values recognized in file names by go/build.
+var (
+	knownOS = map[string]bool{
+		"aix": true, "android": true, "darwin": true, "dragonfly": true,
+		"freebsd": true, "hurd": true, "illumos": true, "ios": true,
+		"js": true, "linux": true, "nacl": true, "netbsd": true,
+		"openbsd": true, "plan9": true, "solaris": true, "wasip1": true,
+		"windows": true, "zos": true,
+	}
+	knownArch = map[string]bool{
+		"386": true, "amd64": true, "amd64p32": true, "arm": true,
+		"armbe": true, "arm64": true, "arm64be": true, "loong64": true,
+		"mips": true, "mipsle": true, "mips64": true, "mips64le": true,
+		"mips64p32": true, "mips64p32le": true, "ppc": true, "ppc64": true,
+		"ppc64le": true, "riscv": true, "riscv64": true, "s390": true,
+		"s390x": true, "sparc": true, "sparc64": true, "wasm": true,
+	}
+)
+
+// fileNameOSArch returns the GOOS and GOARCH in the name of a Go file using
+// the rules of go/build.
+func fileNameOSArch(name string) (goos, goarch string) {
+	name = strings.TrimSuffix(name, ".go")
+	name = strings.TrimSuffix(name, "_test")
+	i := strings.IndexByte(name, '_')
+	if i < 0 {
+		return "", ""
+	}
+	l := strings.Split(name[i:], "_")
+	n := len(l)
+	if n >= 2 && knownOS[l[n-2]] && knownArch[l[n-1]] {
+		return l[n-2], l[n-1]
+	}
+	if knownOS[l[n-1]] {
+		return l[n-1], ""
+	}
+	if knownArch[l[n-1]] {
+		return "", l[n-1]
+	}
+	return "", ""
+}
+
+func nameConstraint(name string) string {
+	goos, goarch := fileNameOSArch(name)
+	switch {
+	case goos != "" && goarch != "":
+		return goos + " && " + goarch
+	case goos != "":
+		return goos
+	}
+	return goarch
+}
+
+// migrateSourceFiles sets SourceFiles of a package encoded before the field
+// was added from the files of the package. Constraints other than the file
+// name are not known and the files are reported as part of the package on
+// all platforms.
+func migrateSourceFiles(pkg map[string]interface{}) error {
+	var files []interface{}
+	for _, key := range []string{"Files", "TestFiles"} {
+		list, _ := pkg[key].([]interface{})
+		for _, v := range list {
+			f, ok := v.(map[string]interface{})
+			if !ok {
+				return fmt.Errorf("%s is not a list of files", key)
+			}
+			name, _ := f["Name"].(string)
+			files = append(files, map[string]interface{}{
+				"Name":           name,
+				"URL":            f["URL"],
+				"Package":        pkg["Name"],
+				"NameConstraint": nameConstraint(name),
+				"Test":           key == "TestFiles",
+			})
+		}
+	}
+	pkg["SourceFiles"] = files
+	return nil
+}
+
+func matchFileName(name, goos, goarch string) bool {
+	fos, farch := fileNameOSArch(name)
+	return (fos == "" || fos == goos) && (farch == "" || farch == goarch)
+}
+
+func containsString(a []string, s string) bool {
+	for _, e := range a {
+		if e == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,107 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file or at
+// https://developers.google.com/open-source/licenses/bsd.
+
+package doc
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/golang/gddo/gosrc"
+)
+
+func TestSourceFiles(t *testing.T) {
+	pkg := newTestPackage(t, "example.com/p", map[string]string{
+		"p.go": `package p
+
+//go:generate stringer -type=Kind
+//go:embed testdata/*.txt
+var files string
+
+type Kind int
+`,
+		"p_windows.go": "package p\n\nfunc F() {}\n",
+		"p_unix.go":    "//go:build linux || darwin\n\npackage p\n\nfunc F() {}\n",
+		"p_arm64.go":   "package p\n",
+		"old.go":       "// +build ignore\n\npackage main\n",
+		"_x.go":        "package p\n",
+		"p_test.go":    "package p_test\n",
+	})
+	all := []string{"linux/amd64", "darwin/amd64", "windows/amd64", "js/wasm"}
+	want := []*SourceFile{
+		{Name: "_x.go", Package: "p", Reason: "file name begins with _ or ."},
+		{Name: "old.go", Package: "main", Constraint: "ignore", Reason: "package main differs from package p"},
+		{Name: "p.go", Package: "p", Platforms: all, Generate: []string{"stringer -type=Kind"}, Embed: []string{"testdata/*.txt"}},
+		{Name: "p_arm64.go", Package: "p", NameConstraint: "arm64", Reason: "file name suffix excludes linux/amd64, darwin/amd64, windows/amd64, js/wasm"},
+		{Name: "p_test.go", Package: "p_test", Platforms: all, Test: true},
+		{Name: "p_unix.go", Package: "p", Constraint: "linux || darwin", Platforms: []string{"linux/amd64", "darwin/amd64"}, Reason: "build constraint excludes windows/amd64, js/wasm"},
+		{Name: "p_windows.go", Package: "p", NameConstraint: "windows", Platforms: []string{"windows/amd64"}, Reason: "file name suffix excludes linux/amd64, darwin/amd64, js/wasm"},
+	}
+	if diff := cmp.Diff(want, pkg.SourceFiles); diff != "" {
+		t.Errorf("source files differ (-want +got):\n%s", diff)
+	}
+}
+
+func TestSourceFilesMultiplePackages(t *testing.T) {
+	// The files are listed in order because the package of the first file
+	// is the package of the directory.
+	pkg, err := newPackage(&gosrc.Directory{
+		ImportPath:  "example.com/p",
+		ProjectRoot: "example.com/p",
+		Files: []*gosrc.File{
+			{Name: "a.go", Data: []byte("package p\n")},
+			{Name: "b.go", Data: []byte("package q\n")},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pkg.Errors) == 0 {
+		t.Error("no error for multiple packages")
+	}
+	if len(pkg.SourceFiles) != 2 {
+		t.Fatalf("got %d source files, want 2", len(pkg.SourceFiles))
+	}
+	if got, want := pkg.SourceFiles[1].Reason, "package q differs from package p"; got != want {
+		t.Errorf("reason = %q, want %q", got, want)
+	}
+}
+
+func TestFileConstraint(t *testing.T) {
+	for _, tt := range []struct {
+		name, src, want string
+	}{
+		{"a_linux_arm64.go", "package p\n", ""},
+		{"a.go", "// +build linux darwin\n// +build cgo\n\npackage p\n", "(linux || darwin) && cgo"},
+		{"a.go", "// Package p does things.\n//go:build ignore\npackage p\n", "ignore"},
+		{"a.go", "package p\n\n//go:build linux\n", ""},
+	} {
+		pkg := &builder{srcs: map[string]*source{tt.name: {name: tt.name, data: []byte(tt.src)}}}
+		files := pkg.sourceFiles(nil)
+		if files[0].Constraint != tt.want {
+			t.Errorf("constraint of %q = %q, want %q", tt.src, files[0].Constraint, tt.want)
+		}
+	}
+	if got := nameConstraint("a_linux_arm64_test.go"); got != "linux && arm64" {
+		t.Errorf("nameConstraint = %q, want linux && arm64", got)
+	}
+}
+
+func TestMigrateSourceFiles(t *testing.T) {
+	got, err := DecodePackage([]byte(`{"schema":1,"package":{"Name":"p","Files":[{"Name":"p.go","URL":"u/p.go"},{"Name":"p_windows.go"}],"TestFiles":[{"Name":"p_test.go"}]}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*SourceFile{
+		{Name: "p.go", URL: "u/p.go", Package: "p"},
+		{Name: "p_windows.go", Package: "p", NameConstraint: "windows"},
+		{Name: "p_test.go", Package: "p", Test: true},
+	}
+	if diff := cmp.Diff(want, got.SourceFiles); diff != "" {
+		t.Errorf("source files differ (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,452 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file or at
+// https://developers.google.com/open-source/licenses/bsd.
+
+package doc
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// Flag is a command line flag defined by a command.
+type Flag struct {
+	Name      string
+	Shorthand string // One letter abbreviation or "".
+	Type      string // Type or argument name; "" for boolean flags.
+	Default   string // Go expression for a non-zero default value or "".
+	Usage     string
+
+	// Name of the subcommand or flag set that defines the flag; "" for the
+	// flags of the command.
+	FlagSet string
+
+	// Flag is defined with github.com/spf13/pflag and uses two dashes.
+	Pflag bool
+
+	Pos Pos
+}
+
+const (
+	flagPath  = "flag"
+	pflagPath = "github.com/spf13/pflag"
+	cobraPath = "github.com/spf13/cobra"
+)
+
+// flagTypes maps the type in the name of a flag definition function to the
+// type printed by the flag packages.
+var flagTypes = map[string]string{
+	"Bool":           "",
+	"BoolFunc":       "",
+	"BoolSlice":      "bools",
+	"BytesBase64":    "bytesBase64",
+	"BytesHex":       "bytesHex",
+	"Count":          "",
+	"Duration":       "duration",
+	"DurationSlice":  "durations",
+	"Float32":        "float",
+	"Float32Slice":   "floats",
+	"Float64":        "float",
+	"Float64Slice":   "floats",
+	"Func":           "value",
+	"IP":             "ip",
+	"IPMask":         "ipMask",
+	"IPNet":          "ipNet",
+	"IPSlice":        "ips",
+	"Int":            "int",
+	"Int8":           "int",
+	"Int16":          "int",
+	"Int32":          "int",
+	"Int32Slice":     "ints",
+	"Int64":          "int",
+	"Int64Slice":     "ints",
+	"IntSlice":       "ints",
+	"String":         "string",
+	"StringArray":    "strings",
+	"StringSlice":    "strings",
+	"StringToInt":    "stringToInt",
+	"StringToInt64":  "stringToInt64",
+	"StringToString": "stringToString",
+	"Text":           "value",
+	"Uint":           "uint",
+	"Uint8":          "uint",
+	"Uint16":         "uint",
+	"Uint32":         "uint",
+	"Uint64":         "uint",
+	"UintSlice":      "uints",
+}
+
+// zeroDefaults are the default values that the flag packages do not print.
+var zeroDefaults = map[string]bool{"false": true, "0": true, `""`: true, "nil": true}
+
+// flagFunc describes a flag definition function.
+type flagFunc struct {
+	typ       string // key in flagTypes
+	pointer   bool   // first argument is a pointer to the variable
+	value     bool   // first argument is a flag.Value
+	shorthand bool   // name is followed by a shorthand
+}
+
+// parseFlagFunc parses the name of a flag definition function such as
+// String, StringVar, StringP or StringVarP.
+func parseFlagFunc(name string) (flagFunc, bool) {
+	var f flagFunc
+	if _, ok := flagTypes[name]; !ok && strings.HasSuffix(name, "P") {
+		f.shorthand = true
+		name = strings.TrimSuffix(name, "P")
+	}
+	if name == "Var" {
+		f.value = true
+		return f, true
+	}
+	if _, ok := flagTypes[name]; !ok && strings.HasSuffix(name, "Var") {
+		f.pointer = true
+		name = strings.TrimSuffix(name, "Var")
+	}
+	f.typ = name
+	switch name {
+	case "Text":
+		return f, f.pointer && !f.shorthand
+	case "Func", "BoolFunc":
+		return f, !f.pointer && !f.shorthand
+	}
+	_, ok := flagTypes[name]
+	return f, ok
+}
+
+// flagFinder finds the flags defined by a command.
+type flagFinder struct {
+	b       *builder
+	imports map[string]string // local name to import path in the current file
+
+	// Flag sets and cobra commands by variable. The variables are keyed by
+	// types.Object when type information is available and by name otherwise.
+	flagSets map[interface{}]string
+	commands map[interface{}]string
+	root     map[string]bool // names of commands that are executed
+
+	flags []*Flag
+}
+
+// flags returns the flags defined in the files of a command.
+func (b *builder) flags(files []*ast.File) []*Flag {
+	f := &flagFinder{
+		b:        b,
+		flagSets: make(map[interface{}]string),
+		commands: make(map[interface{}]string),
+		root:     make(map[string]bool),
+	}
+	f.walk(files, f.findSets)
+	f.walk(files, f.findFlags)
+	for _, flag := range f.flags {
+		if f.root[flag.FlagSet] {
+			flag.FlagSet = ""
+		}
+	}
+	return f.flags
+}
+
+func (f *flagFinder) walk(files []*ast.File, fn func(ast.Node) bool) {
+	for _, file := range files {
+		f.imports = make(map[string]string)
+		for _, is := range file.Imports {
+			p, err := strconv.Unquote(is.Path.Value)
+			if err != nil {
+				continue
+			}
+			name := path.Base(p)
+			if is.Name != nil {
+				name = is.Name.Name
+			}
+			f.imports[name] = p
+		}
+		ast.Inspect(file, fn)
+	}
+}
+
+// key returns the key for the variable referenced by id.
+func (f *flagFinder) key(id *ast.Ident) interface{} {
+	if f.b.tinfo != nil {
+		if obj := f.b.tinfo.Defs[id]; obj != nil {
+			return obj
+		}
+		if obj := f.b.tinfo.Uses[id]; obj != nil {
+			return obj
+		}
+	}
+	return id.Name
+}
+
+// importPath returns the import path of the package selected by expr or "".
+func (f *flagFinder) importPath(expr ast.Expr) string {
+	id, ok := expr.(*ast.Ident)
+	if !ok {
+		return ""
+	}
+	if f.b.tinfo != nil {
+		if pn, ok := f.b.tinfo.Uses[id].(*types.PkgName); ok {
+			return pn.Imported().Path()
+		}
+	}
+	return f.imports[id.Name]
+}
+
+// findSets records the variables assigned flag sets and cobra commands.
+func (f *flagFinder) findSets(n ast.Node) bool {
+	var lhs, rhs []ast.Expr
+	switch n := n.(type) {
+	case *ast.AssignStmt:
+		lhs, rhs = n.Lhs, n.Rhs
+	case *ast.ValueSpec:
+		for _, id := range n.Names {
+			lhs = append(lhs, id)
+		}
+		rhs = n.Values
+	default:
+		return true
+	}
+	if len(lhs) != len(rhs) {
+		return true
+	}
+	for i, expr := range rhs {
+		id, ok := lhs[i].(*ast.Ident)
+		if !ok || id.Name == "_" {
+			continue
+		}
+		if name, ok := f.newFlagSet(expr); ok {
+			if name == "" {
+				name = id.Name
+			}
+			f.flagSets[f.key(id)] = name
+		} else if name, ok := f.newCommand(expr); ok {
+			if name == "" {
+				name = id.Name
+			}
+			f.commands[f.key(id)] = name
+		}
+	}
+	return true
+}
+
+// newFlagSet returns the name of the flag set created by a call to
+// flag.NewFlagSet or pflag.NewFlagSet.
+func (f *flagFinder) newFlagSet(expr ast.Expr) (string, bool) {
+	call, ok := expr.(*ast.CallExpr)
+	if !ok || len(call.Args) == 0 {
+		return "", false
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != "NewFlagSet" {
+		return "", false
+	}
+	if p := f.importPath(sel.X); p != flagPath && p != pflagPath {
+		return "", false
+	}
+	name, _ := f.stringValue(call.Args[0])
+	return name, true
+}
+
+// newCommand returns the name of the command created by a cobra.Command
+// composite literal.
+func (f *flagFinder) newCommand(expr ast.Expr) (string, bool) {
+	if u, ok := expr.(*ast.UnaryExpr); ok && u.Op == token.AND {
+		expr = u.X
+	}
+	lit, ok := expr.(*ast.CompositeLit)
+	if !ok {
+		return "", false
+	}
+	sel, ok := lit.Type.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != "Command" || f.importPath(sel.X) != cobraPath {
+		return "", false
+	}
+	for _, elt := range lit.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			continue
+		}
+		if key, ok := kv.Key.(*ast.Ident); ok && key.Name == "Use" {
+			use, _ := f.stringValue(kv.Value)
+			if fields := strings.Fields(use); len(fields) > 0 {
+				return fields[0], true
+			}
+		}
+	}
+	return "", true
+}
+
+// flagSet returns the name of the flag set selected by expr.
+func (f *flagFinder) flagSet(expr ast.Expr) (name string, pflag bool, ok bool) {
+	switch expr := expr.(type) {
+	case *ast.Ident:
+		name, ok = f.flagSets[f.key(expr)]
+		return name, false, ok
+	case *ast.SelectorExpr:
+		// flag.CommandLine
+		if expr.Sel.Name == "CommandLine" {
+			p := f.importPath(expr.X)
+			return "", p == pflagPath, p == flagPath || p == pflagPath
+		}
+	case *ast.CallExpr:
+		// cmd.Flags(), cmd.PersistentFlags()
+		sel, isSel := expr.Fun.(*ast.SelectorExpr)
+		if !isSel || len(expr.Args) != 0 {
+			return "", false, false
+		}
+		switch sel.Sel.Name {
+		case "Flags", "PersistentFlags", "LocalFlags":
+		default:
+			return "", false, false
+		}
+		if id, isIdent := sel.X.(*ast.Ident); isIdent {
+			name, ok = f.commands[f.key(id)]
+			return name, true, ok
+		}
+	}
+	return "", false, false
+}
+
+// findFlags records the flag definitions.
+func (f *flagFinder) findFlags(n ast.Node) bool {
+	call, ok := n.(*ast.CallExpr)
+	if !ok {
+		return true
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return true
+	}
+
+	// The flags of the command executed by rootCmd.Execute() are the flags
+	// of the program.
+	if sel.Sel.Name == "Execute" || sel.Sel.Name == "ExecuteC" {
+		if id, ok := sel.X.(*ast.Ident); ok {
+			if name, ok := f.commands[f.key(id)]; ok {
+				f.root[name] = true
+			}
+		}
+		return true
+	}
+
+	fn, ok := parseFlagFunc(sel.Sel.Name)
+	if !ok {
+		return true
+	}
+	flag := &Flag{}
+	switch p := f.importPath(sel.X); p {
+	case flagPath, pflagPath:
+		flag.Pflag = p == pflagPath
+	default:
+		if flag.FlagSet, flag.Pflag, ok = f.flagSet(sel.X); !ok {
+			return true
+		}
+	}
+
+	args := call.Args
+	if fn.pointer || fn.value {
+		if len(args) == 0 {
+			return true
+		}
+		args = args[1:]
+	}
+	var name, shorthand, def, usage ast.Expr
+	switch {
+	case fn.typ == "Func" || fn.typ == "BoolFunc":
+		if len(args) != 3 {
+			return true
+		}
+		name, usage = args[0], args[1]
+	case fn.value || fn.typ == "Count":
+		if fn.shorthand && len(args) == 3 {
+			name, shorthand, usage = args[0], args[1], args[2]
+		} else if !fn.shorthand && len(args) == 2 {
+			name, usage = args[0], args[1]
+		} else {
+			return true
+		}
+	default:
+		if fn.shorthand && len(args) == 4 {
+			name, shorthand, def, usage = args[0], args[1], args[2], args[3]
+		} else if !fn.shorthand && len(args) == 3 {
+			name, def, usage = args[0], args[1], args[2]
+		} else {
+			return true
+		}
+	}
+	if flag.Name, ok = f.stringValue(name); !ok || flag.Name == "" {
+		return true
+	}
+	if shorthand != nil {
+		flag.Shorthand, _ = f.stringValue(shorthand)
+	}
+	if def != nil {
+		flag.Default = types.ExprString(def)
+		if zeroDefaults[flag.Default] {
+			flag.Default = ""
+		}
+	}
+	flag.Usage, ok = f.stringValue(usage)
+	if !ok {
+		flag.Usage = types.ExprString(usage)
+	}
+	flag.Type = "value"
+	if !fn.value {
+		flag.Type = flagTypes[fn.typ]
+	}
+	flag.Type, flag.Usage = unquoteUsage(flag.Type, flag.Usage)
+	flag.Pos = f.b.position(call)
+	f.flags = append(f.flags, flag)
+	return true
+}
+
+// stringValue returns the value of a constant string expression.
+func (f *flagFinder) stringValue(expr ast.Expr) (string, bool) {
+	if f.b.tinfo != nil {
+		if tv, ok := f.b.tinfo.Types[expr]; ok && tv.Value != nil && tv.Value.Kind() == constant.String {
+			return constant.StringVal(tv.Value), true
+		}
+	}
+	if lit, ok := expr.(*ast.BasicLit); ok && lit.Kind == token.STRING {
+		s, err := strconv.Unquote(lit.Value)
+		return s, err == nil
+	}
+	return "", false
+}
+
+// unquoteUsage extracts a back-quoted argument name from the usage string
+// the same way as the flag package.
+func unquoteUsage(typ, usage string) (string, string) {
+	i := strings.IndexByte(usage, '`')
+	if i < 0 {
+		return typ, usage
+	}
+	j := strings.IndexByte(usage[i+1:], '`')
+	if j < 0 {
+		return typ, usage
+	}
+	j += i + 1
+	return usage[i+1 : j], usage[:i] + usage[i+1:j] + usage[j+1:]
+}
+
+// mergeFlags appends the flags in src that are not in dst.
+func mergeFlags(dst, src []*Flag) []*Flag {
+	seen := make(map[[2]string]bool)
+	for _, f := range dst {
+		seen[[2]string{f.FlagSet, f.Name}] = true
+	}
+	for _, f := range src {
+		k := [2]string{f.FlagSet, f.Name}
+		if !seen[k] {
+			seen[k] = true
+			dst = append(dst, f)
+		}
+	}
+	return dst
+}
@@ -0,0 +1,120 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file or at
+// https://developers.google.com/open-source/licenses/bsd.
+
+package doc
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+const flagSource = `
+// Command c does things.
+package main
+
+import (
+	"flag"
+	"time"
+)
+
+const defaultAddr = "localhost:8080"
+
+var (
+	addr    = flag.String("addr", defaultAddr, "serve on ` + "`address`" + `")
+	verbose = flag.Bool("v", false, "verbose " + "output")
+	timeout time.Duration
+)
+
+func main() {
+	flag.DurationVar(&timeout, "timeout", 10*time.Second, "request timeout")
+	flag.Var(&list{}, "tag", "add a tag")
+
+	build := flag.NewFlagSet("build", flag.ExitOnError)
+	build.Int("j", 4, "parallel jobs")
+	run()
+}
+
+func run() {
+	fs := flag.NewFlagSet("run", flag.ExitOnError)
+	fs.String("exec", "", "run with program")
+	flag.CommandLine.Uint("n", 1, "count")
+}
+
+type list []string
+`
+
+const cobraSource = `
+package main
+
+import (
+	"github.com/spf13/cobra"
+	flag "github.com/spf13/pflag"
+)
+
+var rootCmd = &cobra.Command{Use: "app"}
+
+var serveCmd = &cobra.Command{
+	Use:   "serve [flags]",
+	Short: "Start the server",
+}
+
+func main() {
+	rootCmd.PersistentFlags().StringP("config", "c", "", "config file")
+	serveCmd.Flags().IntVarP(&port, "port", "p", 80, "listen port")
+	serveCmd.Flags().CountP("verbose", "v", "verbosity")
+	flag.StringSlice("tags", nil, "tags")
+	rootCmd.AddCommand(serveCmd)
+	rootCmd.Execute()
+}
+
+var port int
+`
+
+// clearFlagPos clears the positions of the flags after checking that the
+// positions are set.
+func clearFlagPos(t *testing.T, flags []*Flag) {
+	for _, f := range flags {
+		if f.Pos.Line == 0 {
+			t.Errorf("flag %s has no position", f.Name)
+		}
+		f.Pos = Pos{}
+	}
+}
+
+func TestFlags(t *testing.T) {
+	pkg := newTestPackage(t, "example.com/c", map[string]string{"main.go": flagSource})
+	want := []*Flag{
+		{Name: "addr", Type: "address", Default: "defaultAddr", Usage: "serve on address"},
+		{Name: "v", Usage: "verbose output"},
+		{Name: "timeout", Type: "duration", Default: "10 * time.Second", Usage: "request timeout"},
+		{Name: "tag", Type: "value", Usage: "add a tag"},
+		{Name: "j", Type: "int", Default: "4", Usage: "parallel jobs", FlagSet: "build"},
+		{Name: "exec", Type: "string", Usage: "run with program", FlagSet: "run"},
+		{Name: "n", Type: "uint", Default: "1", Usage: "count"},
+	}
+	clearFlagPos(t, pkg.Flags)
+	if diff := cmp.Diff(want, pkg.Flags); diff != "" {
+		t.Errorf("flags differ (-want +got):\n%s", diff)
+	}
+
+	pkg = newTestPackage(t, "example.com/app", map[string]string{"main.go": cobraSource})
+	want = []*Flag{
+		{Name: "config", Shorthand: "c", Type: "string", Usage: "config file", Pflag: true},
+		{Name: "port", Shorthand: "p", Type: "int", Default: "80", Usage: "listen port", FlagSet: "serve", Pflag: true},
+		{Name: "verbose", Shorthand: "v", Usage: "verbosity", FlagSet: "serve", Pflag: true},
+		{Name: "tags", Type: "strings", Usage: "tags", Pflag: true},
+	}
+	clearFlagPos(t, pkg.Flags)
+	if diff := cmp.Diff(want, pkg.Flags); diff != "" {
+		t.Errorf("cobra flags differ (-want +got):\n%s", diff)
+	}
+
+	pkg = newTestPackage(t, "example.com/p", map[string]string{"p.go": "package p\n\nimport \"flag\"\n\nvar x = flag.Int(\"x\", 0, \"\")\n"})
+	if pkg.Flags != nil {
+		t.Errorf("flags of a library = %v, want nil", pkg.Flags)
+	}
+}
@@ -0,0 +1,197 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file or at
+// https://developers.google.com/open-source/licenses/bsd.
+
+package doc
+
+import (
+	"bytes"
+	"go/types"
+	"strings"
+)
+
+// TypeRef refers to a named type.
+type TypeRef struct {
+	// Import path of the package declaring the type or "" for predeclared
+	// types.
+	Path string
+
+	// Name of the type.
+	Name string
+
+	// True if the relation holds for the pointer type *Name only.
+	Ptr bool
+}
+
+// wellKnownInterfaces are the interfaces outside of the package that types
+// are checked against. The packages are declared in stubSources.
+var wellKnownInterfaces = []TypeRef{
+	{Path: "", Name: "error"},
+	{Path: "encoding", Name: "BinaryMarshaler"},
+	{Path: "encoding", Name: "BinaryUnmarshaler"},
+	{Path: "encoding", Name: "TextMarshaler"},
+	{Path: "encoding", Name: "TextUnmarshaler"},
+	{Path: "encoding/json", Name: "Marshaler"},
+	{Path: "encoding/json", Name: "Unmarshaler"},
+	{Path: "fmt", Name: "Formatter"},
+	{Path: "fmt", Name: "GoStringer"},
+	{Path: "fmt", Name: "Stringer"},
+	{Path: "io", Name: "ByteReader"},
+	{Path: "io", Name: "ByteWriter"},
+	{Path: "io", Name: "Closer"},
+	{Path: "io", Name: "Reader"},
+	{Path: "io", Name: "ReaderAt"},
+	{Path: "io", Name: "ReaderFrom"},
+	{Path: "io", Name: "RuneReader"},
+	{Path: "io", Name: "Seeker"},
+	{Path: "io", Name: "StringWriter"},
+	{Path: "io", Name: "Writer"},
+	{Path: "io", Name: "WriterAt"},
+	{Path: "io", Name: "WriterTo"},
+	{Path: "net/http", Name: "Handler"},
+	{Path: "sort", Name: "Interface"},
+}
+
+type namedInterface struct {
+	ref   TypeRef
+	iface *types.Interface
+	typ   *Type // nil if the interface is not declared in the package
+}
+
+func (b *builder) wellKnownInterface(ref TypeRef) *types.Interface {
+	var obj types.Object
+	if ref.Path == "" {
+		obj = types.Universe.Lookup(ref.Name)
+	} else {
+		pkg, _ := b.importer.Import(ref.Path)
+		obj = pkg.Scope().Lookup(ref.Name)
+	}
+	if obj == nil {
+		return nil
+	}
+	iface, _ := obj.Type().Underlying().(*types.Interface)
+	return iface
+}
+
+// implementations sets the Implements, ImplementedBy and MethodSigs fields
+// of the package types.
+func (b *builder) implementations(importPath string, tdocs []*Type) {
+	if b.tpkg == nil {
+		return
+	}
+
+	var ifaces []*namedInterface
+	for _, ref := range wellKnownInterfaces {
+		if iface := b.wellKnownInterface(ref); iface != nil {
+			ifaces = append(ifaces, &namedInterface{ref: ref, iface: iface})
+		}
+	}
+	named := make(map[*Type]*types.Named)
+	for _, t := range tdocs {
+		n := b.lookupType(t.Name)
+		if n == nil {
+			continue
+		}
+		named[t] = n
+		if iface, ok := n.Underlying().(*types.Interface); ok && iface.NumMethods() > 0 {
+			ifaces = append(ifaces, &namedInterface{
+				ref:   TypeRef{Path: importPath, Name: t.Name},
+				iface: iface,
+				typ:   t,
+			})
+		}
+	}
+
+	for _, t := range tdocs {
+		n := named[t]
+		if n == nil {
+			continue
+		}
+		_, isInterface := n.Underlying().(*types.Interface)
+		t.MethodSigs = methodSigs(n, isInterface)
+		for _, ni := range ifaces {
+			if ni.typ == t {
+				continue
+			}
+			ref := ni.ref
+			switch {
+			case types.Implements(n, ni.iface):
+			case !isInterface && types.Implements(types.NewPointer(n), ni.iface):
+				ref.Ptr = true
+			default:
+				continue
+			}
+			t.Implements = append(t.Implements, &ref)
+			if ni.typ != nil && !isInterface {
+				ni.typ.ImplementedBy = append(ni.typ.ImplementedBy,
+					&TypeRef{Path: importPath, Name: t.Name, Ptr: ref.Ptr})
+			}
+		}
+	}
+}
+
+// methodSigs returns the signatures of the methods in the method set of
+// *T, or T for interfaces. Each signature has the form "Name func(params)
+// results" with types qualified by import path and parameter names omitted.
+// Methods not in the method set of T are prefixed with "*". Unexported and
+// unresolved methods of non-interface types are omitted because they cannot
+// satisfy interfaces from other packages.
+func methodSigs(t *types.Named, isInterface bool) []string {
+	var sigs []string
+	valueSet := types.NewMethodSet(t)
+	mset := valueSet
+	if !isInterface {
+		mset = types.NewMethodSet(types.NewPointer(t))
+	}
+	for i := 0; i < mset.Len(); i++ {
+		f, ok := mset.At(i).Obj().(*types.Func)
+		if !ok {
+			continue
+		}
+		sig := signatureString(f.Type().(*types.Signature))
+		if !isInterface && (!f.Exported() || strings.Contains(sig, "invalid type")) {
+			continue
+		}
+		prefix := ""
+		if valueSet.Lookup(f.Pkg(), f.Name()) == nil {
+			prefix = "*"
+		}
+		sigs = append(sigs, prefix+f.Name()+" "+sig)
+	}
+	return sigs
+}
+
+// signatureString formats a function signature without parameter names.
+func signatureString(sig *types.Signature) string {
+	var buf bytes.Buffer
+	buf.WriteString("func")
+	writeTuple(&buf, sig.Params(), sig.Variadic())
+	switch results := sig.Results(); results.Len() {
+	case 0:
+	case 1:
+		buf.WriteByte(' ')
+		buf.WriteString(types.TypeString(results.At(0).Type(), qualifyByPath))
+	default:
+		buf.WriteByte(' ')
+		writeTuple(&buf, results, false)
+	}
+	return buf.String()
+}
+
+func writeTuple(buf *bytes.Buffer, tuple *types.Tuple, variadic bool) {
+	buf.WriteByte('(')
+	for i := 0; i < tuple.Len(); i++ {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		t := tuple.At(i).Type()
+		if s, ok := t.(*types.Slice); ok && variadic && i == tuple.Len()-1 {
+			buf.WriteString("...")
+			t = s.Elem()
+		}
+		buf.WriteString(types.TypeString(t, qualifyByPath))
+	}
+	buf.WriteByte(')')
+}
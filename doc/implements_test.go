@@ -0,0 +1,136 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file or at
+// https://developers.google.com/open-source/licenses/bsd.
+
+package doc
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/golang/gddo/gosrc"
+)
+
+const implementsSource = `package p
+
+import (
+	"fmt"
+	"io"
+
+	"example.com/plugin"
+)
+
+type Source interface {
+	Next() (string, error)
+}
+
+type Buffer struct{}
+
+func (b *Buffer) Read(p []byte) (int, error) { return 0, nil }
+func (b *Buffer) Next() (string, error)     { return "", nil }
+func (b Buffer) String() string             { return "" }
+
+type Error string
+
+func (e Error) Error() string { return string(e) }
+func (e Error) Format(f fmt.State, verb rune) {}
+
+type ReadSource interface {
+	io.Reader
+	Source
+}
+
+type Plugin struct{}
+
+func (Plugin) Run(ctx plugin.Context, args ...string) error { return nil }
+`
+
+func newTestPackage(t *testing.T, importPath string, files map[string]string) *Package {
+	dir := &gosrc.Directory{ImportPath: importPath, ProjectRoot: importPath}
+	for name, src := range files {
+		dir.Files = append(dir.Files, &gosrc.File{Name: name, Data: []byte(src)})
+	}
+	pkg, err := newPackage(dir)
+	if err != nil {
+		t.Fatalf("newPackage(%q) returned error %v", importPath, err)
+	}
+	return pkg
+}
+
+func findType(pkg *Package, name string) *Type {
+	for _, t := range pkg.Types {
+		if t.Name == name {
+			return t
+		}
+	}
+	return nil
+}
+
+func TestImplementations(t *testing.T) {
+	const path = "example.com/p"
+	pkg := newTestPackage(t, path, map[string]string{"p.go": implementsSource})
+
+	tests := []struct {
+		name          string
+		implements    []*TypeRef
+		implementedBy []*TypeRef
+		sigs          []string
+	}{
+		{
+			name: "Buffer",
+			implements: []*TypeRef{
+				{Path: "fmt", Name: "Stringer"},
+				{Path: "io", Name: "Reader", Ptr: true},
+				{Path: path, Name: "ReadSource", Ptr: true},
+				{Path: path, Name: "Source", Ptr: true},
+			},
+			sigs: []string{
+				"*Next func() (string, error)",
+				"*Read func([]byte) (int, error)",
+				"String func() string",
+			},
+		},
+		{
+			name: "Error",
+			implements: []*TypeRef{
+				{Path: "", Name: "error"},
+				{Path: "fmt", Name: "Formatter"},
+			},
+		},
+		{
+			name: "Plugin",
+			sigs: []string{"Run func(example.com/plugin.Context, ...string) error"},
+		},
+		{
+			name: "ReadSource",
+			implements: []*TypeRef{
+				{Path: "io", Name: "Reader"},
+				{Path: path, Name: "Source"},
+			},
+			implementedBy: []*TypeRef{{Path: path, Name: "Buffer", Ptr: true}},
+		},
+		{
+			name:          "Source",
+			implementedBy: []*TypeRef{{Path: path, Name: "Buffer", Ptr: true}},
+		},
+	}
+	for _, tt := range tests {
+		typ := findType(pkg, tt.name)
+		if typ == nil {
+			t.Errorf("type %s not found", tt.name)
+			continue
+		}
+		if !cmp.Equal(typ.Implements, tt.implements) {
+			t.Errorf("%s.Implements = %v, want %v", tt.name, typ.Implements, tt.implements)
+		}
+		if !cmp.Equal(typ.ImplementedBy, tt.implementedBy) {
+			t.Errorf("%s.ImplementedBy = %v, want %v", tt.name, typ.ImplementedBy, tt.implementedBy)
+		}
+		if tt.sigs != nil && !cmp.Equal(typ.MethodSigs, tt.sigs) {
+			t.Errorf("%s.MethodSigs = %q, want %q", tt.name, typ.MethodSigs, tt.sigs)
+		}
+	}
+}
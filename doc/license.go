@@ -0,0 +1,83 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file or at
+// https://developers.google.com/open-source/licenses/bsd.
+
+package doc
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/golang/gddo/gosrc"
+)
+
+// OtherLicense is the license of a package with a license file in an
+// unrecognized format.
+const OtherLicense = "Other"
+
+var licensePat = regexp.MustCompile(`(?i)^(?:licen[cs]e|copying|unlicense)(?:$|[.-])`)
+
+// licenses are the recognized licenses in the order they are tested. A
+// license matches if its text contains all the phrases.
+var licenses = []struct {
+	id      string // SPDX identifier.
+	phrases []string
+}{
+	{"AGPL-3.0", []string{"gnu affero general public license", "version 3"}},
+	{"LGPL-3.0", []string{"gnu lesser general public license", "version 3"}},
+	{"LGPL-2.1", []string{"gnu lesser general public license", "version 2.1"}},
+	{"GPL-3.0", []string{"gnu general public license", "version 3"}},
+	{"GPL-2.0", []string{"gnu general public license", "version 2"}},
+	{"MPL-2.0", []string{"mozilla public license", "2.0"}},
+	{"Apache-2.0", []string{"apache license", "version 2.0"}},
+	{"BSD-3-Clause", []string{"redistribution and use in source and binary forms", "neither the name"}},
+	{"BSD-3-Clause", []string{"redistribution and use in source and binary forms", "names of its contributors"}},
+	{"BSD-2-Clause", []string{"redistribution and use in source and binary forms"}},
+	{"MIT", []string{"permission is hereby granted, free of charge"}},
+	{"ISC", []string{"permission to use, copy, modify, and", "distribute this software for any purpose with or without fee"}},
+	{"Unlicense", []string{"this is free and unencumbered software released into the public domain"}},
+}
+
+// licenseID returns the SPDX identifier of the license text or OtherLicense
+// if the license is not recognized.
+func licenseID(data []byte) string {
+	text := strings.Join(strings.Fields(strings.ToLower(string(data))), " ")
+	for _, l := range licenses {
+		match := true
+		for _, p := range l.phrases {
+			if !strings.Contains(text, p) {
+				match = false
+				break
+			}
+		}
+		if match {
+			return l.id
+		}
+	}
+	return OtherLicense
+}
+
+// newLicense returns the license of the files in the directory or "" if the
+// directory does not have a license file. The license file with the
+// shortest name is used.
+func newLicense(dir *gosrc.Directory) string {
+	var file *gosrc.File
+	for _, f := range dir.Files {
+		if licensePat.MatchString(f.Name) && (file == nil || len(f.Name) < len(file.Name)) {
+			file = f
+		}
+	}
+	if file == nil {
+		return ""
+	}
+	return licenseID(file.Data)
+}
+
+// migrateLicense upgrades a package encoded before License was added. The
+// license file is not part of the document, so License is left unset until
+// the package is crawled again.
+func migrateLicense(pkg map[string]interface{}) error {
+	return nil
+}
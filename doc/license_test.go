@@ -0,0 +1,41 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file or at
+// https://developers.google.com/open-source/licenses/bsd.
+
+package doc
+
+import (
+	"testing"
+
+	"github.com/golang/gddo/gosrc"
+)
+
+var licenseTests = []struct {
+	files map[string]string
+	want  string
+}{
+	{map[string]string{"README.md": "# Title\n"}, ""},
+	{map[string]string{"LICENSE": "Permission is hereby granted, free of charge,\nto any person obtaining a copy"}, "MIT"},
+	{map[string]string{"LICENSE.txt": "Apache License\n  Version 2.0, January 2004"}, "Apache-2.0"},
+	{map[string]string{"COPYING": "GNU GENERAL PUBLIC LICENSE\nVersion 3, 29 June 2007"}, "GPL-3.0"},
+	{map[string]string{"LICENSE": "GNU LESSER GENERAL PUBLIC LICENSE\nVersion 3, 29 June 2007"}, "LGPL-3.0"},
+	{map[string]string{"LICENSE": "Redistribution and use in source and binary forms ...\n" +
+		"Neither the name of Google Inc. nor the names of its\ncontributors may be used"}, "BSD-3-Clause"},
+	{map[string]string{"LICENSE": "Redistribution and use in source and binary forms ..."}, "BSD-2-Clause"},
+	{map[string]string{"LICENSE": "All rights reserved."}, OtherLicense},
+	{map[string]string{"LICENSE-MIT": "Permission is hereby granted, free of charge", "LICENSE": "Apache License, Version 2.0"}, "Apache-2.0"},
+}
+
+func TestLicense(t *testing.T) {
+	for _, tt := range licenseTests {
+		dir := &gosrc.Directory{}
+		for name, data := range tt.files {
+			dir.Files = append(dir.Files, &gosrc.File{Name: name, Data: []byte(data)})
+		}
+		if got := newLicense(dir); got != tt.want {
+			t.Errorf("newLicense(%v) = %q, want %q", tt.files, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file or at
+// https://developers.google.com/open-source/licenses/bsd.
+
+package doc
+
+import (
+	"bytes"
+	"html"
+	"strconv"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/renderer"
+	htmlrenderer "github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/util"
+)
+
+// markdown renders a Markdown README with goldmark and the GitHub extensions
+// for tables, strikethrough and bare URLs. The HTML written by goldmark,
+// including the HTML embedded in the README, is rewritten by the
+// htmlSanitizer, which also resolves the URLs of links and images.
+func (r *readmeRenderer) markdown(s string) {
+	md := goldmark.New(
+		goldmark.WithExtensions(
+			extension.NewTable(extension.WithTableCellAlignMethod(extension.TableCellAlignAttribute)),
+			extension.Strikethrough,
+			extension.Linkify,
+		),
+		goldmark.WithRendererOptions(
+			htmlrenderer.WithUnsafe(),
+			renderer.WithNodeRenderers(util.Prioritized(markdownHeadings{r}, 100)),
+		),
+	)
+	var buf bytes.Buffer
+	if err := md.Convert([]byte(s), &buf); err != nil {
+		r.plain(s)
+		return
+	}
+	r.buf.WriteString(r.sanitize(buf.String()))
+}
+
+// markdownHeadings renders the headings of a Markdown README with the ids
+// used for the links to the headings. See readmeRenderer.headingID.
+type markdownHeadings struct {
+	r *readmeRenderer
+}
+
+func (h markdownHeadings) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(ast.KindHeading, h.render)
+}
+
+func (h markdownHeadings) render(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	n := node.(*ast.Heading)
+	level := strconv.Itoa(n.Level)
+	if entering {
+		w.WriteString(`<h` + level + ` id="readme-` + html.EscapeString(h.r.headingID(string(n.Text(source)))) + `">`)
+	} else {
+		w.WriteString("</h" + level + ">\n")
+	}
+	return ast.WalkContinue, nil
+}
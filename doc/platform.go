@@ -0,0 +1,293 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file or at
+// https://developers.google.com/open-source/licenses/bsd.
+
+package doc
+
+import (
+	"go/build"
+	"sort"
+	"strings"
+)
+
+// target is a set of package files and the platforms that select them.
+type target struct {
+	bpkg         *build.Package
+	goos, goarch string   // first platform
+	platforms    []string // GOOS/GOARCH
+}
+
+func (t *target) goFiles() []string {
+	var names []string
+	names = append(names, t.bpkg.GoFiles...)
+	return append(names, t.bpkg.CgoFiles...)
+}
+
+// addTarget adds the platform to the target with the same files as bpkg or
+// appends a new target.
+func addTarget(targets []*target, bpkg *build.Package, goos, goarch string) []*target {
+	nt := &target{bpkg: bpkg, goos: goos, goarch: goarch}
+	key := strings.Join(nt.goFiles(), " ")
+	platform := goos + "/" + goarch
+	for _, t := range targets {
+		if strings.Join(t.goFiles(), " ") == key {
+			t.platforms = append(t.platforms, platform)
+			return targets
+		}
+	}
+	nt.platforms = []string{platform}
+	return append(targets, nt)
+}
+
+// A PlatformDecl is the declaration and documentation of a constant,
+// variable, function or type on some of the platforms. If they differ
+// between platforms, the PlatformDecls field of the value, function or type
+// lists each variant once. The first is the variant of the primary platform,
+// the same as the Decl and Doc fields.
+type PlatformDecl struct {
+	Decl      Code
+	Pos       Pos
+	Doc       string
+	Platforms []string
+}
+
+// mergeDecl merges the declaration src of other platforms into the
+// variants decls of the declaration primary. The platforms of primary are
+// the platforms merged so far.
+func mergeDecl(decls []*PlatformDecl, primary, src *PlatformDecl) []*PlatformDecl {
+	if decls == nil {
+		if src.Decl.Text == primary.Decl.Text && src.Doc == primary.Doc {
+			return nil
+		}
+		primary.Platforms = append([]string(nil), primary.Platforms...)
+		decls = []*PlatformDecl{primary}
+	}
+	for _, d := range decls {
+		if d.Decl.Text == src.Decl.Text && d.Doc == src.Doc {
+			d.Platforms = append(d.Platforms, src.Platforms...)
+			return decls
+		}
+	}
+	return append(decls, src)
+}
+
+// migratePlatformDecls upgrades a package encoded before the PlatformDecls
+// fields were added. Only the declarations of the primary platform are in
+// the document, so the fields are left unset until the package is crawled
+// again.
+func migratePlatformDecls(pkg map[string]interface{}) error {
+	return nil
+}
+
+// walkDecls calls fn with the Platforms field of each declaration in pkg.
+func walkDecls(pkg *Package, fn func(*[]string)) {
+	values := func(values []*Value) {
+		for _, v := range values {
+			fn(&v.Platforms)
+		}
+	}
+	funcs := func(funcs []*Func) {
+		for _, f := range funcs {
+			fn(&f.Platforms)
+		}
+	}
+	values(pkg.Consts)
+	values(pkg.Vars)
+	funcs(pkg.Funcs)
+	for _, t := range pkg.Types {
+		fn(&t.Platforms)
+		for _, m := range t.Fields {
+			fn(&m.Platforms)
+		}
+		for _, m := range t.Promoted {
+			fn(&m.Platforms)
+		}
+		values(t.Consts)
+		values(t.Vars)
+		funcs(t.Funcs)
+		funcs(t.Methods)
+	}
+}
+
+// setPlatforms sets the platforms of all declarations in pkg.
+func setPlatforms(pkg *Package, platforms []string) {
+	walkDecls(pkg, func(p *[]string) {
+		*p = append([]string(nil), platforms...)
+	})
+}
+
+// clearPlatforms clears the platforms of declarations that exist on all n
+// platforms.
+func clearPlatforms(pkg *Package, n int) {
+	walkDecls(pkg, func(p *[]string) {
+		if len(*p) == n {
+			*p = nil
+		}
+	})
+}
+
+// mergePackage merges the declarations of src, built for other platforms,
+// into dst. Declarations that exist in both packages are taken from dst.
+// If the declaration text or documentation differs, both variants are kept
+// in the PlatformDecls field.
+func mergePackage(dst, src *Package) {
+	dst.Consts = mergeValues(dst.Consts, src.Consts)
+	dst.Vars = mergeValues(dst.Vars, src.Vars)
+	dst.Funcs = mergeFuncs(dst.Funcs, src.Funcs)
+	dst.Types = mergeTypes(dst.Types, src.Types)
+	dst.Flags = mergeFlags(dst.Flags, src.Flags)
+	dst.Symbols = mergeSymbols(dst.Symbols, src.Symbols)
+	dst.ErrorCatalog = mergeErrorCatalog(dst.ErrorCatalog, src.ErrorCatalog)
+	mergeTests(dst, src)
+
+	dst.Errors = mergeStrings(dst.Errors, src.Errors, false)
+	dst.Imports = mergeStrings(dst.Imports, src.Imports, true)
+	dst.TestImports = mergeStrings(dst.TestImports, src.TestImports, true)
+	dst.XTestImports = mergeStrings(dst.XTestImports, src.XTestImports, true)
+
+	seen := make(map[Diagnostic]bool)
+	for _, d := range dst.Diagnostics {
+		seen[*d] = true
+	}
+	for _, d := range src.Diagnostics {
+		if !seen[*d] {
+			dst.Diagnostics = append(dst.Diagnostics, d)
+		}
+	}
+	sort.Sort(byDiagnosticPos(dst.Diagnostics))
+}
+
+func mergeValues(dst, src []*Value) []*Value {
+	index := make(map[string]*Value)
+	for _, v := range dst {
+		index[strings.Join(v.Names, ",")] = v
+	}
+	for _, v := range src {
+		if d := index[strings.Join(v.Names, ",")]; d != nil {
+			d.PlatformDecls = mergeDecl(d.PlatformDecls,
+				&PlatformDecl{Decl: d.Decl, Pos: d.Pos, Doc: d.Doc, Platforms: d.Platforms},
+				&PlatformDecl{Decl: v.Decl, Pos: v.Pos, Doc: v.Doc, Platforms: v.Platforms})
+			d.Platforms = append(d.Platforms, v.Platforms...)
+		} else {
+			dst = append(dst, v)
+		}
+	}
+	return dst
+}
+
+func mergeFuncs(dst, src []*Func) []*Func {
+	index := make(map[string]*Func)
+	for _, f := range dst {
+		index[f.Recv+"."+f.Name] = f
+	}
+	n := len(dst)
+	for _, f := range src {
+		if d := index[f.Recv+"."+f.Name]; d != nil {
+			d.PlatformDecls = mergeDecl(d.PlatformDecls,
+				&PlatformDecl{Decl: d.Decl, Pos: d.Pos, Doc: d.Doc, Platforms: d.Platforms},
+				&PlatformDecl{Decl: f.Decl, Pos: f.Pos, Doc: f.Doc, Platforms: f.Platforms})
+			d.Platforms = append(d.Platforms, f.Platforms...)
+		} else {
+			dst = append(dst, f)
+		}
+	}
+	if len(dst) > n {
+		sort.SliceStable(dst, func(i, j int) bool { return dst[i].Name < dst[j].Name })
+	}
+	return dst
+}
+
+func mergeTypes(dst, src []*Type) []*Type {
+	index := make(map[string]*Type)
+	for _, t := range dst {
+		index[t.Name] = t
+	}
+	n := len(dst)
+	for _, t := range src {
+		d := index[t.Name]
+		if d == nil {
+			dst = append(dst, t)
+			continue
+		}
+		d.PlatformDecls = mergeDecl(d.PlatformDecls,
+			&PlatformDecl{Decl: d.Decl, Pos: d.Pos, Doc: d.Doc, Platforms: d.Platforms},
+			&PlatformDecl{Decl: t.Decl, Pos: t.Pos, Doc: t.Doc, Platforms: t.Platforms})
+		d.Platforms = append(d.Platforms, t.Platforms...)
+		d.Consts = mergeValues(d.Consts, t.Consts)
+		d.Vars = mergeValues(d.Vars, t.Vars)
+		d.Funcs = mergeFuncs(d.Funcs, t.Funcs)
+		d.Methods = mergeFuncs(d.Methods, t.Methods)
+		d.Fields = mergeMembers(d.Fields, t.Fields)
+		d.Promoted = mergeMembers(d.Promoted, t.Promoted)
+		d.ExternalEmbeds = mergeTypeRefs(d.ExternalEmbeds, t.ExternalEmbeds)
+	}
+	if len(dst) > n {
+		sort.SliceStable(dst, func(i, j int) bool { return dst[i].Name < dst[j].Name })
+	}
+	return dst
+}
+
+// mergeMembers merges the fields or promoted members of a struct type on
+// other platforms. A member is the same on two platforms if it has the same
+// type or signature and is promoted from the same type.
+func mergeMembers(dst, src []*Member) []*Member {
+	type key struct {
+		name   string
+		method bool
+		text   string
+		from   TypeRef
+	}
+	index := make(map[key]*Member)
+	for _, m := range dst {
+		index[key{m.Name, m.Method, m.Text, m.From}] = m
+	}
+	n := len(dst)
+	for _, m := range src {
+		if d := index[key{m.Name, m.Method, m.Text, m.From}]; d != nil {
+			d.Platforms = append(d.Platforms, m.Platforms...)
+		} else {
+			dst = append(dst, m)
+		}
+	}
+	if len(dst) > n {
+		// Methods come before fields.
+		sort.SliceStable(dst, func(i, j int) bool { return dst[i].Method && !dst[j].Method })
+	}
+	return dst
+}
+
+func mergeTypeRefs(dst, src []*TypeRef) []*TypeRef {
+	seen := make(map[TypeRef]bool)
+	for _, r := range dst {
+		seen[*r] = true
+	}
+	for _, r := range src {
+		if !seen[*r] {
+			seen[*r] = true
+			dst = append(dst, r)
+		}
+	}
+	return dst
+}
+
+// mergeStrings appends the strings in src that are not in dst. If sorted is
+// true, the result is sorted.
+func mergeStrings(dst, src []string, sorted bool) []string {
+	seen := make(map[string]bool)
+	for _, s := range dst {
+		seen[s] = true
+	}
+	n := len(dst)
+	for _, s := range src {
+		if !seen[s] {
+			seen[s] = true
+			dst = append(dst, s)
+		}
+	}
+	if sorted && len(dst) > n {
+		sort.Strings(dst)
+	}
+	return dst
+}
@@ -0,0 +1,111 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file or at
+// https://developers.google.com/open-source/licenses/bsd.
+
+package doc
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestPlatforms(t *testing.T) {
+	pkg := newTestPackage(t, "example.com/p", map[string]string{
+		"p.go": `package p
+
+type Handle struct{}
+
+func Open() {}
+
+const Mode = 1
+`,
+		"p_linux.go": `package p
+
+func Epoll() {}
+
+const Mode2 = 2
+`,
+		"p_windows.go": `package p
+
+func (Handle) Close() {}
+
+func Registry() {}
+
+const Mode2 = 3
+`,
+	})
+
+	wantPlatforms := []string{"linux/amd64", "darwin/amd64", "js/wasm", "windows/amd64"}
+	if !cmp.Equal(pkg.Platforms, wantPlatforms) {
+		t.Errorf("Platforms = %v, want %v", pkg.Platforms, wantPlatforms)
+	}
+	if pkg.GOOS != "linux" || pkg.GOARCH != "amd64" {
+		t.Errorf("GOOS, GOARCH = %s, %s, want linux, amd64", pkg.GOOS, pkg.GOARCH)
+	}
+
+	var fileNames []string
+	for _, f := range pkg.Files {
+		fileNames = append(fileNames, f.Name)
+	}
+	if want := []string{"p.go", "p_linux.go", "p_windows.go"}; !cmp.Equal(fileNames, want) {
+		t.Errorf("Files = %v, want %v", fileNames, want)
+	}
+
+	funcs := make(map[string][]string)
+	for _, f := range pkg.Funcs {
+		funcs[f.Name] = f.Platforms
+	}
+	for _, f := range pkg.Types[0].Methods {
+		funcs[f.Recv+"."+f.Name] = f.Platforms
+	}
+	wantFuncs := map[string][]string{
+		"Epoll":        {"linux/amd64"},
+		"Open":         nil,
+		"Registry":     {"windows/amd64"},
+		"Handle.Close": {"windows/amd64"},
+	}
+	if diff := cmp.Diff(wantFuncs, funcs); diff != "" {
+		t.Errorf("func platforms mismatch (-want +got):\n%s", diff)
+	}
+
+	consts := make(map[string][]string)
+	for _, v := range pkg.Consts {
+		consts[v.Names[0]] = v.Platforms
+	}
+	wantConsts := map[string][]string{
+		"Mode":  nil,
+		"Mode2": {"linux/amd64", "windows/amd64"},
+	}
+	if diff := cmp.Diff(wantConsts, consts); diff != "" {
+		t.Errorf("const platforms mismatch (-want +got):\n%s", diff)
+	}
+
+	type variant struct {
+		Decl      string
+		Platforms []string
+	}
+	var mode2 []variant
+	for _, v := range pkg.Consts {
+		if v.Names[0] == "Mode2" {
+			for _, d := range v.PlatformDecls {
+				mode2 = append(mode2, variant{d.Decl.Text, d.Platforms})
+			}
+		}
+	}
+	wantMode2 := []variant{
+		{"const Mode2 = 2", []string{"linux/amd64"}},
+		{"const Mode2 = 3", []string{"windows/amd64"}},
+	}
+	if diff := cmp.Diff(wantMode2, mode2); diff != "" {
+		t.Errorf("Mode2 declarations mismatch (-want +got):\n%s", diff)
+	}
+
+	for _, f := range pkg.Funcs {
+		if f.Name == "Registry" && pkg.Files[f.Pos.File].Name != "p_windows.go" {
+			t.Errorf("Registry declared in %s, want p_windows.go", pkg.Files[f.Pos.File].Name)
+		}
+	}
+}
@@ -0,0 +1,257 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file or at
+// https://developers.google.com/open-source/licenses/bsd.
+
+package doc
+
+import (
+	"go/ast"
+	"go/types"
+	"strings"
+)
+
+// Member is a field or method of a struct type.
+type Member struct {
+	// Name of the field or method.
+	Name string
+
+	// Whether the member is a method.
+	Method bool
+
+	// Field type or method signature without the func keyword, for example
+	// "(p []byte) (n int, err error)".
+	Text string
+
+	// Embedded type that declares a promoted member. Ptr is true for
+	// methods that are only in the method set of the pointer type.
+	From TypeRef
+
+	// Platforms where the type has the member or nil for all platforms.
+	Platforms []string
+}
+
+// migrateMembers upgrades a package encoded before the Platforms field of
+// Member was added. The members of the primary platform are the only ones
+// in the document, so they are reported for all platforms until the
+// package is crawled again.
+func migrateMembers(pkg map[string]interface{}) error {
+	return nil
+}
+
+// namedRef returns a reference to the named type t relative to the package.
+func (b *builder) namedRef(t *types.Named) TypeRef {
+	ref := TypeRef{Name: t.Obj().Name()}
+	if pkg := t.Obj().Pkg(); pkg != nil {
+		ref.Path = pkg.Path()
+	}
+	return ref
+}
+
+func derefNamed(t types.Type) (n *types.Named, ptr bool) {
+	if p, ok := t.(*types.Pointer); ok {
+		t = p.Elem()
+		ptr = true
+	}
+	n, _ = t.(*types.Named)
+	return n, ptr
+}
+
+// embeddedIdent returns the identifier of the type name of an embedded
+// field.
+func embeddedIdent(e ast.Expr) *ast.Ident {
+	switch e := e.(type) {
+	case *ast.Ident:
+		return e
+	case *ast.StarExpr:
+		return embeddedIdent(e.X)
+	case *ast.SelectorExpr:
+		return e.Sel
+	case *ast.IndexExpr:
+		return embeddedIdent(e.X)
+	case *ast.IndexListExpr:
+		return embeddedIdent(e.X)
+	}
+	return nil
+}
+
+// fieldTypes returns the type expressions of the struct fields declared in
+// the package. The fields are printed from the source so that a type from a
+// package that could not be imported is not printed as an invalid type.
+func (b *builder) fieldTypes() map[*types.Var]ast.Expr {
+	exprs := make(map[*types.Var]ast.Expr)
+	for _, file := range b.tfiles {
+		ast.Inspect(file, func(n ast.Node) bool {
+			field, ok := n.(*ast.Field)
+			if !ok {
+				return true
+			}
+			names := field.Names
+			if len(names) == 0 {
+				if id := embeddedIdent(field.Type); id != nil {
+					names = []*ast.Ident{id}
+				}
+			}
+			for _, id := range names {
+				if v, ok := b.tinfo.Defs[id].(*types.Var); ok && v.IsField() {
+					exprs[v] = field.Type
+				}
+			}
+			return true
+		})
+	}
+	return exprs
+}
+
+// memberText returns the type of the field f.
+func memberText(f *types.Var, exprs map[*types.Var]ast.Expr, qf types.Qualifier) string {
+	if e := exprs[f]; e != nil {
+		return types.ExprString(e)
+	}
+	return types.TypeString(f.Type(), qf)
+}
+
+// members sets the Fields, Promoted and ExternalEmbeds fields of struct
+// types. Methods promoted from unexported embedded types are already in the
+// Methods field set by go/doc and are skipped. Types from other packages
+// are qualified by package name as in the source.
+func (b *builder) members(tdocs []*Type) {
+	if b.tpkg == nil {
+		return
+	}
+	qf := func(p *types.Package) string {
+		if p == b.tpkg {
+			return ""
+		}
+		return p.Name()
+	}
+	exprs := b.fieldTypes()
+	for _, t := range tdocs {
+		n := b.lookupType(t.Name)
+		if n == nil {
+			continue
+		}
+		st, ok := n.Underlying().(*types.Struct)
+		if !ok {
+			continue
+		}
+
+		names := make(map[string]bool)
+		for _, m := range t.Methods {
+			names[m.Name] = true
+		}
+
+		for i := 0; i < st.NumFields(); i++ {
+			f := st.Field(i)
+			names[f.Name()] = true
+			if f.Exported() {
+				t.Fields = append(t.Fields, &Member{Name: f.Name(), Text: memberText(f, exprs, qf)})
+			}
+		}
+
+		valueSet := types.NewMethodSet(n)
+		mset := types.NewMethodSet(types.NewPointer(n))
+		for i := 0; i < mset.Len(); i++ {
+			sel := mset.At(i)
+			f, ok := sel.Obj().(*types.Func)
+			if !ok || len(sel.Index()) == 1 || !f.Exported() || names[f.Name()] {
+				continue
+			}
+			sig := f.Type().(*types.Signature)
+			recv, _ := derefNamed(sig.Recv().Type())
+			if recv == nil {
+				continue
+			}
+			m := &Member{
+				Name:   f.Name(),
+				Method: true,
+				Text:   strings.TrimPrefix(types.TypeString(sig, qf), "func"),
+				From:   b.namedRef(recv),
+			}
+			m.From.Ptr = valueSet.Lookup(f.Pkg(), f.Name()) == nil
+			t.Promoted = append(t.Promoted, m)
+			names[f.Name()] = true
+		}
+
+		b.promotedFields(t, n, names, exprs, qf)
+	}
+}
+
+// promotedFields adds the exported fields promoted to t from embedded
+// structs. The embedded types are searched breadth first so that shallower
+// fields shadow deeper ones. Fields with the same name at the same depth
+// are ambiguous and omitted. Embedded types from other packages are added
+// to ExternalEmbeds.
+func (b *builder) promotedFields(t *Type, n *types.Named, names map[string]bool, exprs map[*types.Var]ast.Expr, qf types.Qualifier) {
+	type embedded struct {
+		named *types.Named
+		ptr   bool
+	}
+
+	visited := map[*types.Named]bool{n: true}
+	external := make(map[TypeRef]bool)
+	var level []embedded
+	addEmbedded := func(st *types.Struct, ptr bool) {
+		for i := 0; i < st.NumFields(); i++ {
+			f := st.Field(i)
+			if !f.Embedded() {
+				continue
+			}
+			e, isPtr := derefNamed(f.Type())
+			if e == nil || visited[e] {
+				continue
+			}
+			visited[e] = true
+			level = append(level, embedded{e, ptr || isPtr})
+		}
+	}
+	addEmbedded(n.Underlying().(*types.Struct), false)
+
+	for len(level) > 0 {
+		current := level
+		level = nil
+		found := make(map[string][]*Member)
+		for _, e := range current {
+			ref := b.namedRef(e.named)
+			if ref.Path != b.tpkg.Path() {
+				ref.Ptr = e.ptr
+				if !external[ref] {
+					external[ref] = true
+					t.ExternalEmbeds = append(t.ExternalEmbeds, &TypeRef{Path: ref.Path, Name: ref.Name, Ptr: ref.Ptr})
+				}
+				continue
+			}
+			st, ok := e.named.Underlying().(*types.Struct)
+			if !ok {
+				continue
+			}
+			for i := 0; i < st.NumFields(); i++ {
+				f := st.Field(i)
+				if !f.Exported() || names[f.Name()] {
+					continue
+				}
+				found[f.Name()] = append(found[f.Name()], &Member{
+					Name: f.Name(),
+					Text: memberText(f, exprs, qf),
+					From: ref,
+				})
+			}
+			addEmbedded(st, e.ptr)
+		}
+		for _, e := range current {
+			st, ok := e.named.Underlying().(*types.Struct)
+			if !ok {
+				continue
+			}
+			for i := 0; i < st.NumFields(); i++ {
+				name := st.Field(i).Name()
+				if fields := found[name]; len(fields) == 1 {
+					t.Promoted = append(t.Promoted, fields[0])
+					delete(found, name)
+				}
+				names[name] = true
+			}
+		}
+	}
+}
@@ -0,0 +1,173 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file or at
+// https://developers.google.com/open-source/licenses/bsd.
+
+package doc
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+const promotedSource = `package p
+
+import (
+	"bytes"
+	"io"
+)
+
+type Base struct {
+	ID   int
+	Name string
+}
+
+func (b *Base) SetName(name string) { b.Name = name }
+func (b Base) Key() string          { return "" }
+
+type inner struct {
+	Count int
+	Name  string
+}
+
+func (inner) Reset() {}
+
+type Other struct {
+	Count int
+}
+
+type Item struct {
+	Base
+	inner
+	*Other
+	io.Reader
+	*bytes.Buffer
+
+	Key string
+}
+`
+
+func TestPromoted(t *testing.T) {
+	const path = "example.com/p"
+	pkg := newTestPackage(t, path, map[string]string{"p.go": promotedSource})
+	item := findType(pkg, "Item")
+	if item == nil {
+		t.Fatal("type Item not found")
+	}
+
+	fields := []*Member{
+		{Name: "Base", Text: "Base"},
+		{Name: "Other", Text: "*Other"},
+		{Name: "Reader", Text: "io.Reader"},
+		{Name: "Buffer", Text: "*bytes.Buffer"},
+		{Name: "Key", Text: "string"},
+	}
+	if !cmp.Equal(item.Fields, fields) {
+		t.Errorf("Fields = %v, want %v", item.Fields, fields)
+	}
+
+	promoted := []*Member{
+		{Name: "Read", Method: true, Text: "(p []byte) (n int, err error)", From: TypeRef{Path: "io", Name: "Reader"}},
+		{Name: "SetName", Method: true, Text: "(name string)", From: TypeRef{Path: path, Name: "Base", Ptr: true}},
+		{Name: "ID", Text: "int", From: TypeRef{Path: path, Name: "Base"}},
+	}
+	if !cmp.Equal(item.Promoted, promoted) {
+		t.Errorf("Promoted = %v, want %v", item.Promoted, promoted)
+	}
+
+	external := []*TypeRef{
+		{Path: "io", Name: "Reader"},
+		{Path: "bytes", Name: "Buffer", Ptr: true},
+	}
+	if !cmp.Equal(item.ExternalEmbeds, external) {
+		t.Errorf("ExternalEmbeds = %v, want %v", item.ExternalEmbeds, external)
+	}
+
+	var methods []string
+	for _, m := range item.Methods {
+		methods = append(methods, m.Name)
+	}
+	if want := []string{"Reset"}; !cmp.Equal(methods, want) {
+		t.Errorf("Methods = %v, want %v", methods, want)
+	}
+}
+
+func TestPromotedImports(t *testing.T) {
+	tests := []struct {
+		name     string
+		src      string
+		fields   []*Member
+		external []*TypeRef
+	}{
+		{
+			name: "unknown package",
+			src: `package p
+
+import "example.com/dep"
+
+type T struct {
+	dep.Base
+	N int
+}
+`,
+			fields: []*Member{
+				{Name: "Base", Text: "dep.Base"},
+				{Name: "N", Text: "int"},
+			},
+			external: []*TypeRef{{Path: "example.com/dep", Name: "Base"}},
+		},
+		{
+			name: "bad import",
+			src: `package p
+
+import x "example.com/a-b/"
+
+type T struct {
+	x.Base
+}
+`,
+			fields: []*Member{{Name: "Base", Text: "x.Base"}},
+		},
+	}
+	for _, tt := range tests {
+		pkg := newTestPackage(t, "example.com/p", map[string]string{"p.go": tt.src})
+		typ := findType(pkg, "T")
+		if typ == nil {
+			t.Errorf("%s: type T not found", tt.name)
+			continue
+		}
+		if !cmp.Equal(typ.Fields, tt.fields) {
+			t.Errorf("%s: Fields = %v, want %v", tt.name, typ.Fields, tt.fields)
+		}
+		if !cmp.Equal(typ.ExternalEmbeds, tt.external) {
+			t.Errorf("%s: ExternalEmbeds = %v, want %v", tt.name, typ.ExternalEmbeds, tt.external)
+		}
+	}
+}
+
+func TestPromotedPlatforms(t *testing.T) {
+	const path = "example.com/p"
+	pkg := newTestPackage(t, path, map[string]string{
+		"p.go":         "package p\n\ntype T struct {\n\tinner\n}\n",
+		"p_linux.go":   "package p\n\ntype inner struct{ Fd int }\n",
+		"p_darwin.go":  "package p\n\ntype inner struct{ Fd int }\n",
+		"p_windows.go": "package p\n\ntype inner struct{ Handle uintptr }\n",
+		"p_js.go":      "package p\n\ntype inner struct{ Fd string }\n",
+	})
+	typ := findType(pkg, "T")
+	if typ == nil {
+		t.Fatal("type T not found")
+	}
+
+	from := TypeRef{Path: path, Name: "inner"}
+	want := []*Member{
+		{Name: "Fd", Text: "int", From: from, Platforms: []string{"linux/amd64", "darwin/amd64"}},
+		{Name: "Handle", Text: "uintptr", From: from, Platforms: []string{"windows/amd64"}},
+		{Name: "Fd", Text: "string", From: from, Platforms: []string{"js/wasm"}},
+	}
+	if !cmp.Equal(typ.Promoted, want) {
+		t.Errorf("Promoted = %v, want %v", typ.Promoted, want)
+	}
+}
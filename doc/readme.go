@@ -0,0 +1,400 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file or at
+// https://developers.google.com/open-source/licenses/bsd.
+
+package doc
+
+import (
+	"bytes"
+	"html"
+	"net/url"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/golang/gddo/gosrc"
+)
+
+// README is a README file rendered as HTML.
+type README struct {
+	Name string // File name.
+	URL  string // Browse URL of the file.
+	HTML string // Sanitized HTML.
+}
+
+// maxReadmeSize is the size in bytes of the largest README file rendered.
+const maxReadmeSize = 256 << 10
+
+// Kinds of README files in order of preference.
+const (
+	markdownReadme = iota
+	rstReadme
+	plainReadme
+	unknownReadme
+)
+
+var readmePat = regexp.MustCompile(`(?i)^readme(?:$|\.)`)
+
+func readmeKind(name string) int {
+	switch strings.ToLower(path.Ext(name)) {
+	case ".md", ".markdown", ".mdown", ".mkd", ".mkdn":
+		return markdownReadme
+	case ".rst", ".rest":
+		return rstReadme
+	case "", ".txt", ".text":
+		return plainReadme
+	}
+	return unknownReadme
+}
+
+// newREADME returns the README file of the directory rendered as HTML or nil
+// if the directory does not have a README file in a supported format.
+// Relative links and images are resolved against the browse URL of the
+// directory.
+func newREADME(dir *gosrc.Directory) *README {
+	var file *gosrc.File
+	kind := unknownReadme
+	for _, f := range dir.Files {
+		if !readmePat.MatchString(f.Name) || len(f.Data) > maxReadmeSize {
+			continue
+		}
+		k := readmeKind(f.Name)
+		if k < kind || (k == kind && file != nil && len(f.Name) < len(file.Name)) {
+			file, kind = f, k
+		}
+	}
+	if file == nil {
+		return nil
+	}
+
+	r := &readmeRenderer{ids: make(map[string]bool)}
+	if dir.BrowseURL != "" {
+		if u, err := url.Parse(dir.BrowseURL); err == nil && u.IsAbs() {
+			if !strings.HasSuffix(u.Path, "/") {
+				u.Path += "/"
+			}
+			r.base = u
+		}
+	}
+	data := strings.Replace(string(bytes.ToValidUTF8(file.Data, []byte("�"))), "\r\n", "\n", -1)
+	switch kind {
+	case markdownReadme:
+		r.markdown(data)
+	case rstReadme:
+		r.rst(data)
+	default:
+		r.plain(data)
+	}
+	return &README{Name: file.Name, URL: file.BrowseURL, HTML: r.buf.String()}
+}
+
+// readmeRenderer holds the state shared by the README formats.
+type readmeRenderer struct {
+	buf  bytes.Buffer
+	base *url.URL
+	ids  map[string]bool
+}
+
+// url returns the sanitized URL for a link or image or "" if the URL is not
+// allowed. Fragments refer to the headings of the README.
+func (r *readmeRenderer) url(s string, image bool) string {
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(s, "#") {
+		if image || len(s) == 1 {
+			return ""
+		}
+		return "#readme-" + s[1:]
+	}
+	u, err := url.Parse(s)
+	if err != nil || s == "" {
+		return ""
+	}
+	switch {
+	case u.Scheme == "" && u.Host != "":
+		u.Scheme = "https"
+	case u.Scheme == "":
+		if r.base == nil {
+			return ""
+		}
+		u = r.base.ResolveReference(u)
+		if image && u.Host == "github.com" {
+			// Serve the file instead of the GitHub page for the file.
+			if p := strings.SplitN(u.Path, "/", 5); len(p) == 5 && (p[3] == "tree" || p[3] == "blob") {
+				p[3] = "raw"
+				u.Path = strings.Join(p, "/")
+			}
+		}
+	case u.Scheme == "http" || u.Scheme == "https":
+	case u.Scheme == "mailto" && !image:
+	default:
+		return ""
+	}
+	return u.String()
+}
+
+// heading writes a heading with an id derived from the heading text.
+func (r *readmeRenderer) heading(level int, content string) {
+	id := r.headingID(html.UnescapeString(tagPat.ReplaceAllString(content, "")))
+	h := strconv.Itoa(level)
+	r.buf.WriteString(`<h` + h + ` id="readme-` + html.EscapeString(id) + `">` + content + "</h" + h + ">\n")
+}
+
+// headingID returns the id of a heading with the given text, without the
+// readme- prefix. The ids of the headings of a README are distinct.
+func (r *readmeRenderer) headingID(text string) string {
+	var id []rune
+	for _, c := range strings.ToLower(strings.TrimSpace(text)) {
+		switch {
+		case unicode.IsLetter(c) || unicode.IsDigit(c) || c == '-' || c == '_':
+			id = append(id, c)
+		case c == ' ':
+			id = append(id, '-')
+		}
+	}
+	base := string(id)
+	s := base
+	for i := 1; r.ids[s]; i++ {
+		s = base + "-" + strconv.Itoa(i)
+	}
+	r.ids[s] = true
+	return s
+}
+
+// plain renders a plain text README. URLs in the text are linked.
+func (r *readmeRenderer) plain(s string) {
+	r.buf.WriteString("<pre>")
+	r.buf.WriteString(r.autolink(s))
+	r.buf.WriteString("</pre>\n")
+}
+
+var bareURLPat = regexp.MustCompile(`\bhttps?://[^\s<>"]*[^\s<>".,:;'!?)\]*_]`)
+
+// autolink escapes s and links the URLs in s.
+func (r *readmeRenderer) autolink(s string) string {
+	var buf strings.Builder
+	last := 0
+	for _, m := range bareURLPat.FindAllStringIndex(s, -1) {
+		buf.WriteString(html.EscapeString(s[last:m[0]]))
+		text := html.EscapeString(s[m[0]:m[1]])
+		if u := r.url(s[m[0]:m[1]], false); u != "" {
+			buf.WriteString(`<a href="` + html.EscapeString(u) + `">` + text + "</a>")
+		} else {
+			buf.WriteString(text)
+		}
+		last = m[1]
+	}
+	buf.WriteString(html.EscapeString(s[last:]))
+	return buf.String()
+}
+
+var (
+	tagPat = regexp.MustCompile(`<[^>]*>`)
+
+	// htmlTokenPat matches comments, tags and text in HTML.
+	htmlTokenPat = regexp.MustCompile(`(?s)<!--.*?(?:-->|$)|</?[a-zA-Z][a-zA-Z0-9]*(?:\s+[^\s"'>/=]+(?:\s*=\s*(?:"[^"]*"|'[^']*'|[^\s"'=<>` + "`" + `]+))?)*\s*/?>|<|[^<]+`)
+	htmlTagPat   = regexp.MustCompile(`^<(/?)([a-zA-Z][a-zA-Z0-9]*)`)
+	htmlAttrPat  = regexp.MustCompile(`([^\s"'>/=]+)(?:\s*=\s*(?:"([^"]*)"|'([^']*)'|([^\s"'=<>` + "`" + `]+)))?`)
+)
+
+// Elements allowed in README files and their allowed attributes.
+var readmeElements = map[string][]string{
+	"a":          {"href", "title"},
+	"abbr":       {"title"},
+	"b":          nil,
+	"blockquote": nil,
+	"br":         nil,
+	"code":       {"class"},
+	"dd":         nil,
+	"del":        nil,
+	"details":    nil,
+	"div":        {"align"},
+	"dl":         nil,
+	"dt":         nil,
+	"em":         nil,
+	"h1":         {"align", "id"},
+	"h2":         {"align", "id"},
+	"h3":         {"align", "id"},
+	"h4":         {"align", "id"},
+	"h5":         {"align", "id"},
+	"h6":         {"align", "id"},
+	"hr":         nil,
+	"i":          nil,
+	"img":        {"src", "alt", "title", "width", "height", "align"},
+	"ins":        nil,
+	"kbd":        nil,
+	"li":         nil,
+	"ol":         nil,
+	"p":          {"align"},
+	"pre":        nil,
+	"s":          nil,
+	"samp":       nil,
+	"small":      nil,
+	"span":       nil,
+	"strike":     nil,
+	"strong":     nil,
+	"sub":        nil,
+	"summary":    nil,
+	"sup":        nil,
+	"table":      nil,
+	"tbody":      nil,
+	"td":         {"align", "colspan", "rowspan"},
+	"tfoot":      nil,
+	"th":         {"align", "colspan", "rowspan"},
+	"thead":      nil,
+	"tr":         nil,
+	"tt":         nil,
+	"u":          nil,
+	"ul":         nil,
+}
+
+// Elements without end tags.
+var voidElements = map[string]bool{"br": true, "hr": true, "img": true}
+
+// Elements dropped with their content.
+var droppedElements = map[string]bool{
+	"iframe": true, "math": true, "noscript": true, "object": true, "script": true,
+	"style": true, "svg": true, "template": true, "textarea": true, "title": true,
+}
+
+var (
+	sizePat      = regexp.MustCompile(`^[0-9]+%?$`)
+	alignPat     = regexp.MustCompile(`(?i)^(left|right|center|justify|top|middle|bottom)$`)
+	langClassPat = regexp.MustCompile(`^language-[\w+#.-]+$`)
+)
+
+// htmlSanitizer rewrites HTML embedded in a README. Allowed elements are
+// written with their allowed attributes, other elements are removed and
+// text is escaped. Elements opened by the sanitizer are closed by the
+// sanitizer.
+type htmlSanitizer struct {
+	r       *readmeRenderer
+	stack   []string
+	dropped string // element whose content is dropped
+}
+
+// write writes the sanitized HTML fragment s to buf.
+func (z *htmlSanitizer) write(buf *strings.Builder, s string) {
+	for _, tok := range htmlTokenPat.FindAllString(s, -1) {
+		m := htmlTagPat.FindStringSubmatch(tok)
+		if z.dropped != "" {
+			if m != nil && m[1] == "/" && strings.ToLower(m[2]) == z.dropped {
+				z.dropped = ""
+			}
+			continue
+		}
+		switch {
+		case strings.HasPrefix(tok, "<!--"):
+		case m == nil:
+			buf.WriteString(html.EscapeString(html.UnescapeString(tok)))
+		case m[1] == "/":
+			z.end(buf, strings.ToLower(m[2]))
+		default:
+			z.start(buf, strings.ToLower(m[2]), tok[len(m[0]):])
+		}
+	}
+}
+
+func (z *htmlSanitizer) start(buf *strings.Builder, name, attrs string) {
+	if droppedElements[name] {
+		if !strings.HasSuffix(attrs, "/>") {
+			z.dropped = name
+		}
+		return
+	}
+	allowed, ok := readmeElements[name]
+	if !ok {
+		return
+	}
+	var tag strings.Builder
+	tag.WriteString("<" + name)
+	hasURL := false
+	for _, m := range htmlAttrPat.FindAllStringSubmatch(attrs, -1) {
+		key := strings.ToLower(m[1])
+		value := html.UnescapeString(m[2] + m[3] + m[4])
+		if !stringIn(key, allowed) {
+			continue
+		}
+		switch key {
+		case "href":
+			value = z.r.url(value, false)
+			hasURL = value != ""
+		case "src":
+			value = z.r.url(value, true)
+			hasURL = value != ""
+		case "width", "height", "colspan", "rowspan":
+			if !sizePat.MatchString(value) {
+				value = ""
+			}
+		case "align":
+			if !alignPat.MatchString(value) {
+				value = ""
+			}
+		case "class":
+			if !langClassPat.MatchString(value) {
+				value = ""
+			}
+		case "id":
+			// Only the ids of the headings rendered from the README.
+			if !strings.HasPrefix(value, "readme-") {
+				value = ""
+			}
+		}
+		if value != "" {
+			tag.WriteString(" " + key + `="` + html.EscapeString(value) + `"`)
+		}
+	}
+	if (name == "a" || name == "img") && !hasURL {
+		// Links and images without an allowed URL are removed.
+		return
+	}
+	if name == "table" {
+		tag.WriteString(` class="table table-condensed"`)
+	}
+	buf.WriteString(tag.String() + ">")
+	if !voidElements[name] {
+		z.stack = append(z.stack, name)
+	}
+}
+
+func (z *htmlSanitizer) end(buf *strings.Builder, name string) {
+	for i := len(z.stack) - 1; i >= 0; i-- {
+		if z.stack[i] == name {
+			for len(z.stack) > i {
+				buf.WriteString("</" + z.stack[len(z.stack)-1] + ">")
+				z.stack = z.stack[:len(z.stack)-1]
+			}
+			return
+		}
+	}
+}
+
+// close closes the open elements.
+func (z *htmlSanitizer) close(buf *strings.Builder) {
+	for i := len(z.stack) - 1; i >= 0; i-- {
+		buf.WriteString("</" + z.stack[i] + ">")
+	}
+	z.stack = nil
+	z.dropped = ""
+}
+
+// sanitize returns the sanitized HTML block s.
+func (r *readmeRenderer) sanitize(s string) string {
+	var buf strings.Builder
+	z := htmlSanitizer{r: r}
+	z.write(&buf, s)
+	z.close(&buf)
+	return buf.String()
+}
+
+func stringIn(s string, list []string) bool {
+	for _, t := range list {
+		if s == t {
+			return true
+		}
+	}
+	return false
+}
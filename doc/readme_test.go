@@ -0,0 +1,152 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file or at
+// https://developers.google.com/open-source/licenses/bsd.
+
+package doc
+
+import (
+	"testing"
+
+	"github.com/golang/gddo/gosrc"
+)
+
+var readmeTests = []struct {
+	name, data, want string
+}{
+	{
+		"README.md",
+		"# Title\n\nSome *emph*, **strong** and `code`.\n",
+		"<h1 id=\"readme-title\">Title</h1>\n<p>Some <em>emph</em>, <strong>strong</strong> and <code>code</code>.</p>\n",
+	},
+	{
+		"README.md",
+		"[![Badge][img]][ci] [doc](doc/usage.md#install) [top](#title) ![logo](logo.png)\n\n[img]: https://ci.example.com/badge.svg\n[ci]: https://ci.example.com/\n",
+		"<p><a href=\"https://ci.example.com/\"><img src=\"https://ci.example.com/badge.svg\" alt=\"Badge\"></a> " +
+			"<a href=\"https://github.com/user/repo/tree/master/pkg/doc/usage.md#install\">doc</a> " +
+			"<a href=\"#readme-title\">top</a> " +
+			"<img src=\"https://github.com/user/repo/raw/master/pkg/logo.png\" alt=\"logo\"></p>\n",
+	},
+	{
+		"README.md",
+		"[x](javascript:alert(1)) <b onclick=\"f()\">b</b> <script>alert(1)</script> &copy;\n",
+		"<p>x <b>b</b>  ©</p>\n",
+	},
+	{
+		"README.md",
+		"<div align=\"center\" style=\"x\">\n<img src=\"logo.png\" width=\"100\" height=\"x\">\n</div>\n</div>\n",
+		"<div align=\"center\">\n<img src=\"https://github.com/user/repo/raw/master/pkg/logo.png\" width=\"100\">\n</div>\n\n",
+	},
+	{
+		"README.md",
+		"Install\n-------\n\n```sh\ngo get <pkg>\n```\n\n    indented\n",
+		"<h2 id=\"readme-install\">Install</h2>\n<pre><code class=\"language-sh\">go get &lt;pkg&gt;\n</code></pre>\n<pre><code>indented\n</code></pre>\n",
+	},
+	{
+		"README.md",
+		"- a\n- b\n  - c\n\n1. x\n\n2. y\n",
+		"<ul>\n<li>a</li>\n<li>b\n<ul>\n<li>c</li>\n</ul>\n</li>\n</ul>\n<ol>\n<li>\n<p>x</p>\n</li>\n<li>\n<p>y</p>\n</li>\n</ol>\n",
+	},
+	{
+		"README.md",
+		"> quote\n\n| A | B |\n|---|--:|\n| 1 | 2 |\n\n---\n",
+		"<blockquote>\n<p>quote</p>\n</blockquote>\n" +
+			"<table class=\"table table-condensed\">\n<thead>\n<tr>\n<th>A</th>\n<th align=\"right\">B</th>\n</tr>\n</thead>\n" +
+			"<tbody>\n<tr>\n<td>1</td>\n<td align=\"right\">2</td>\n</tr>\n</tbody>\n</table>\n<hr>\n",
+	},
+	{
+		"README.md",
+		"# Usage\n\n## Usage\n\n## `go get` & *more*\n\n[usage](#usage-1)\n",
+		"<h1 id=\"readme-usage\">Usage</h1>\n<h2 id=\"readme-usage-1\">Usage</h2>\n" +
+			"<h2 id=\"readme-go-get--more\"><code>go get</code> &amp; <em>more</em></h2>\n" +
+			"<p><a href=\"#readme-usage-1\">usage</a></p>\n",
+	},
+	{
+		"README.md",
+		"~~old~~ snake_case_name **a *b* c** \\*not\\* ``a ` b`` line  \nbreak\n",
+		"<p><del>old</del> snake_case_name <strong>a <em>b</em> c</strong> *not* <code>a ` b</code> line<br>\nbreak</p>\n",
+	},
+	{
+		"README.md",
+		"See https://example.com/a, <mailto:me@example.com> and [t](http://example.com/ \"Title\").\n",
+		"<p>See <a href=\"https://example.com/a\">https://example.com/a</a>, " +
+			"<a href=\"mailto:me@example.com\">mailto:me@example.com</a> and " +
+			"<a href=\"http://example.com/\" title=\"Title\">t</a>.</p>\n",
+	},
+	{
+		"README.md",
+		"![x](javascript:alert(1)) ![y](#top) <a href=\"vbscript:x\" title=\"t\">v</a> <img src=\"data:x\">\n",
+		"<p>  v </p>\n",
+	},
+	{
+		"README.md",
+		"<p align=\"center\">\n\n# Logo\n\n</p>\n\n<h2 id=\"other\" class=\"x\">Raw</h2>\n\n<style>p { color: red }</style>\n\ntext\n",
+		"<p align=\"center\">\n<h1 id=\"readme-logo\">Logo</h1>\n</p>\n<h2>Raw</h2>\n\n<p>text</p>\n",
+	},
+	{
+		"README.md",
+		"```js\nalert(1)\n```\n\n<pre><code class=\"javascript:x\">x</code></pre>\n\n1) one\n2) two\n\n* [link *em*](a.md)\n",
+		"<pre><code class=\"language-js\">alert(1)\n</code></pre>\n<pre><code>x</code></pre>\n" +
+			"<ol>\n<li>one</li>\n<li>two</li>\n</ol>\n" +
+			"<ul>\n<li><a href=\"https://github.com/user/repo/tree/master/pkg/a.md\">link <em>em</em></a></li>\n</ul>\n",
+	},
+	{
+		"README.rst",
+		"=====\nTitle\n=====\n\n|build|\n\n.. |build| image:: https://ci.example.com/badge.svg\n   :target: https://ci.example.com/\n\nSub\n---\n\n*emph* **strong** ``code`` `link <http://example.com/>`_ Ref_\n\n.. _Ref: http://example.com/ref\n",
+		"<h1 id=\"readme-title\">Title</h1>\n" +
+			"<p><a href=\"https://ci.example.com/\"><img src=\"https://ci.example.com/badge.svg\" alt=\"\"></a></p>\n" +
+			"<h2 id=\"readme-sub\">Sub</h2>\n" +
+			"<p><em>emph</em> <strong>strong</strong> <code>code</code> <a href=\"http://example.com/\">link</a> <a href=\"http://example.com/ref\">Ref</a></p>\n",
+	},
+	{
+		"README.rst",
+		"- a\n- b\n\nExample::\n\n    go get <pkg>\n\n.. note:: Careful.\n",
+		"<ul>\n<li>a</li>\n<li>b</li>\n</ul>\n<p>Example:</p>\n<pre><code>go get &lt;pkg&gt;</code></pre>\n" +
+			"<blockquote>\n<p><strong>Note</strong></p>\n<p>Careful.</p>\n</blockquote>\n",
+	},
+	{
+		"README",
+		"See <http://example.com/a>.\n",
+		"<pre>See &lt;<a href=\"http://example.com/a\">http://example.com/a</a>&gt;.\n</pre>\n",
+	},
+}
+
+func TestREADME(t *testing.T) {
+	for _, tt := range readmeTests {
+		dir := &gosrc.Directory{
+			BrowseURL: "https://github.com/user/repo/tree/master/pkg",
+			Files:     []*gosrc.File{{Name: tt.name, Data: []byte(tt.data)}},
+		}
+		r := newREADME(dir)
+		if r == nil {
+			t.Errorf("newREADME(%q) = nil", tt.data)
+			continue
+		}
+		if r.HTML != tt.want {
+			t.Errorf("newREADME(%q)\n got: %q\nwant: %q", tt.data, r.HTML, tt.want)
+		}
+	}
+}
+
+func TestREADMEChoice(t *testing.T) {
+	dir := &gosrc.Directory{
+		Files: []*gosrc.File{
+			{Name: "readme.txt", Data: []byte("text")},
+			{Name: "README.zh.md", Data: []byte("zh")},
+			{Name: "README.md", Data: []byte("[a](a.md)")},
+			{Name: "README.html", Data: []byte("html")},
+		},
+	}
+	r := newREADME(dir)
+	if r == nil || r.Name != "README.md" {
+		t.Fatalf("newREADME chose %+v, want README.md", r)
+	}
+	// Relative links are dropped without a browse URL.
+	if want := "<p>a</p>\n"; r.HTML != want {
+		t.Errorf("HTML = %q, want %q", r.HTML, want)
+	}
+	if r := newREADME(&gosrc.Directory{Files: []*gosrc.File{{Name: "main.go"}}}); r != nil {
+		t.Errorf("newREADME returned %+v for directory without README", r)
+	}
+}
@@ -0,0 +1,567 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file or at
+// https://developers.google.com/open-source/licenses/bsd.
+
+package doc
+
+import (
+	"html"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// This file renders the commonly used subset of reStructuredText: section
+// titles, transitions, paragraphs, literal blocks, bullet, enumerated and
+// definition lists, block quotes, line blocks, the image, figure, code and
+// admonition directives, hyperlink targets, substitutions and inline markup.
+
+var (
+	rstBulletPat    = regexp.MustCompile(`^([-*+•‣⁃]) +`)
+	rstEnumPat      = regexp.MustCompile(`^(?:[0-9]+|#)[.)] +|^\((?:[0-9]+|#)\) +`)
+	rstDirectivePat = regexp.MustCompile(`^\.\.\s+([a-zA-Z][\w-]*)::\s*(.*)$`)
+	rstTargetPat    = regexp.MustCompile("^\\.\\.\\s+_(`[^`]+`|[^:]+):\\s*(\\S*)\\s*$")
+	rstSubstPat     = regexp.MustCompile(`^\.\.\s+\|([^|]+)\|\s+([a-zA-Z][\w-]*)::\s*(.*)$`)
+	rstOptionPat    = regexp.MustCompile(`^\s+:([\w-]+):\s*(.*)$`)
+	rstRolePat      = regexp.MustCompile("^:([a-zA-Z][\\w+.-]*):`([^`]+)`")
+	rstRefPat       = regexp.MustCompile(`^([A-Za-z0-9][A-Za-z0-9.+-]*)(__?)`)
+	rstSubstRefPat  = regexp.MustCompile(`^\|([^|\s](?:[^|]*[^|\s])?)\|(__?)?`)
+)
+
+var rstAdmonitions = map[string]string{
+	"attention": "Attention",
+	"caution":   "Caution",
+	"danger":    "Danger",
+	"error":     "Error",
+	"hint":      "Hint",
+	"important": "Important",
+	"note":      "Note",
+	"tip":       "Tip",
+	"warning":   "Warning",
+}
+
+type rstRenderer struct {
+	*readmeRenderer
+	targets map[string]string // hyperlink targets by normalized name
+	subs    map[string]string // substitutions by name
+	styles  []string          // section title styles in order of appearance
+}
+
+// rst renders a reStructuredText README.
+func (r *readmeRenderer) rst(s string) {
+	t := &rstRenderer{
+		readmeRenderer: r,
+		targets:        make(map[string]string),
+		subs:           make(map[string]string),
+	}
+	lines := strings.Split(expandTabs(s), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " ")
+	}
+	for _, line := range lines {
+		if m := rstTargetPat.FindStringSubmatch(line); m != nil && m[2] != "" {
+			t.targets[rstName(strings.Trim(m[1], "`"))] = m[2]
+		}
+	}
+	for i, line := range lines {
+		if m := rstSubstPat.FindStringSubmatch(line); m != nil {
+			_, body := explicitBlock(lines, i)
+			t.subs[m[1]] = t.substitution(m[2], m[3], body)
+		}
+	}
+	t.blocks(lines)
+}
+
+func expandTabs(s string) string {
+	if !strings.Contains(s, "\t") {
+		return s
+	}
+	var buf strings.Builder
+	col := 0
+	for _, c := range s {
+		switch c {
+		case '\t':
+			n := 4 - col%4
+			buf.WriteString(strings.Repeat(" ", n))
+			col += n
+		case '\n':
+			buf.WriteRune(c)
+			col = 0
+		default:
+			buf.WriteRune(c)
+			col++
+		}
+	}
+	return buf.String()
+}
+
+func indent(line string) int { return len(line) - len(strings.TrimLeft(line, " ")) }
+
+func isWordByte(c byte) bool {
+	return c == '_' || '0' <= c && c <= '9' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || c >= 0x80
+}
+
+func isSpaceByte(c byte) bool {
+	return c == ' ' || c == '\n' || c == '\t'
+}
+
+// rstName normalizes a reference name.
+func rstName(s string) string {
+	return strings.ToLower(strings.Join(strings.Fields(s), " "))
+}
+
+// isAdornment returns true if line is a section title adornment or a
+// transition: a line of at least three repeated punctuation characters.
+func isAdornment(line string) bool {
+	line = strings.TrimRight(line, " ")
+	if len(line) < 3 {
+		return false
+	}
+	c := line[0]
+	if c > '~' || !unicode.IsPunct(rune(c)) && !unicode.IsSymbol(rune(c)) {
+		return false
+	}
+	return strings.Count(line, line[:1]) == len(line)
+}
+
+// explicitBlock returns the end of the explicit markup block starting at
+// line i and the indented lines of the block.
+func explicitBlock(lines []string, i int) (int, []string) {
+	j := i + 1
+	for ; j < len(lines) && (lines[j] == "" || indent(lines[j]) > 0); j++ {
+	}
+	for j > i+1 && lines[j-1] == "" {
+		j--
+	}
+	return j, lines[i+1 : j]
+}
+
+// options splits the body of a directive into options and content.
+func options(body []string) (map[string]string, []string) {
+	opts := make(map[string]string)
+	i := 0
+	for ; i < len(body); i++ {
+		m := rstOptionPat.FindStringSubmatch(body[i])
+		if m == nil {
+			break
+		}
+		opts[m[1]] = m[2]
+	}
+	return opts, dedent(body[i:])
+}
+
+// dedent removes the common indentation of lines and the surrounding blank
+// lines.
+func dedent(lines []string) []string {
+	for len(lines) > 0 && lines[0] == "" {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	n := -1
+	for _, line := range lines {
+		if line != "" && (n < 0 || indent(line) < n) {
+			n = indent(line)
+		}
+	}
+	result := make([]string, len(lines))
+	for i, line := range lines {
+		if line != "" {
+			result[i] = line[n:]
+		}
+	}
+	return result
+}
+
+func (t *rstRenderer) image(src string, opts map[string]string) string {
+	u := t.url(src, true)
+	if u == "" {
+		return ""
+	}
+	s := `<img src="` + html.EscapeString(u) + `" alt="` + html.EscapeString(opts["alt"]) + `"`
+	for _, key := range []string{"width", "height"} {
+		if v := opts[key]; sizePat.MatchString(v) {
+			s += " " + key + `="` + v + `"`
+		}
+	}
+	if v := opts["align"]; alignPat.MatchString(v) {
+		s += ` align="` + strings.ToLower(v) + `"`
+	}
+	s += ">"
+	if target := opts["target"]; target != "" {
+		if strings.HasSuffix(target, "_") {
+			target = t.targets[rstName(strings.Trim(target, "`_"))]
+		}
+		if u := t.url(target, false); u != "" {
+			s = `<a href="` + html.EscapeString(u) + `">` + s + "</a>"
+		}
+	}
+	return s
+}
+
+func (t *rstRenderer) substitution(directive, arg string, body []string) string {
+	switch directive {
+	case "image":
+		opts, _ := options(body)
+		return t.image(arg, opts)
+	case "replace":
+		return t.inline(strings.Join(append([]string{arg}, dedent(body)...), " "))
+	}
+	return ""
+}
+
+func (t *rstRenderer) title(level string, text string) {
+	n := 0
+	for ; n < len(t.styles) && t.styles[n] != level; n++ {
+	}
+	if n == len(t.styles) {
+		t.styles = append(t.styles, level)
+	}
+	if n >= 6 {
+		n = 5
+	}
+	t.heading(n+1, t.inline(strings.TrimSpace(text)))
+}
+
+func (t *rstRenderer) blocks(lines []string) {
+	for i := 0; i < len(lines); {
+		line := lines[i]
+		switch {
+		case line == "":
+			i++
+		case line == ".." || strings.HasPrefix(line, ".. "):
+			i = t.explicit(lines, i)
+		case isAdornment(line) && i+2 < len(lines) && lines[i+2] == line && lines[i+1] != "":
+			t.title(line[:1]+"/"+line[:1], lines[i+1])
+			i += 3
+		case isAdornment(line) && len(line) >= 4 &&
+			(i+1 == len(lines) || lines[i+1] == ""):
+			t.buf.WriteString("<hr>\n")
+			i++
+		case indent(line) == 0 && i+1 < len(lines) && isAdornment(lines[i+1]) &&
+			!isAdornment(line):
+			t.title(lines[i+1][:1], line)
+			i += 2
+		case indent(line) > 0:
+			j := i
+			for ; j < len(lines) && (lines[j] == "" || indent(lines[j]) > 0); j++ {
+			}
+			t.buf.WriteString("<blockquote>\n")
+			t.blocks(dedent(lines[i:j]))
+			t.buf.WriteString("</blockquote>\n")
+			i = j
+		case rstBulletPat.MatchString(line):
+			i = t.list(lines, i, rstBulletPat, "ul")
+		case rstEnumPat.MatchString(line):
+			i = t.list(lines, i, rstEnumPat, "ol")
+		case strings.HasPrefix(line, ">>> "):
+			j := i
+			for ; j < len(lines) && lines[j] != ""; j++ {
+			}
+			t.buf.WriteString("<pre><code>" + html.EscapeString(strings.Join(lines[i:j], "\n")) + "</code></pre>\n")
+			i = j
+		case line == "|" || strings.HasPrefix(line, "| "):
+			var parts []string
+			for ; i < len(lines) && (lines[i] == "|" || strings.HasPrefix(lines[i], "| ")); i++ {
+				parts = append(parts, t.inline(strings.TrimPrefix(strings.TrimPrefix(lines[i], "|"), " ")))
+			}
+			t.buf.WriteString("<p>" + strings.Join(parts, "<br>\n") + "</p>\n")
+		case i+1 < len(lines) && indent(lines[i+1]) > 0:
+			t.buf.WriteString("<dl>\n")
+			for i+1 < len(lines) && indent(lines[i]) == 0 && lines[i] != "" && indent(lines[i+1]) > 0 {
+				j := i + 1
+				for ; j < len(lines) && (lines[j] == "" || indent(lines[j]) > 0); j++ {
+				}
+				t.buf.WriteString("<dt>" + t.inline(lines[i]) + "</dt>\n<dd>\n")
+				t.blocks(dedent(lines[i+1 : j]))
+				t.buf.WriteString("</dd>\n")
+				i = j
+			}
+			t.buf.WriteString("</dl>\n")
+		default:
+			i = t.paragraph(lines, i)
+		}
+	}
+}
+
+func (t *rstRenderer) paragraph(lines []string, i int) int {
+	j := i
+	for ; j < len(lines) && lines[j] != "" && indent(lines[j]) == 0; j++ {
+	}
+	text := strings.Join(lines[i:j], "\n")
+	literal := strings.HasSuffix(text, "::")
+	if literal {
+		switch {
+		case text == "::":
+			text = ""
+		case strings.HasSuffix(text, " ::") || strings.HasSuffix(text, "\n::"):
+			text = strings.TrimRight(text[:len(text)-2], " \n")
+		default:
+			text = text[:len(text)-1]
+		}
+	}
+	if text != "" {
+		t.buf.WriteString("<p>" + t.inline(text) + "</p>\n")
+	}
+	if !literal {
+		return j
+	}
+	k := j
+	for ; k < len(lines) && lines[k] == ""; k++ {
+	}
+	end := k
+	for ; end < len(lines) && (lines[end] == "" || indent(lines[end]) > 0); end++ {
+	}
+	if end > k {
+		t.buf.WriteString("<pre><code>" + html.EscapeString(strings.Join(dedent(lines[k:end]), "\n")) + "</code></pre>\n")
+	}
+	return end
+}
+
+func (t *rstRenderer) list(lines []string, i int, pat *regexp.Regexp, tag string) int {
+	t.buf.WriteString("<" + tag + ">\n")
+	for i < len(lines) {
+		m := pat.FindString(lines[i])
+		if m == "" {
+			break
+		}
+		width := len(m)
+		item := []string{lines[i][width:]}
+		j := i + 1
+		for ; j < len(lines) && (lines[j] == "" || indent(lines[j]) >= width); j++ {
+			item = append(item, lines[j])
+		}
+		item = dedentItem(item, width)
+		t.buf.WriteString("<li>")
+		if len(item) == 1 || !containsBlank(item) {
+			t.buf.WriteString(t.inline(strings.Join(item, "\n")))
+		} else {
+			t.blocks(item)
+		}
+		t.buf.WriteString("</li>\n")
+		for i = j; i < len(lines) && lines[i] == ""; i++ {
+		}
+		if i < len(lines) && !pat.MatchString(lines[i]) {
+			break
+		}
+	}
+	t.buf.WriteString("</" + tag + ">\n")
+	return i
+}
+
+// dedentItem removes the indentation of the lines following the first line
+// of a list item.
+func dedentItem(item []string, width int) []string {
+	for len(item) > 0 && item[len(item)-1] == "" {
+		item = item[:len(item)-1]
+	}
+	for k := 1; k < len(item); k++ {
+		if item[k] != "" {
+			item[k] = item[k][width:]
+		}
+	}
+	return item
+}
+
+func containsBlank(lines []string) bool {
+	for _, line := range lines {
+		if line == "" {
+			return true
+		}
+	}
+	return false
+}
+
+// explicit renders the explicit markup block at line i.
+func (t *rstRenderer) explicit(lines []string, i int) int {
+	end, body := explicitBlock(lines, i)
+	m := rstDirectivePat.FindStringSubmatch(lines[i])
+	if m == nil {
+		// Comments, hyperlink targets and substitution definitions.
+		return end
+	}
+	name := strings.ToLower(m[1])
+	opts, content := options(body)
+	switch {
+	case name == "image" || name == "figure":
+		if img := t.image(m[2], opts); img != "" {
+			t.buf.WriteString("<p>" + img + "</p>\n")
+		}
+		if name == "figure" && len(content) > 0 {
+			t.blocks(content)
+		}
+	case name == "code" || name == "code-block" || name == "sourcecode":
+		t.buf.WriteString("<pre><code")
+		if m[2] != "" {
+			t.buf.WriteString(` class="language-` + html.EscapeString(m[2]) + `"`)
+		}
+		t.buf.WriteString(">" + html.EscapeString(strings.Join(content, "\n")) + "</code></pre>\n")
+	case rstAdmonitions[name] != "":
+		t.buf.WriteString("<blockquote>\n<p><strong>" + rstAdmonitions[name] + "</strong></p>\n")
+		if m[2] != "" {
+			content = append([]string{m[2], ""}, content...)
+		}
+		t.blocks(content)
+		t.buf.WriteString("</blockquote>\n")
+	}
+	return end
+}
+
+// rstStart returns true if inline markup can start at s[i].
+func rstStart(s string, i, n int) bool {
+	if i > 0 && !strings.ContainsRune(" \n'\"([{<-/:", rune(s[i-1])) {
+		return false
+	}
+	return i+n < len(s) && !isSpaceByte(s[i+n])
+}
+
+// rstEnd returns the index of the end string delim in s after i or -1.
+func rstEnd(s string, i int, delim string) int {
+	for j := i; j < len(s); {
+		k := strings.Index(s[j:], delim)
+		if k < 0 {
+			return -1
+		}
+		k += j
+		if k > i && !isSpaceByte(s[k-1]) && s[k-1] != '\\' {
+			return k
+		}
+		j = k + 1
+	}
+	return -1
+}
+
+// inline renders inline markup.
+func (t *rstRenderer) inline(s string) string {
+	var buf strings.Builder
+	text := 0
+	flush := func(i int) {
+		buf.WriteString(html.EscapeString(s[text:i]))
+	}
+	link := func(u, label string) {
+		if u = t.url(u, false); u != "" {
+			buf.WriteString(`<a href="` + html.EscapeString(u) + `">` + label + "</a>")
+		} else {
+			buf.WriteString(label)
+		}
+	}
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == '\\' && i+1 < len(s):
+			flush(i)
+			if !isSpaceByte(s[i+1]) {
+				buf.WriteString(html.EscapeString(s[i+1 : i+2]))
+			}
+			i += 2
+			text = i
+			continue
+		case strings.HasPrefix(s[i:], "``") && rstStart(s, i, 2):
+			if k := rstEnd(s, i+2, "``"); k >= 0 {
+				flush(i)
+				buf.WriteString("<code>" + html.EscapeString(s[i+2:k]) + "</code>")
+				i = k + 2
+				text = i
+				continue
+			}
+		case c == ':' && rstStart(s, i, 1):
+			if m := rstRolePat.FindStringSubmatch(s[i:]); m != nil {
+				flush(i)
+				if m[1] == "code" || m[1] == "literal" {
+					buf.WriteString("<code>" + html.EscapeString(m[2]) + "</code>")
+				} else {
+					buf.WriteString(html.EscapeString(m[2]))
+				}
+				i += len(m[0])
+				text = i
+				continue
+			}
+		case c == '`' && rstStart(s, i, 1):
+			if k := rstEnd(s, i+1, "`"); k >= 0 {
+				flush(i)
+				ref := s[i+1 : k]
+				i = k + 1
+				n := 0
+				for n < 2 && i+n < len(s) && s[i+n] == '_' {
+					n++
+				}
+				i += n
+				text = i
+				if n == 0 {
+					buf.WriteString("<em>" + html.EscapeString(ref) + "</em>")
+					continue
+				}
+				if lt := strings.LastIndex(ref, "<"); lt >= 0 && strings.HasSuffix(ref, ">") {
+					label := strings.TrimSpace(ref[:lt])
+					u := ref[lt+1 : len(ref)-1]
+					if label == "" {
+						label = u
+					}
+					if strings.HasSuffix(u, "_") {
+						u = t.targets[rstName(strings.TrimSuffix(u, "_"))]
+					}
+					link(u, html.EscapeString(label))
+					continue
+				}
+				link(t.targets[rstName(ref)], html.EscapeString(ref))
+				continue
+			}
+		case strings.HasPrefix(s[i:], "**") && rstStart(s, i, 2):
+			if k := rstEnd(s, i+2, "**"); k >= 0 {
+				flush(i)
+				buf.WriteString("<strong>" + t.inline(s[i+2:k]) + "</strong>")
+				i = k + 2
+				text = i
+				continue
+			}
+		case c == '*' && rstStart(s, i, 1):
+			if k := rstEnd(s, i+1, "*"); k >= 0 {
+				flush(i)
+				buf.WriteString("<em>" + t.inline(s[i+1:k]) + "</em>")
+				i = k + 1
+				text = i
+				continue
+			}
+		case c == '|' && rstStart(s, i, 1):
+			if m := rstSubstRefPat.FindStringSubmatch(s[i:]); m != nil {
+				if sub, ok := t.subs[m[1]]; ok {
+					flush(i)
+					if m[2] != "" && !strings.Contains(sub, "<a ") {
+						link(t.targets[rstName(m[1])], sub)
+					} else {
+						buf.WriteString(sub)
+					}
+					i += len(m[0])
+					text = i
+					continue
+				}
+			}
+		case (c == 'h' || c == 'H') && (i == 0 || !isWordByte(s[i-1])):
+			if loc := bareURLPat.FindStringIndex(s[i:]); loc != nil && loc[0] == 0 {
+				flush(i)
+				u := s[i : i+loc[1]]
+				link(u, html.EscapeString(u))
+				i += loc[1]
+				text = i
+				continue
+			}
+		}
+		if isWordByte(c) && (i == 0 || !isWordByte(s[i-1])) {
+			if m := rstRefPat.FindStringSubmatch(s[i:]); m != nil && (i+len(m[0]) == len(s) || !isWordByte(s[i+len(m[0])])) {
+				if u, ok := t.targets[rstName(m[1])]; ok {
+					flush(i)
+					link(u, html.EscapeString(m[1]))
+					i += len(m[0])
+					text = i
+					continue
+				}
+			}
+		}
+		i++
+	}
+	flush(len(s))
+	return buf.String()
+}
@@ -0,0 +1,130 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file or at
+// https://developers.google.com/open-source/licenses/bsd.
+
+package doc
+
+import (
+	"errors"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"sort"
+)
+
+// maxSourceSize is the size in bytes of the largest file kept in
+// File.Source.
+const maxSourceSize = 256 << 10
+
+// migrateFileSources upgrades a package encoded before File.HasSource was
+// added, when the sources were stored in the document. The sources are
+// removed and the files have no source until the package is crawled again.
+func migrateFileSources(pkg map[string]interface{}) error {
+	files, _ := pkg["Files"].([]interface{})
+	for _, v := range files {
+		f, ok := v.(map[string]interface{})
+		if !ok {
+			return errors.New("Files is not a list of files")
+		}
+		delete(f, "Source")
+	}
+	return nil
+}
+
+// sourceAnnotator collects the annotations for a source file.
+type sourceAnnotator struct {
+	file        *token.File
+	annotations []Annotation
+	paths       []string
+	pathIndex   map[string]int
+}
+
+func (a *sourceAnnotator) add(n ast.Node, kind AnnotationKind, importPath string) {
+	pathIndex := -1
+	if importPath != "" {
+		var ok bool
+		pathIndex, ok = a.pathIndex[importPath]
+		if !ok {
+			pathIndex = len(a.paths)
+			a.paths = append(a.paths, importPath)
+			a.pathIndex[importPath] = pathIndex
+		}
+	}
+	a.annotations = append(a.annotations, Annotation{
+		Pos:       int32(a.file.Offset(n.Pos())),
+		End:       int32(a.file.Offset(n.End())),
+		Kind:      kind,
+		PathIndex: int16(pathIndex),
+	})
+}
+
+type byAnnotationPos []Annotation
+
+func (p byAnnotationPos) Len() int           { return len(p) }
+func (p byAnnotationPos) Swap(i, j int)      { p[i], p[j] = p[j], p[i] }
+func (p byAnnotationPos) Less(i, j int) bool { return p[i].Pos < p[j].Pos }
+
+// annotateSource sets the Source field of the package files. Comments are
+// annotated, references to imported packages and predeclared identifiers
+// link to their documentation, and exported package-level identifiers link
+// to the declaration in the package documentation. The files must be type
+// checked and not yet modified by go/doc.
+func (b *builder) annotateSource(pkg *Package, files map[string]*ast.File) {
+	if b.tpkg == nil {
+		return
+	}
+	for _, f := range pkg.Files {
+		file := files[f.Name]
+		src := b.srcs[f.Name]
+		if file == nil || src == nil || len(src.data) > maxSourceSize || f.Source.Text != "" {
+			continue
+		}
+		a := &sourceAnnotator{
+			file:      b.fset.File(file.Pos()),
+			pathIndex: make(map[string]int),
+		}
+		for _, c := range file.Comments {
+			a.add(c, CommentAnnotation, "")
+		}
+		ast.Inspect(file, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.SelectorExpr:
+				x, ok := n.X.(*ast.Ident)
+				if !ok {
+					break
+				}
+				pn, ok := b.tinfo.Uses[x].(*types.PkgName)
+				if !ok {
+					break
+				}
+				path := pn.Imported().Path()
+				if !isRecognizedImport(path) {
+					break
+				}
+				a.add(x, PackageLinkAnnotation, path)
+				if path != "C" && ast.IsExported(n.Sel.Name) {
+					a.add(n.Sel, LinkAnnotation, path)
+				}
+				return false
+			case *ast.Ident:
+				obj := b.tinfo.Uses[n]
+				if obj == nil {
+					obj = b.tinfo.Defs[n]
+				}
+				switch {
+				case obj == nil:
+				case obj.Parent() == types.Universe:
+					a.add(n, BuiltinAnnotation, "")
+				case obj.Parent() == b.tpkg.Scope() && obj.Exported():
+					a.add(n, LinkAnnotation, pkg.ImportPath)
+				}
+			}
+			return true
+		})
+		sort.Stable(byAnnotationPos(a.annotations))
+		f.Source = Code{Text: string(src.data), Annotations: a.annotations, Paths: a.paths}
+		f.HasSource = true
+	}
+}
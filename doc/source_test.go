@@ -0,0 +1,93 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file or at
+// https://developers.google.com/open-source/licenses/bsd.
+
+package doc
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+const sourceSource = `package p
+
+import "example.com/dep"
+
+// Value is a value.
+type Value struct {
+	d dep.Data
+}
+
+func (v *Value) Len() int { return len(v.d.Items) }
+
+func newValue() *Value { return &Value{} }
+`
+
+func TestAnnotateSource(t *testing.T) {
+	const path = "example.com/p"
+	pkg := newTestPackage(t, path, map[string]string{"p.go": sourceSource})
+	src := pkg.Files[0].Source
+	if src.Text != sourceSource {
+		t.Fatalf("Source.Text = %q, want %q", src.Text, sourceSource)
+	}
+
+	type annotation struct {
+		Text string
+		Kind AnnotationKind
+		Path string
+	}
+	var got []annotation
+	for _, a := range src.Annotations {
+		var p string
+		if a.PathIndex >= 0 {
+			p = src.Paths[a.PathIndex]
+		}
+		got = append(got, annotation{src.Text[a.Pos:a.End], a.Kind, p})
+	}
+	want := []annotation{
+		{"// Value is a value.", CommentAnnotation, ""},
+		{"Value", LinkAnnotation, path},
+		{"dep", PackageLinkAnnotation, "example.com/dep"},
+		{"Data", LinkAnnotation, "example.com/dep"},
+		{"Value", LinkAnnotation, path},
+		{"int", BuiltinAnnotation, ""},
+		{"len", BuiltinAnnotation, ""},
+		{"Value", LinkAnnotation, path},
+		{"Value", LinkAnnotation, path},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Annotations mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestAnnotateSourceBadImport(t *testing.T) {
+	const (
+		path = "example.com/p"
+		src  = `package p
+
+import x "example.com/a-b/"
+
+var V x.T
+`
+	)
+	pkg := newTestPackage(t, path, map[string]string{"p.go": src})
+	for _, a := range pkg.Files[0].Source.Annotations {
+		if a.Kind == PackageLinkAnnotation || a.PathIndex >= 0 && pkg.Files[0].Source.Paths[a.PathIndex] != path {
+			t.Errorf("annotation %q links to an unrecognized import path", src[a.Pos:a.End])
+		}
+	}
+}
+
+func TestMigrateFileSources(t *testing.T) {
+	got, err := DecodePackage([]byte(`{"schema":5,"package":{"Name":"p","Files":[{"Name":"p.go","URL":"u/p.go","Source":{"Text":"package p"}},{"Name":"big.go"}]}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*File{{Name: "p.go", URL: "u/p.go"}, {Name: "big.go"}}
+	if diff := cmp.Diff(want, got.Files); diff != "" {
+		t.Errorf("files differ (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,197 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file or at
+// https://developers.google.com/open-source/licenses/bsd.
+
+package doc
+
+import (
+	"go/ast"
+	"go/doc"
+	"go/types"
+	"sort"
+)
+
+// Symbol is an exported declaration of a package. The symbols of a package
+// are the stable identities of its declarations.
+type Symbol struct {
+	// Name of the symbol: Name for package-level declarations and
+	// Type.Name for methods and fields. The name matches the anchor of the
+	// declaration on the documentation page.
+	Name string `json:"name"`
+
+	// Kind is const, var, func, type, method or field.
+	Kind string `json:"kind"`
+
+	// Signature of the symbol as printed by go/types or "" if the
+	// signature refers to a package that could not be imported.
+	Signature string `json:"signature"`
+
+	// Synopsis of the symbol documentation.
+	Synopsis string `json:"synopsis,omitempty"`
+
+	Pos Pos `json:"pos"`
+}
+
+// SymbolID returns the canonical identifier of the symbol with the given
+// name in the package with the given import path, for example
+// "net/http.Client.Do".
+func SymbolID(importPath, name string) string {
+	return importPath + "." + name
+}
+
+// symbols returns the exported symbols of dpkg sorted by name.
+func (b *builder) symbols(dpkg *doc.Package) []*Symbol {
+	var syms []*Symbol
+	add := func(kind, name string, id *ast.Ident, text string) {
+		s := &Symbol{
+			Name:     name,
+			Kind:     kind,
+			Synopsis: synopsis(text),
+			Pos:      b.position(id),
+		}
+		if b.tinfo != nil {
+			if obj := b.tinfo.Defs[id]; obj != nil {
+				s.Signature = b.signature(obj)
+			}
+		}
+		syms = append(syms, s)
+	}
+	values := func(kind string, values []*doc.Value) {
+		for _, v := range values {
+			for _, spec := range v.Decl.Specs {
+				vs := spec.(*ast.ValueSpec)
+				text := v.Doc
+				if vs.Doc != nil {
+					text = vs.Doc.Text()
+				}
+				for _, id := range vs.Names {
+					if id.IsExported() {
+						add(kind, id.Name, id, text)
+					}
+				}
+			}
+		}
+	}
+	funcs := func(funcs []*doc.Func) {
+		for _, f := range funcs {
+			if ast.IsExported(f.Name) {
+				add("func", f.Name, f.Decl.Name, f.Doc)
+			}
+		}
+	}
+
+	values("const", dpkg.Consts)
+	values("var", dpkg.Vars)
+	funcs(dpkg.Funcs)
+	for _, t := range dpkg.Types {
+		values("const", t.Consts)
+		values("var", t.Vars)
+		funcs(t.Funcs)
+		for _, spec := range t.Decl.Specs {
+			ts, ok := spec.(*ast.TypeSpec)
+			if !ok || ts.Name.Name != t.Name {
+				continue
+			}
+			if ts.Name.IsExported() {
+				add("type", t.Name, ts.Name, t.Doc)
+			}
+			var fields []*ast.Field
+			kind := "field"
+			switch typ := ts.Type.(type) {
+			case *ast.StructType:
+				fields = typ.Fields.List
+			case *ast.InterfaceType:
+				fields = typ.Methods.List
+				kind = "method"
+			}
+			for _, f := range fields {
+				text := ""
+				if f.Doc != nil {
+					text = f.Doc.Text()
+				} else if f.Comment != nil {
+					text = f.Comment.Text()
+				}
+				for _, id := range f.Names {
+					if id.IsExported() {
+						add(kind, t.Name+"."+id.Name, id, text)
+					}
+				}
+			}
+		}
+		for _, m := range t.Methods {
+			if m.Level == 0 && ast.IsExported(m.Name) {
+				add("method", t.Name+"."+m.Name, m.Decl.Name, m.Doc)
+			}
+		}
+	}
+	sort.Slice(syms, func(i, j int) bool { return syms[i].Name < syms[j].Name })
+	return syms
+}
+
+// signature returns the signature of obj. The underlying types of structs
+// and interfaces are omitted.
+func (b *builder) signature(obj types.Object) string {
+	if !isValidType(obj.Type()) {
+		return ""
+	}
+	qf := types.RelativeTo(b.tpkg)
+	if tn, ok := obj.(*types.TypeName); ok && !tn.IsAlias() {
+		switch tn.Type().Underlying().(type) {
+		case *types.Struct:
+			return "type " + tn.Name() + " struct"
+		case *types.Interface:
+			return "type " + tn.Name() + " interface"
+		}
+	}
+	return types.ObjectString(obj, qf)
+}
+
+// isValidType returns false if t is or is composed of the invalid type that
+// the type checker uses for types from packages that could not be imported.
+func isValidType(t types.Type) bool {
+	switch t := t.(type) {
+	case nil:
+		return false
+	case *types.Basic:
+		return t.Kind() != types.Invalid
+	case *types.Pointer:
+		return isValidType(t.Elem())
+	case *types.Slice:
+		return isValidType(t.Elem())
+	case *types.Array:
+		return isValidType(t.Elem())
+	case *types.Chan:
+		return isValidType(t.Elem())
+	case *types.Map:
+		return isValidType(t.Key()) && isValidType(t.Elem())
+	case *types.Signature:
+		return isValidType(t.Params()) && isValidType(t.Results())
+	case *types.Tuple:
+		for i := 0; i < t.Len(); i++ {
+			if !isValidType(t.At(i).Type()) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// mergeSymbols adds the symbols in src that are not in dst to dst.
+func mergeSymbols(dst, src []*Symbol) []*Symbol {
+	seen := make(map[string]bool)
+	for _, s := range dst {
+		seen[s.Name] = true
+	}
+	n := len(dst)
+	for _, s := range src {
+		if !seen[s.Name] {
+			dst = append(dst, s)
+		}
+	}
+	if len(dst) > n {
+		sort.Slice(dst, func(i, j int) bool { return dst[i].Name < dst[j].Name })
+	}
+	return dst
+}
@@ -0,0 +1,115 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file or at
+// https://developers.google.com/open-source/licenses/bsd.
+
+package doc
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+const symbolsSource = `package p
+
+import "io"
+
+// Kinds.
+const (
+	// A is the first kind.
+	A Kind = iota
+	b
+)
+
+// Kind is a kind.
+type Kind int
+
+// V is a variable.
+var V = []string{}
+
+// T is a type.
+type T struct {
+	// X is a field.
+	X int
+	Y io.Reader // Y is a reader.
+	z int
+}
+
+// NewT returns a T.
+func NewT() *T { return nil }
+
+// M is a method.
+func (t *T) M(n int) error { return nil }
+
+func (t *T) m() {}
+
+type I interface {
+	// Get gets.
+	Get() int
+}
+`
+
+const symbolsWindowsSource = `package p
+
+// W is for Windows.
+func W() {}
+`
+
+func TestSymbols(t *testing.T) {
+	pkg := newTestPackage(t, "example.com/p", map[string]string{
+		"p.go":         symbolsSource,
+		"p_windows.go": symbolsWindowsSource,
+	})
+	for _, s := range pkg.Symbols {
+		if s.Pos.Line == 0 {
+			t.Errorf("symbol %s has no position", s.Name)
+		}
+		s.Pos = Pos{}
+	}
+	want := []*Symbol{
+		{Name: "A", Kind: "const", Signature: "const A Kind", Synopsis: "A is the first kind."},
+		{Name: "I", Kind: "type", Signature: "type I interface"},
+		{Name: "I.Get", Kind: "method", Signature: "func (I).Get() int", Synopsis: "Get gets."},
+		{Name: "Kind", Kind: "type", Signature: "type Kind int", Synopsis: "Kind is a kind."},
+		{Name: "NewT", Kind: "func", Signature: "func NewT() *T", Synopsis: "NewT returns a T."},
+		{Name: "T", Kind: "type", Signature: "type T struct", Synopsis: "T is a type."},
+		{Name: "T.M", Kind: "method", Signature: "func (*T).M(n int) error", Synopsis: "M is a method."},
+		{Name: "T.X", Kind: "field", Signature: "field X int", Synopsis: "X is a field."},
+		{Name: "T.Y", Kind: "field", Signature: "field Y io.Reader", Synopsis: "Y is a reader."},
+		{Name: "V", Kind: "var", Signature: "var V []string", Synopsis: "V is a variable."},
+		{Name: "W", Kind: "func", Signature: "func W()", Synopsis: "W is for Windows."},
+	}
+	if diff := cmp.Diff(want, pkg.Symbols); diff != "" {
+		t.Errorf("symbols differ (-want +got):\n%s", diff)
+	}
+	if got, want := SymbolID("example.com/p", "T.M"), "example.com/p.T.M"; got != want {
+		t.Errorf("SymbolID = %q, want %q", got, want)
+	}
+}
+
+func TestSymbolsBadImport(t *testing.T) {
+	pkg := newTestPackage(t, "example.com/p", map[string]string{"p.go": `package p
+
+import x "example.com/a-b/"
+
+// V is a variable.
+var V x.T
+
+var N int
+
+func F(v x.T) {}
+`})
+	for _, s := range pkg.Symbols {
+		s.Pos = Pos{}
+	}
+	want := []*Symbol{
+		{Name: "F", Kind: "func"},
+		{Name: "N", Kind: "var", Signature: "var N int"},
+		{Name: "V", Kind: "var", Synopsis: "V is a variable."},
+	}
+	if diff := cmp.Diff(want, pkg.Symbols); diff != "" {
+		t.Errorf("symbols differ (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,119 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file or at
+// https://developers.google.com/open-source/licenses/bsd.
+
+package doc
+
+import (
+	"go/ast"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// TestFunc is a test, benchmark or fuzz target in the test files of a
+// package.
+type TestFunc struct {
+	Name string
+	Kind string // "Test", "Benchmark" or "Fuzz"
+	Doc  string
+	File int16 // index in Package.TestFiles
+	Line int32
+}
+
+var testKinds = []struct {
+	kind, param string
+}{
+	{"Test", "T"},
+	{"Benchmark", "B"},
+	{"Fuzz", "F"},
+}
+
+// testFuncs returns the test functions declared in the test file at index i
+// of the package test files.
+func (b *builder) testFuncs(file *ast.File, i int) []*TestFunc {
+	var tests []*TestFunc
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Recv != nil {
+			continue
+		}
+		kind := testKind(fn)
+		if kind == "" {
+			continue
+		}
+		tests = append(tests, &TestFunc{
+			Name: fn.Name.Name,
+			Kind: kind,
+			Doc:  strings.TrimRight(fn.Doc.Text(), " \t\n\r"),
+			File: int16(i),
+			Line: int32(b.fset.Position(fn.Pos()).Line),
+		})
+	}
+	return tests
+}
+
+// testKind returns the kind of the test function declared by fn or "" if
+// fn does not declare a test function. The rules match those of go test.
+func testKind(fn *ast.FuncDecl) string {
+	name := fn.Name.Name
+	params := fn.Type.Params.List
+	if len(params) != 1 || len(params[0].Names) > 1 || fn.Type.Results != nil {
+		return ""
+	}
+	star, ok := params[0].Type.(*ast.StarExpr)
+	if !ok {
+		return ""
+	}
+	var param string
+	switch x := star.X.(type) {
+	case *ast.SelectorExpr:
+		param = x.Sel.Name
+	case *ast.Ident:
+		// The testing package is dot imported.
+		param = x.Name
+	}
+	for _, k := range testKinds {
+		if !strings.HasPrefix(name, k.kind) || param != k.param {
+			continue
+		}
+		if len(name) == len(k.kind) {
+			return k.kind
+		}
+		r, _ := utf8.DecodeRuneInString(name[len(k.kind):])
+		if !unicode.IsLower(r) {
+			return k.kind
+		}
+	}
+	return ""
+}
+
+// mergeTests adds the test functions and test files in src that are not in
+// dst to dst.
+func mergeTests(dst, src *Package) {
+	files := make(map[string]int16)
+	for i, f := range dst.TestFiles {
+		files[f.Name] = int16(i)
+	}
+	seen := make(map[string]bool)
+	for _, t := range dst.Tests {
+		seen[t.Name] = true
+	}
+	for _, t := range src.Tests {
+		if seen[t.Name] {
+			continue
+		}
+		seen[t.Name] = true
+		f := src.TestFiles[t.File]
+		i, ok := files[f.Name]
+		if !ok {
+			i = int16(len(dst.TestFiles))
+			files[f.Name] = i
+			dst.TestFiles = append(dst.TestFiles, f)
+		}
+		t.File = i
+		dst.Tests = append(dst.Tests, t)
+	}
+}
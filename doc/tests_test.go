@@ -0,0 +1,79 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file or at
+// https://developers.google.com/open-source/licenses/bsd.
+
+package doc
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+const testsSource = `package p
+
+import "testing"
+
+// TestOpen tests Open.
+func TestOpen(t *testing.T) {}
+
+func Test(t *testing.T) {}
+
+func Testing(t *testing.T) {}
+
+func TestHelper(t *testing.T, n int) {}
+
+func BenchmarkRead(b *testing.B) {}
+
+func BenchmarkWrite(t *testing.T) {}
+
+// FuzzParse fuzzes the parser.
+// It needs a corpus.
+func FuzzParse(f *testing.F) {}
+`
+
+const testsXSource = `package p_test
+
+import . "testing"
+
+func TestX(t *T) {}
+`
+
+const testsWindowsSource = `package p
+
+import "testing"
+
+func TestWindows(t *testing.T) {}
+
+func TestOpen(t *testing.T) {}
+`
+
+func TestTests(t *testing.T) {
+	pkg := newTestPackage(t, "example.com/p", map[string]string{
+		"p.go":              "package p\n",
+		"p_windows.go":      "package p\n\nfunc W() {}\n",
+		"p_test.go":         testsSource,
+		"x_test.go":         testsXSource,
+		"p_windows_test.go": testsWindowsSource,
+	})
+	want := []*TestFunc{
+		{Name: "TestOpen", Kind: "Test", Doc: "TestOpen tests Open.", File: 0, Line: 6},
+		{Name: "Test", Kind: "Test", File: 0, Line: 8},
+		{Name: "BenchmarkRead", Kind: "Benchmark", File: 0, Line: 14},
+		{Name: "FuzzParse", Kind: "Fuzz", Doc: "FuzzParse fuzzes the parser.\nIt needs a corpus.", File: 0, Line: 20},
+		{Name: "TestX", Kind: "Test", File: 1, Line: 5},
+		{Name: "TestWindows", Kind: "Test", File: 2, Line: 5},
+	}
+	if diff := cmp.Diff(want, pkg.Tests); diff != "" {
+		t.Errorf("tests differ (-want +got):\n%s", diff)
+	}
+	var files []string
+	for _, f := range pkg.TestFiles {
+		files = append(files, f.Name)
+	}
+	if want := []string{"p_test.go", "x_test.go", "p_windows_test.go"}; !cmp.Equal(files, want) {
+		t.Errorf("test files = %v, want %v", files, want)
+	}
+}
@@ -0,0 +1,301 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file or at
+// https://developers.google.com/open-source/licenses/bsd.
+
+package doc
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"path"
+	"sort"
+	"strconv"
+)
+
+// The documentation builder does not have access to the dependencies of a
+// package. To type check the package anyway, imported packages are replaced
+// with placeholders. A placeholder package declares every name the package
+// selects from it as a named type with an empty struct underlying type.
+// Declarations that only pass imported types around, the common case for
+// exported API, type check the same way as with the real dependencies.
+//
+// A few standard library packages are declared by the stub sources below so
+// that types can be checked against well-known interfaces and that the
+// analyzers can recognize the functions and types they check.
+
+var stubSources = map[string]string{
+	"encoding": `package encoding
+type BinaryMarshaler interface { MarshalBinary() (data []byte, err error) }
+type BinaryUnmarshaler interface { UnmarshalBinary(data []byte) error }
+type TextMarshaler interface { MarshalText() (text []byte, err error) }
+type TextUnmarshaler interface { UnmarshalText(text []byte) error }
+`,
+	"errors": `package errors
+func New(text string) error
+`,
+	"encoding/json": `package json
+type Marshaler interface { MarshalJSON() ([]byte, error) }
+type Unmarshaler interface { UnmarshalJSON([]byte) error }
+`,
+	"fmt": `package fmt
+import "io"
+func Errorf(format string, a ...interface{}) error
+func Fprint(w io.Writer, a ...interface{}) (n int, err error)
+func Fprintf(w io.Writer, format string, a ...interface{}) (n int, err error)
+func Fprintln(w io.Writer, a ...interface{}) (n int, err error)
+func Print(a ...interface{}) (n int, err error)
+func Printf(format string, a ...interface{}) (n int, err error)
+func Println(a ...interface{}) (n int, err error)
+func Sprint(a ...interface{}) string
+func Sprintf(format string, a ...interface{}) string
+func Sprintln(a ...interface{}) string
+type State interface {
+	Write(b []byte) (n int, err error)
+	Width() (wid int, ok bool)
+	Precision() (prec int, ok bool)
+	Flag(c int) bool
+}
+type Formatter interface { Format(f State, verb rune) }
+type Stringer interface { String() string }
+type GoStringer interface { GoString() string }
+`,
+	"io": `package io
+type Reader interface { Read(p []byte) (n int, err error) }
+type Writer interface { Write(p []byte) (n int, err error) }
+type Closer interface { Close() error }
+type Seeker interface { Seek(offset int64, whence int) (int64, error) }
+type ReaderAt interface { ReadAt(p []byte, off int64) (n int, err error) }
+type WriterAt interface { WriteAt(p []byte, off int64) (n int, err error) }
+type ReaderFrom interface { ReadFrom(r Reader) (n int64, err error) }
+type WriterTo interface { WriteTo(w Writer) (n int64, err error) }
+type ByteReader interface { ReadByte() (byte, error) }
+type ByteWriter interface { WriteByte(c byte) error }
+type RuneReader interface { ReadRune() (r rune, size int, err error) }
+type StringWriter interface { WriteString(s string) (n int, err error) }
+`,
+	"log": `package log
+func Fatal(v ...interface{})
+func Fatalf(format string, v ...interface{})
+func Fatalln(v ...interface{})
+func Panic(v ...interface{})
+func Panicf(format string, v ...interface{})
+func Panicln(v ...interface{})
+func Print(v ...interface{})
+func Printf(format string, v ...interface{})
+func Println(v ...interface{})
+type Logger struct{}
+func (l *Logger) Fatal(v ...interface{})                 {}
+func (l *Logger) Fatalf(format string, v ...interface{}) {}
+func (l *Logger) Fatalln(v ...interface{})               {}
+func (l *Logger) Panic(v ...interface{})                 {}
+func (l *Logger) Panicf(format string, v ...interface{}) {}
+func (l *Logger) Panicln(v ...interface{})               {}
+func (l *Logger) Print(v ...interface{})                 {}
+func (l *Logger) Printf(format string, v ...interface{}) {}
+func (l *Logger) Println(v ...interface{})               {}
+`,
+	"net/http": `package http
+type Header map[string][]string
+type Request struct{}
+type ResponseWriter interface {
+	Header() Header
+	Write([]byte) (int, error)
+	WriteHeader(statusCode int)
+}
+type Handler interface { ServeHTTP(ResponseWriter, *Request) }
+`,
+	"sort": `package sort
+type Interface interface {
+	Len() int
+	Less(i, j int) bool
+	Swap(i, j int)
+}
+`,
+	"sync": `package sync
+type Locker interface {
+	Lock()
+	Unlock()
+}
+type noCopy struct{}
+func (*noCopy) Lock()   {}
+func (*noCopy) Unlock() {}
+type Mutex struct { state int32; sema uint32 }
+func (m *Mutex) Lock()   {}
+func (m *Mutex) Unlock() {}
+type RWMutex struct { w Mutex; readers int32 }
+func (rw *RWMutex) Lock()    {}
+func (rw *RWMutex) Unlock()  {}
+func (rw *RWMutex) RLock()   {}
+func (rw *RWMutex) RUnlock() {}
+func (rw *RWMutex) RLocker() Locker { return nil }
+type Once struct { m Mutex; done uint32 }
+func (o *Once) Do(f func()) {}
+type WaitGroup struct { noCopy noCopy; state [3]uint32 }
+func (wg *WaitGroup) Add(delta int) {}
+func (wg *WaitGroup) Done()         {}
+func (wg *WaitGroup) Wait()         {}
+type Cond struct { noCopy noCopy; L Locker }
+func NewCond(l Locker) *Cond { return nil }
+func (c *Cond) Wait()      {}
+func (c *Cond) Signal()    {}
+func (c *Cond) Broadcast() {}
+`,
+	"testing": `package testing
+type common struct{}
+func (c *common) Error(args ...interface{})                 {}
+func (c *common) Errorf(format string, args ...interface{}) {}
+func (c *common) Fatal(args ...interface{})                 {}
+func (c *common) Fatalf(format string, args ...interface{}) {}
+func (c *common) Log(args ...interface{})                   {}
+func (c *common) Logf(format string, args ...interface{})   {}
+func (c *common) Skip(args ...interface{})                  {}
+func (c *common) Skipf(format string, args ...interface{})  {}
+type T struct { common }
+type B struct { common }
+`,
+}
+
+// typeImporter implements types.Importer with stub and placeholder packages.
+type typeImporter struct {
+	refs map[string]map[string]bool // names selected from each import path
+	pkgs map[string]*types.Package
+}
+
+func (imp *typeImporter) Import(importPath string) (*types.Package, error) {
+	if pkg := imp.pkgs[importPath]; pkg != nil {
+		return pkg, nil
+	}
+	var pkg *types.Package
+	if src, ok := stubSources[importPath]; ok {
+		pkg = imp.checkStub(importPath, src)
+	} else {
+		pkg = types.NewPackage(importPath, placeholderName(importPath))
+	}
+	for name := range imp.refs[importPath] {
+		if pkg.Scope().Lookup(name) == nil {
+			obj := types.NewTypeName(token.NoPos, pkg, name, nil)
+			types.NewNamed(obj, types.NewStruct(nil, nil), nil)
+			pkg.Scope().Insert(obj)
+		}
+	}
+	pkg.MarkComplete()
+	imp.pkgs[importPath] = pkg
+	return pkg, nil
+}
+
+// placeholderName returns the name of the placeholder package for the
+// import path. Import paths the package name cannot be guessed from, like
+// "foo/", get the last element of the path or "_" if the element is not an
+// identifier.
+func placeholderName(importPath string) string {
+	if obj, err := simpleImporter(make(map[string]*ast.Object), importPath); err == nil {
+		return obj.Name
+	}
+	if name := path.Base(importPath); token.IsIdentifier(name) {
+		return name
+	}
+	return "_"
+}
+
+func (imp *typeImporter) checkStub(path, src string) *types.Package {
+	// Stubs are checked in their own file set so that positions in the
+	// package file set are not affected.
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, path+".go", src, 0)
+	if err != nil {
+		panic(err)
+	}
+	conf := types.Config{Importer: imp}
+	pkg, err := conf.Check(path, fset, []*ast.File{file}, nil)
+	if err != nil {
+		panic(err)
+	}
+	return pkg
+}
+
+// importRefs returns the names selected from each import path in files.
+func importRefs(files []*ast.File) map[string]map[string]bool {
+	refs := make(map[string]map[string]bool)
+	for _, file := range files {
+		ast.Inspect(file, func(n ast.Node) bool {
+			sel, ok := n.(*ast.SelectorExpr)
+			if !ok {
+				return true
+			}
+			x, _ := sel.X.(*ast.Ident)
+			if x == nil || x.Obj == nil || x.Obj.Kind != ast.Pkg {
+				return true
+			}
+			spec, _ := x.Obj.Decl.(*ast.ImportSpec)
+			if spec == nil {
+				return true
+			}
+			path, err := strconv.Unquote(spec.Path.Value)
+			if err != nil {
+				return true
+			}
+			if refs[path] == nil {
+				refs[path] = make(map[string]bool)
+			}
+			refs[path][sel.Sel.Name] = true
+			return false
+		})
+	}
+	return refs
+}
+
+// typeCheck type checks the package files. The files must be resolved by
+// ast.NewPackage and not yet modified by go/doc. Type errors are ignored;
+// the result is as complete as the placeholders allow.
+func (b *builder) typeCheck(importPath string, files map[string]*ast.File) {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	list := make([]*ast.File, len(names))
+	for i, name := range names {
+		list[i] = files[name]
+	}
+
+	b.importer = &typeImporter{
+		refs: importRefs(list),
+		pkgs: make(map[string]*types.Package),
+	}
+	conf := types.Config{
+		Importer:    b.importer,
+		FakeImportC: true,
+		Error:       func(error) {}, // Expected with placeholder packages.
+	}
+	b.tfiles = list
+	b.tinfo = &types.Info{
+		Types:      make(map[ast.Expr]types.TypeAndValue),
+		Defs:       make(map[*ast.Ident]types.Object),
+		Uses:       make(map[*ast.Ident]types.Object),
+		Selections: make(map[*ast.SelectorExpr]*types.Selection),
+	}
+	b.tpkg, _ = conf.Check(importPath, b.fset, list, b.tinfo)
+}
+
+// lookupType returns the named type declared in the package with the given
+// name or nil if the type is not found.
+func (b *builder) lookupType(name string) *types.Named {
+	if b.tpkg == nil {
+		return nil
+	}
+	tn, _ := b.tpkg.Scope().Lookup(name).(*types.TypeName)
+	if tn == nil {
+		return nil
+	}
+	t, _ := tn.Type().(*types.Named)
+	return t
+}
+
+// qualifyByPath qualifies package-level types by their import path.
+func qualifyByPath(pkg *types.Package) string {
+	return pkg.Path()
+}
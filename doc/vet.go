@@ -60,14 +60,19 @@ func (v *vetVisitor) Visit(n ast.Node) ast.Visitor {
 	return v
 }
 
+// isRecognizedImport returns true if importPath is a valid import path.
+func isRecognizedImport(importPath string) bool {
+	return gosrc.IsValidPath(importPath) ||
+		strings.HasPrefix(importPath, "exp/") ||
+		strings.HasPrefix(importPath, "appengine")
+}
+
 func (b *builder) vetPackage(pkg *Package, apkg *ast.Package) {
 	errors := make(map[string]token.Pos)
 	for _, file := range apkg.Files {
 		for _, is := range file.Imports {
 			importPath, _ := strconv.Unquote(is.Path.Value)
-			if !gosrc.IsValidPath(importPath) &&
-				!strings.HasPrefix(importPath, "exp/") &&
-				!strings.HasPrefix(importPath, "appengine") {
+			if !isRecognizedImport(importPath) {
 				errors[fmt.Sprintf("Unrecognized import path %q", importPath)] = is.Pos()
 			}
 		}
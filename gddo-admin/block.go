@@ -31,4 +31,7 @@ func block(c *command) {
 	if err := db.Block(c.flag.Args()[0]); err != nil {
 		log.Fatal(err)
 	}
+	if err := db.Close(); err != nil {
+		log.Fatal(err)
+	}
 }
@@ -13,7 +13,6 @@ import (
 	"os"
 	"strings"
 
-	"github.com/garyburd/redigo/redis"
 	"github.com/golang/gddo/database"
 )
 
@@ -43,9 +42,7 @@ func crawl(c *command) {
 		}
 	}
 
-	conn := db.Pool.Get()
-	defer conn.Close()
-	paths, err := redis.Strings(conn.Do("SMEMBERS", "newCrawl"))
+	paths, err := db.NewCrawls()
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -54,12 +51,15 @@ func crawl(c *command) {
 		fmt.Println(path)
 	}
 
-	paths, err = redis.Strings(conn.Do("SMEMBERS", "badCrawl"))
+	failures, err := db.CrawlFailures("")
 	if err != nil {
 		log.Fatal(err)
 	}
-	fmt.Println("BAD")
-	for _, path := range paths {
-		fmt.Println(path)
+	fmt.Println("FAILED")
+	for _, f := range failures {
+		fmt.Println(f.Path)
+	}
+	if err := db.Close(); err != nil {
+		log.Fatal(err)
 	}
 }
@@ -32,4 +32,7 @@ func del(c *command) {
 	if err := db.Delete(context.Background(), c.flag.Args()[0]); err != nil {
 		log.Fatal(err)
 	}
+	if err := db.Close(); err != nil {
+		log.Fatal(err)
+	}
 }
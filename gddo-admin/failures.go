@@ -0,0 +1,58 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file or at
+// https://developers.google.com/open-source/licenses/bsd.
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/golang/gddo/database"
+)
+
+var failuresCommand = &command{
+	name:  "failures",
+	usage: "failures [-class class] [-retry]",
+}
+
+var (
+	failuresClass = failuresCommand.flag.String("class", "", "Print the failures of this class only: notfound, remote, timeout or error.")
+	failuresRetry = failuresCommand.flag.Bool("retry", false, "Add the paths due for a retry to the new crawl set first.")
+)
+
+func init() {
+	failuresCommand.run = failures
+}
+
+func failures(c *command) {
+	if len(c.flag.Args()) != 0 {
+		c.printUsage()
+		os.Exit(1)
+	}
+	db, err := database.New(*redisServer, *dbIdleTimeout, false, gaeEndpoint)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if *failuresRetry {
+		n, err := db.RetryCrawlFailures(time.Now())
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("Retrying %d paths\n", n)
+	}
+	records, err := db.CrawlFailures(*failuresClass)
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, f := range records {
+		fmt.Printf("%s %d %-8s %s: %s\n", f.LastAttempt.Format("2006-01-02T15:04"), f.Attempts, f.Class, f.Path, f.Message)
+	}
+	if err := db.Close(); err != nil {
+		log.Fatal(err)
+	}
+}
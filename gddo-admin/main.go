@@ -29,7 +29,7 @@ func (c *command) printUsage() {
 
 var (
 	project       = flag.String("project", "", "App Engine project ID used to interact with remote API.")
-	redisServer   = flag.String("db-server", "redis://127.0.0.1:6379", "URI of Redis server.")
+	redisServer   = flag.String("db-server", "redis://127.0.0.1:6379", "URI of Redis server or file: URI of a database file.")
 	dbIdleTimeout = flag.Duration("db-idle-timeout", 250*time.Second, "Close database connections after remaining idle for this duration.")
 )
 
@@ -40,8 +40,10 @@ var commands = []*command{
 	reindexCommand,
 	deleteCommand,
 	popularCommand,
+	rankCommand,
 	dangleCommand,
 	crawlCommand,
+	failuresCommand,
 	statsCommand,
 }
 
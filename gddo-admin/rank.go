@@ -0,0 +1,51 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file or at
+// https://developers.google.com/open-source/licenses/bsd.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/golang/gddo/database"
+)
+
+var rankCommand = &command{
+	name:  "rank",
+	usage: "rank [-n count]",
+}
+
+var rankCount = rankCommand.flag.Int("n", 20, "Print the count packages with the highest import rank.")
+
+func init() {
+	rankCommand.run = rank
+}
+
+func rank(c *command) {
+	if len(c.flag.Args()) != 0 {
+		c.printUsage()
+		os.Exit(1)
+	}
+	db, err := database.New(*redisServer, *dbIdleTimeout, false, gaeEndpoint)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := db.UpdateImportRanks(context.Background()); err != nil {
+		log.Fatal(err)
+	}
+	pkgs, err := db.TopImportRanks(*rankCount)
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, pkg := range pkgs {
+		fmt.Printf("%.1f %s\n", pkg.Score, pkg.Path)
+	}
+	if err := db.Close(); err != nil {
+		log.Fatal(err)
+	}
+}
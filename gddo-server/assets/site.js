@@ -137,6 +137,17 @@ $(function() {
 
 });
 
+// platform selector
+$(function() {
+    $('#x-platform').on('change', function() {
+        var platform = $(this).val();
+        $('[data-platforms]').each(function() {
+            var el = $(this);
+            el.toggle(!platform || (' ' + el.attr('data-platforms')).indexOf(' ' + platform + ' ') >= 0);
+        });
+    });
+});
+
 // keyboard shortcuts
 $(function() {
     var prevCh = null, prevTime = 0, modal = false;
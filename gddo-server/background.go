@@ -16,10 +16,17 @@ import (
 	"github.com/golang/gddo/gosrc"
 )
 
+// dueCrawlCandidates is the number of due packages considered by doCrawl
+// for a crawl allowed by the crawl limits.
+const dueCrawlCandidates = 20
+
+// doCrawl starts a crawl of a new package or of the stored package due for
+// crawling first, if the crawl limits allow it. The crawl runs in the
+// background.
 func (s *server) doCrawl(ctx context.Context) error {
-	span := s.traceClient.NewSpan("Crawl")
-	defer span.Finish()
-	ctx = trace.NewContext(ctx, span)
+	if s.crawls.full() {
+		return nil
+	}
 
 	// Look for new package to crawl.
 	importPath, hasSubdirs, err := s.db.PopNewCrawl()
@@ -27,33 +34,76 @@ func (s *server) doCrawl(ctx context.Context) error {
 		log.Printf("db.PopNewCrawl() returned error %v", err)
 		return nil
 	}
-	if importPath != "" {
-		if pdoc, err := s.crawlDoc(ctx, "new", importPath, nil, hasSubdirs, time.Time{}); pdoc == nil && err == nil {
-			if err := s.db.AddBadCrawl(importPath); err != nil {
-				log.Printf("ERROR db.AddBadCrawl(%q): %v", importPath, err)
-			}
+	if importPath != "" && !s.crawls.acquire(importPath) {
+		// Crawl the package once its host is less busy and look for a
+		// stored package of another host instead. AddNewCrawl would drop
+		// the paths retried after a crawl failure.
+		if _, err := s.db.Store.RetryCrawl(importPath); err != nil {
+			log.Printf("ERROR db.RetryCrawl(%q): %v", importPath, err)
 		}
+		importPath = ""
+	}
+	if importPath != "" {
+		go func() {
+			defer s.crawls.release(importPath)
+			span := s.traceClient.NewSpan("Crawl")
+			defer span.Finish()
+			ctx := trace.NewContext(ctx, span)
+
+			s.crawlDoc(ctx, "new", importPath, nil, hasSubdirs, time.Time{})
+		}()
 		return nil
 	}
 
 	// Crawl existing doc.
-	pdoc, pkgs, nextCrawl, err := s.db.Get(ctx, "-")
+	paths, err := s.db.DueCrawls(time.Now(), dueCrawlCandidates)
 	if err != nil {
-		log.Printf("db.Get(\"-\") returned error %v", err)
-		return nil
-	}
-	if pdoc == nil || nextCrawl.After(time.Now()) {
+		log.Printf("db.DueCrawls() returned error %v", err)
 		return nil
 	}
-	if _, err = s.crawlDoc(ctx, "crawl", pdoc.ImportPath, pdoc, len(pkgs) > 0, nextCrawl); err != nil {
-		// Touch package so that crawl advances to next package.
-		if err := s.db.SetNextCrawl(pdoc.ImportPath, time.Now().Add(s.v.GetDuration(ConfigMaxAge)/3)); err != nil {
-			log.Printf("ERROR db.SetNextCrawl(%q): %v", pdoc.ImportPath, err)
+	for _, path := range paths {
+		if !s.crawls.acquire(path) {
+			continue
 		}
+		go func(path string) {
+			defer s.crawls.release(path)
+			span := s.traceClient.NewSpan("Crawl")
+			defer span.Finish()
+			ctx := trace.NewContext(ctx, span)
+
+			pdoc, pkgs, nextCrawl, err := s.db.Get(ctx, path)
+			if err != nil {
+				log.Printf("db.Get(%q) returned error %v", path, err)
+				return
+			}
+			if pdoc == nil || nextCrawl.After(time.Now()) {
+				return
+			}
+			s.crawlDoc(ctx, "crawl", pdoc.ImportPath, pdoc, len(pkgs) > 0, nextCrawl)
+		}(path)
+		return nil
 	}
 	return nil
 }
 
+func (s *server) updateImportRanks(ctx context.Context) error {
+	span := s.traceClient.NewSpan("ImportRanks")
+	defer span.Finish()
+	ctx = trace.NewContext(ctx, span)
+
+	return s.db.UpdateImportRanks(ctx)
+}
+
+// retryCrawlFailures adds the paths that failed to crawl with transient
+// errors back to the new crawl set. See database.RetryCrawlFailures.
+func (s *server) retryCrawlFailures() error {
+	n, err := s.db.RetryCrawlFailures(time.Now())
+	if n > 0 {
+		log.Printf("retry crawl of %d paths", n)
+	}
+	return err
+}
+
 func (s *server) readGitHubUpdates(ctx context.Context) error {
 	span := s.traceClient.NewSpan("GitHubUpdates")
 	defer span.Finish()
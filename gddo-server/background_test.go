@@ -0,0 +1,46 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file or at
+// https://developers.google.com/open-source/licenses/bsd.
+
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDoCrawlBusyHost(t *testing.T) {
+	s, cleanup := newTestServer(t)
+	defer cleanup()
+	s.crawls = newCrawlLimiter(2, 1)
+	if !s.crawls.acquire("github.com/a/busy") {
+		t.Fatal("acquire failed")
+	}
+
+	// A path retried after a transient crawl failure keeps its failure
+	// record until it is crawled.
+	const path = "github.com/a/b"
+	now := time.Now()
+	if err := s.db.AddCrawlFailure(path, context.DeadlineExceeded, now.Add(-2*time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := s.db.RetryCrawlFailures(now); n != 1 || err != nil {
+		t.Fatalf("RetryCrawlFailures() = %d, %v; want 1, nil", n, err)
+	}
+
+	if err := s.doCrawl(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	got, err := s.db.NewCrawls()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{path}; !cmp.Equal(got, want) {
+		t.Errorf("new crawls after doCrawl = %v, want %v", got, want)
+	}
+}
@@ -43,7 +43,9 @@ const (
 	// Display Config
 	ConfigSidebar        = "sidebar"
 	ConfigSourcegraphURL = "sourcegraph_url"
+	ConfigSourceViewer   = "source_viewer"
 	ConfigDefaultGOOS    = "default_goos"
+	ConfigAnalyzers      = "analyzers"
 	ConfigGAAccount      = "ga_account"
 
 	// Crawl Config
@@ -52,6 +54,10 @@ const (
 	ConfigFirstGetTimeout = "first_get_timeout"
 	ConfigGithubInterval  = "github_interval"
 	ConfigCrawlInterval   = "crawl_interval"
+	ConfigRankInterval    = "rank_interval"
+	ConfigCrawlWorkers    = "crawl_workers"
+	ConfigCrawlHostLimit  = "crawl_host_limit"
+	ConfigRetryInterval   = "crawl_retry_interval"
 	ConfigDialTimeout     = "dial_timeout"
 	ConfigRequestTimeout  = "request_timeout"
 	ConfigMemcacheAddr    = "memcache_addr"
@@ -160,17 +166,23 @@ func buildFlags() *pflag.FlagSet {
 	flags.String(ConfigBindAddress, ":8080", "Listen for HTTP connections on this address.")
 	flags.Bool(ConfigSidebar, false, "Enable package page sidebar.")
 	flags.String(ConfigDefaultGOOS, "", "Default GOOS to use when building package documents.")
+	flags.StringSlice(ConfigAnalyzers, []string{"copylocks", "printf", "shadow", "unusedresult"}, "Static analyzers to run when building package documents.")
 	flags.Bool(ConfigTrustProxyHeaders, false, "If enabled, identify the remote address of the request using X-Real-Ip in header.")
 	flags.String(ConfigSourcegraphURL, "https://sourcegraph.com", "Link to global uses on Sourcegraph based at this URL (no need for trailing slash).")
+	flags.Bool(ConfigSourceViewer, false, "Link to the built-in source viewer instead of the repository host.")
 	flags.Duration(ConfigGithubInterval, 0, "Github updates crawler sleeps for this duration between fetches. Zero disables the crawler.")
 	flags.Duration(ConfigCrawlInterval, 0, "Package updater sleeps for this duration between package updates. Zero disables updates.")
+	flags.Int(ConfigCrawlWorkers, 4, "Maximum number of package updates in progress.")
+	flags.Int(ConfigCrawlHostLimit, 2, "Maximum number of package updates in progress per host.")
+	flags.Duration(ConfigRetryInterval, time.Hour, "Paths that failed to crawl with transient errors are queued for crawling again at this interval. Zero disables the retries.")
+	flags.Duration(ConfigRankInterval, 24*time.Hour, "Import ranks of the packages are computed at this interval. Zero disables the computation.")
 	flags.Duration(ConfigDialTimeout, 5*time.Second, "Timeout for dialing an HTTP connection.")
 	flags.Duration(ConfigRequestTimeout, 20*time.Second, "Time out for roundtripping an HTTP request.")
-	flags.String(ConfigDBServer, "redis://127.0.0.1:6379", "URI of Redis server.")
+	flags.String(ConfigDBServer, "redis://127.0.0.1:6379", "URI of Redis server or file: URI of a database file.")
 	flags.Duration(ConfigDBIdleTimeout, 250*time.Second, "Close Redis connections after remaining idle for this duration.")
 	flags.Bool(ConfigDBLog, false, "Log database commands")
 	flags.String(ConfigMemcacheAddr, "", "Address in the format host:port gddo uses to point to the memcache backend.")
-	flags.String(ConfigGAERemoteAPI, "", "Remoteapi endpoint for App Engine Search. Defaults to serviceproxy-dot-${project}.appspot.com.")
+	flags.String(ConfigGAERemoteAPI, "", "Remoteapi endpoint for App Engine Search. Defaults to serviceproxy-dot-${project}.appspot.com. Packages are searched in a local index when no endpoint is set.")
 	flags.Float64(ConfigTraceSamplerFraction, 0.1, "Fraction of the requests sampled by the trace API.")
 	flags.Float64(ConfigTraceSamplerMaxQPS, 5, "Max number of requests sampled every second by the trace API.")
 
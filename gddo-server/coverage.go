@@ -0,0 +1,50 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file or at
+// https://developers.google.com/open-source/licenses/bsd.
+
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/golang/gddo/doc"
+)
+
+const coverageBadgeTemplate = `<svg xmlns="http://www.w3.org/2000/svg" width="%[1]d" height="20">` +
+	`<linearGradient id="a" x2="0" y2="100%%"><stop offset="0" stop-color="#bbb" stop-opacity=".1"/><stop offset="1" stop-opacity=".1"/></linearGradient>` +
+	`<rect rx="3" width="%[1]d" height="20" fill="#555"/><rect rx="3" x="88" width="%[2]d" height="20" fill="%[3]s"/>` +
+	`<path fill="%[3]s" d="M88 0h4v20h-4z"/><rect rx="3" width="%[1]d" height="20" fill="url(#a)"/>` +
+	`<g fill="#fff" text-anchor="middle" font-family="DejaVu Sans,Verdana,Geneva,sans-serif" font-size="11">` +
+	`<text x="44" y="15" fill="#010101" fill-opacity=".3">doc coverage</text><text x="44" y="14">doc coverage</text>` +
+	`<text x="%[4]d" y="15" fill="#010101" fill-opacity=".3">%[5]s</text><text x="%[4]d" y="14">%[5]s</text></g></svg>`
+
+// coverageBadge returns an SVG badge for the documentation coverage. The
+// coverage is unknown if c is nil.
+func coverageBadge(c *doc.Coverage) []byte {
+	text, color := "unknown", "#9f9f9f"
+	if c != nil {
+		percent := c.Sum().Percent()
+		text = strconv.Itoa(percent) + "%"
+		switch {
+		case percent >= 80:
+			color = "#4c1"
+		case percent >= 50:
+			color = "#dfb317"
+		default:
+			color = "#e05d44"
+		}
+	}
+	width := 7*len(text) + 12
+	return []byte(fmt.Sprintf(coverageBadgeTemplate, 88+width, width, color, 88+width/2, text))
+}
+
+func serveCoverageBadge(resp http.ResponseWriter, pdoc *doc.Package) error {
+	resp.Header().Set("Content-Type", "image/svg+xml")
+	resp.Header().Set("Cache-Control", "max-age=3600")
+	_, err := resp.Write(coverageBadge(pdoc.Coverage))
+	return err
+}
@@ -0,0 +1,36 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file or at
+// https://developers.google.com/open-source/licenses/bsd.
+
+package main
+
+import (
+	"bytes"
+	"encoding/xml"
+	"testing"
+
+	"github.com/golang/gddo/doc"
+)
+
+func TestCoverageBadge(t *testing.T) {
+	tests := []struct {
+		coverage    *doc.Coverage
+		text, color string
+	}{
+		{nil, "unknown", "#9f9f9f"},
+		{&doc.Coverage{Funcs: doc.CoverageCount{Total: 10, Documented: 9}}, "90%", "#4c1"},
+		{&doc.Coverage{Funcs: doc.CoverageCount{Total: 10, Documented: 5}, Types: doc.CoverageCount{Total: 2, Documented: 2}}, "58%", "#dfb317"},
+		{&doc.Coverage{Funcs: doc.CoverageCount{Total: 3}}, "0%", "#e05d44"},
+	}
+	for _, tt := range tests {
+		badge := coverageBadge(tt.coverage)
+		if err := xml.Unmarshal(badge, new(interface{})); err != nil {
+			t.Errorf("coverageBadge(%+v) is not valid XML: %v", tt.coverage, err)
+		}
+		if !bytes.Contains(badge, []byte(">"+tt.text+"<")) || !bytes.Contains(badge, []byte(`fill="`+tt.color+`"`)) {
+			t.Errorf("coverageBadge(%+v) = %s, want text %q and color %q", tt.coverage, badge, tt.text, tt.color)
+		}
+	}
+}
@@ -17,6 +17,7 @@ import (
 
 	"cloud.google.com/go/pubsub"
 
+	"github.com/golang/gddo/database"
 	"github.com/golang/gddo/doc"
 	"github.com/golang/gddo/gosrc"
 )
@@ -46,7 +47,9 @@ func (s *server) publishCrawl(ctx context.Context, importPath string) {
 	s.crawlTopic.Publish(ctx, &pubsub.Message{Data: b})
 }
 
-// crawlDoc fetches the package documentation from the VCS and updates the database.
+// crawlDoc fetches the package documentation from the VCS and updates the
+// database. The next crawl of the package is scheduled by the crawl state
+// of the package. See crawlInterval and crawlBackoff.
 func (s *server) crawlDoc(ctx context.Context, source string, importPath string, pdoc *doc.Package, hasSubdirs bool, nextCrawl time.Time) (*doc.Package, error) {
 	message := []interface{}{source}
 	defer func() {
@@ -62,13 +65,19 @@ func (s *server) crawlDoc(ctx context.Context, source string, importPath string,
 	}
 
 	etag := ""
+	stored := pdoc != nil
 	if pdoc != nil {
 		etag = pdoc.Etag
 		message = append(message, "etag:", etag)
 	}
 
+	st, err := s.db.CrawlState(importPath)
+	if err != nil {
+		log.Printf("ERROR db.CrawlState(%q): %v", importPath, err)
+		st = &database.CrawlState{}
+	}
+
 	start := time.Now()
-	var err error
 	if strings.HasPrefix(importPath, "code.google.com/p/go.") {
 		// Old import path for Go sub-repository.
 		pdoc = nil
@@ -95,14 +104,31 @@ func (s *server) crawlDoc(ctx context.Context, source string, importPath string,
 	}
 
 	maxAge := s.v.GetDuration(ConfigMaxAge)
-	nextCrawl = start.Add(maxAge)
-	switch {
-	case strings.HasPrefix(importPath, "github.com/") || (pdoc != nil && len(pdoc.Errors) > 0):
-		nextCrawl = start.Add(maxAge * 7)
-	case strings.HasPrefix(importPath, "gist.github.com/"):
-		// Don't spend time on gists. It's silly thing to do.
-		nextCrawl = start.Add(maxAge * 30)
+	failed := st.Failures > 0
+	switch err.(type) {
+	case nil:
+		st.RecordSuccess(start, pdoc.Etag != etag)
+	case gosrc.NotModifiedError:
+		st.RecordSuccess(start, false)
+	default:
+		st.RecordFailure(start, err)
+		if err := s.db.AddCrawlFailure(importPath, err, start); err != nil {
+			log.Printf("ERROR db.AddCrawlFailure(%q): %v", importPath, err)
+		}
+	}
+	if failed && st.Failures == 0 {
+		if err := s.db.DeleteCrawlFailure(importPath); err != nil {
+			log.Printf("ERROR db.DeleteCrawlFailure(%q): %v", importPath, err)
+		}
 	}
+	if err := s.db.PutCrawlState(importPath, st); err != nil {
+		log.Printf("ERROR db.PutCrawlState(%q): %v", importPath, err)
+	}
+	priority, perr := s.db.CrawlPriority(importPath, st, start)
+	if perr != nil {
+		log.Printf("ERROR db.CrawlPriority(%q): %v", importPath, perr)
+	}
+	nextCrawl = start.Add(crawlInterval(maxAge, importPath, pdoc, st, priority))
 
 	if err == nil {
 		message = append(message, "put:", pdoc.Etag)
@@ -137,7 +163,13 @@ func (s *server) crawlDoc(ctx context.Context, source string, importPath string,
 		}
 		return nil, e
 	} else {
-		message = append(message, "ERROR:", err)
+		message = append(message, "ERROR:", err, "failures:", st.Failures)
+		if stored {
+			// Back off from packages failing to crawl.
+			if err := s.db.SetNextCrawl(importPath, start.Add(crawlBackoff(maxAge, st.Failures))); err != nil {
+				log.Printf("ERROR db.SetNextCrawl(%q): %v", importPath, err)
+			}
+		}
 		return nil, err
 	}
 }
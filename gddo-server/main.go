@@ -20,12 +20,14 @@ import (
 	"log"
 	"net/http"
 	"os"
+	"os/signal"
 	"path"
 	"regexp"
 	"runtime/debug"
 	"sort"
 	"strconv"
 	"strings"
+	"syscall"
 	"time"
 
 	"cloud.google.com/go/logging"
@@ -175,6 +177,30 @@ func isView(req *http.Request, key string) bool {
 		(len(rq) == len(key) || rq[len(key)] == '=' || rq[len(key)] == '&')
 }
 
+const (
+	// defaultImporterDepth and maxImporterDepth are the default and the
+	// maximum of the depth parameter of transitive importer requests.
+	defaultImporterDepth = 3
+	maxImporterDepth     = 10
+
+	// maxTransitiveImporters is the maximum number of importers returned
+	// by a transitive importers request.
+	maxTransitiveImporters = 1000
+)
+
+// importerDepth returns the depth parameter of a transitive importers
+// request, limited to maxImporterDepth.
+func importerDepth(req *http.Request) int {
+	depth, err := strconv.Atoi(req.FormValue("depth"))
+	switch {
+	case err != nil || depth < 1:
+		return defaultImporterDepth
+	case depth > maxImporterDepth:
+		return maxImporterDepth
+	}
+	return depth
+}
+
 // httpEtag returns the package entity tag used in HTTP transactions.
 func (s *server) httpEtag(pdoc *doc.Package, pkgs []database.Package, importerCount int, flashMessages []flashMessage) string {
 	b := make([]byte, 0, 128)
@@ -291,16 +317,87 @@ func (s *server) servePackage(resp http.ResponseWriter, req *http.Request) error
 			"showPkgGoDevRedirectToast": showPkgGoDevRedirectToast,
 		})
 	case isView(req, "tools"):
-		proto := "http"
-		if req.Host == "godoc.org" {
-			proto = "https"
-		}
 		return s.templates.execute(resp, "tools.html", http.StatusOK, nil, map[string]interface{}{
 			"flashMessages":             flashMessages,
-			"uri":                       fmt.Sprintf("%s://%s/%s", proto, req.Host, importPath),
+			"uri":                       siteURL(req, importPath),
 			"pdoc":                      newTDoc(s.v, pdoc),
 			"showPkgGoDevRedirectToast": showPkgGoDevRedirectToast,
 		})
+	case isView(req, "file"):
+		var file *doc.File
+		for _, f := range pdoc.Files {
+			if f.Name == req.Form.Get("file") && f.HasSource {
+				file = f
+				break
+			}
+		}
+		if file == nil {
+			return &httpError{status: http.StatusNotFound}
+		}
+		src, err := s.db.Source(pdoc.ImportPath, file.Name)
+		if err != nil {
+			return err
+		}
+		if src == nil {
+			return &httpError{status: http.StatusNotFound}
+		}
+		file = &doc.File{Name: file.Name, URL: file.URL, Source: *src, HasSource: true}
+		return s.templates.execute(resp, "file.html", http.StatusOK, nil, map[string]interface{}{
+			"flashMessages":             flashMessages,
+			"pdoc":                      newTDoc(s.v, pdoc),
+			"file":                      file,
+			"showPkgGoDevRedirectToast": showPkgGoDevRedirectToast,
+		})
+	case isView(req, "vet"):
+		if pdoc.Name == "" {
+			return &httpError{status: http.StatusNotFound}
+		}
+		return s.templates.execute(resp, "vet.html", http.StatusOK, nil, map[string]interface{}{
+			"flashMessages":             flashMessages,
+			"pdoc":                      newTDoc(s.v, pdoc),
+			"showPkgGoDevRedirectToast": showPkgGoDevRedirectToast,
+		})
+	case isView(req, "files"):
+		if len(pdoc.SourceFiles) == 0 {
+			return &httpError{status: http.StatusNotFound}
+		}
+		return s.templates.execute(resp, "files.html", http.StatusOK, nil, map[string]interface{}{
+			"flashMessages":             flashMessages,
+			"pdoc":                      newTDoc(s.v, pdoc),
+			"showPkgGoDevRedirectToast": showPkgGoDevRedirectToast,
+		})
+	case isView(req, "tests"):
+		if pdoc.Name == "" {
+			return &httpError{status: http.StatusNotFound}
+		}
+		return s.templates.execute(resp, "tests.html", http.StatusOK, nil, map[string]interface{}{
+			"flashMessages":             flashMessages,
+			"pdoc":                      newTDoc(s.v, pdoc),
+			"showPkgGoDevRedirectToast": showPkgGoDevRedirectToast,
+		})
+	case isView(req, "coverage.svg"):
+		if pdoc.Name == "" {
+			return &httpError{status: http.StatusNotFound}
+		}
+		return serveCoverageBadge(resp, pdoc)
+	case isView(req, "coverage"):
+		if pdoc.Name == "" {
+			return &httpError{status: http.StatusNotFound}
+		}
+		var projectCoverage *doc.CoverageCount
+		if pdoc.ProjectRoot != "" {
+			sum, err := s.db.ProjectCoverage(pdoc.ProjectRoot)
+			if err != nil {
+				return err
+			}
+			projectCoverage = &sum
+		}
+		return s.templates.execute(resp, "coverage.html", http.StatusOK, nil, map[string]interface{}{
+			"flashMessages":             flashMessages,
+			"pdoc":                      newTDoc(s.v, pdoc),
+			"projectCoverage":           projectCoverage,
+			"showPkgGoDevRedirectToast": showPkgGoDevRedirectToast,
+		})
 	case isView(req, "importers"):
 		if pdoc.Name == "" {
 			return &httpError{status: http.StatusNotFound}
@@ -320,6 +417,40 @@ func (s *server) servePackage(resp http.ResponseWriter, req *http.Request) error
 			"pdoc":                      newTDoc(s.v, pdoc),
 			"showPkgGoDevRedirectToast": showPkgGoDevRedirectToast,
 		})
+	case isView(req, "transitive-importers"):
+		if requestType == robotRequest {
+			return &httpError{status: http.StatusForbidden}
+		}
+		if pdoc.Name == "" {
+			return &httpError{status: http.StatusNotFound}
+		}
+
+		// Share the throttle of ?import-graph requests.
+		select {
+		case s.importGraphSem <- struct{}{}:
+		default:
+			return &httpError{status: http.StatusTooManyRequests}
+		}
+		defer func() { <-s.importGraphSem }()
+
+		depth := importerDepth(req)
+		importers, truncated, err := s.db.TransitiveImporters(importPath, depth, maxTransitiveImporters)
+		if err != nil {
+			return err
+		}
+		moreDepth := 0 // depth of the link to the next level
+		if depth < maxImporterDepth && !truncated {
+			moreDepth = depth + 1
+		}
+		return s.templates.execute(resp, "transitive_importers.html", http.StatusOK, nil, map[string]interface{}{
+			"flashMessages":             flashMessages,
+			"importers":                 importers,
+			"truncated":                 truncated,
+			"depth":                     depth,
+			"moreDepth":                 moreDepth,
+			"pdoc":                      newTDoc(s.v, pdoc),
+			"showPkgGoDevRedirectToast": showPkgGoDevRedirectToast,
+		})
 	case isView(req, "import-graph"):
 		if requestType == robotRequest {
 			return &httpError{status: http.StatusForbidden}
@@ -359,6 +490,9 @@ func (s *server) servePackage(resp http.ResponseWriter, req *http.Request) error
 			"showPkgGoDevRedirectToast": showPkgGoDevRedirectToast,
 		})
 	case isView(req, "play"):
+		if err := s.db.LoadTypes(pdoc); err != nil {
+			return err
+		}
 		u, err := s.playURL(pdoc, req.Form.Get("play"), req.Header.Get("X-AppEngine-Country"))
 		if err != nil {
 			return err
@@ -423,11 +557,45 @@ func (s *server) servePackage(resp http.ResponseWriter, req *http.Request) error
 		}
 		template += templateExt(req)
 
+		// The HTML page of a document with separately stored types shows
+		// the documentation of one type at a time.
+		if template == "pkg.html" {
+			if name := req.Form.Get("type"); name != "" {
+				err = s.db.LoadTypes(pdoc, name)
+			}
+		} else {
+			err = s.db.LoadTypes(pdoc)
+		}
+		if err != nil {
+			return err
+		}
+
+		var implementations map[string][]database.Implementation
+		if template == "pkg.html" && importerCount > 0 {
+			impls, err := s.db.Implementations(importPath)
+			if err != nil {
+				return err
+			}
+			implementations = make(map[string][]database.Implementation)
+			for _, impl := range impls {
+				implementations[impl.Interface] = append(implementations[impl.Interface], impl)
+			}
+		}
+
+		tpdoc := newTDoc(s.v, pdoc)
+		if template == "pkg.html" {
+			tpdoc.externalMembers, err = s.externalMembers(req.Context(), pdoc)
+			if err != nil {
+				return err
+			}
+		}
+
 		return s.templates.execute(resp, template, status, http.Header{"Etag": {etag}}, map[string]interface{}{
 			"flashMessages":             flashMessages,
 			"pkgs":                      pkgs,
-			"pdoc":                      newTDoc(s.v, pdoc),
+			"pdoc":                      tpdoc,
 			"importerCount":             importerCount,
+			"implementations":           implementations,
 			"showPkgGoDevRedirectToast": showPkgGoDevRedirectToast,
 		})
 	}
@@ -439,6 +607,15 @@ func (s *server) serveRefresh(resp http.ResponseWriter, req *http.Request) error
 	if err != nil {
 		return err
 	}
+	// Refreshed packages are crawled more often.
+	st, err := s.db.CrawlState(importPath)
+	if err != nil {
+		return err
+	}
+	st.RecordRefresh(time.Now())
+	if err := s.db.PutCrawlState(importPath, st); err != nil {
+		return err
+	}
 	c := make(chan error, 1)
 	go func() {
 		_, err := s.crawlDoc(req.Context(), "rfrsh", importPath, nil, len(pkgs) > 0, time.Time{})
@@ -480,6 +657,28 @@ func (s *server) serveGoSubrepoIndex(resp http.ResponseWriter, req *http.Request
 	})
 }
 
+// maxCrawlFailures is the number of crawl failures listed by
+// serveCrawlFailures.
+const maxCrawlFailures = 500
+
+func (s *server) serveCrawlFailures(resp http.ResponseWriter, req *http.Request) error {
+	class := req.Form.Get("class")
+	failures, err := s.db.CrawlFailures(class)
+	if err != nil {
+		return err
+	}
+	total := len(failures)
+	if len(failures) > maxCrawlFailures {
+		failures = failures[:maxCrawlFailures]
+	}
+	return s.templates.execute(resp, "crawl_failures.html", http.StatusOK, nil, map[string]interface{}{
+		"class":    class,
+		"classes":  []string{database.CrawlNotFound, database.CrawlRemote, database.CrawlTimeout, database.CrawlError},
+		"failures": failures,
+		"total":    total,
+	})
+}
+
 type byPath struct {
 	pkgs []database.Package
 	rank []int
@@ -546,6 +745,10 @@ func (s *server) popular() ([]database.Package, error) {
 	return pkgs, nil
 }
 
+// topRankedCount is the number of packages with the highest import rank on
+// the home page.
+const topRankedCount = 10
+
 func (s *server) serveHome(resp http.ResponseWriter, req *http.Request) error {
 	if req.URL.Path != "/" {
 		return s.servePackage(resp, req)
@@ -558,9 +761,15 @@ func (s *server) serveHome(resp http.ResponseWriter, req *http.Request) error {
 			return err
 		}
 
+		topRanked, err := s.db.TopImportRanks(topRankedCount)
+		if err != nil {
+			return err
+		}
+
 		return s.templates.execute(resp, "home"+templateExt(req), http.StatusOK, nil,
 			map[string]interface{}{
-				"Popular": pkgs,
+				"Popular":   pkgs,
+				"TopRanked": topRanked,
 
 				"showPkgGoDevRedirectToast": userReturningFromPkgGoDev(req),
 			})
@@ -597,8 +806,9 @@ func (s *server) serveHome(resp http.ResponseWriter, req *http.Request) error {
 
 	return s.templates.execute(resp, "results"+templateExt(req), http.StatusOK, nil,
 		map[string]interface{}{
-			"q":    q,
-			"pkgs": pkgs,
+			"q":             q,
+			"pkgs":          pkgs,
+			"indexBuilding": !s.db.SearchIndexReady(),
 
 			"showPkgGoDevRedirectToast": userReturningFromPkgGoDev(req),
 		})
@@ -613,6 +823,54 @@ func (s *server) serveAbout(resp http.ResponseWriter, req *http.Request) error {
 		})
 }
 
+// siteURL returns the absolute URL of the path on the site serving req.
+func siteURL(req *http.Request, path string) string {
+	proto := "http"
+	if req.Host == "godoc.org" {
+		proto = "https"
+	}
+	return fmt.Sprintf("%s://%s/%s", proto, req.Host, path)
+}
+
+func (s *server) serveOpenSearch(resp http.ResponseWriter, req *http.Request) error {
+	return s.templates.execute(resp, "opensearch.xml", http.StatusOK, nil, req.Host)
+}
+
+const (
+	// maxSuggestions is the number of completions returned for a search
+	// suggestion request.
+	maxSuggestions = 10
+
+	// suggestTimeout is the time budget of a search suggestion request.
+	suggestTimeout = 100 * time.Millisecond
+)
+
+// serveSuggest serves the completions of the query in the OpenSearch
+// suggestions format: the query followed by the arrays of the completions,
+// their descriptions and their URLs.
+func (s *server) serveSuggest(resp http.ResponseWriter, req *http.Request) error {
+	q := strings.TrimSpace(req.Form.Get("q"))
+	ctx, cancel := context.WithTimeout(req.Context(), suggestTimeout)
+	defer cancel()
+	suggestions, err := s.db.Suggest(ctx, q, maxSuggestions)
+	if err != nil {
+		return err
+	}
+	completions := make([]string, len(suggestions))
+	descriptions := make([]string, len(suggestions))
+	urls := make([]string, len(suggestions))
+	for i, sg := range suggestions {
+		completions[i] = sg.Text
+		descriptions[i] = sg.Synopsis
+		urls[i] = siteURL(req, sg.Path)
+		if sg.Symbol != "" {
+			urls[i] += "#" + sg.Symbol
+		}
+	}
+	resp.Header().Set("Content-Type", jsonMIMEType)
+	return json.NewEncoder(resp).Encode([]interface{}{q, completions, descriptions, urls})
+}
+
 func (s *server) serveBot(resp http.ResponseWriter, req *http.Request) error {
 	return s.templates.execute(resp, "bot.html", http.StatusOK, nil, nil)
 }
@@ -654,8 +912,13 @@ func (s *server) serveAPISearch(resp http.ResponseWriter, req *http.Request) err
 
 	var data = struct {
 		Results []database.Package `json:"results"`
+
+		// IndexBuilding is set while the search index is built and the
+		// results may be incomplete.
+		IndexBuilding bool `json:"indexBuilding,omitempty"`
 	}{
 		pkgs,
+		!s.db.SearchIndexReady(),
 	}
 	resp.Header().Set("Content-Type", jsonMIMEType)
 	return json.NewEncoder(resp).Encode(&data)
@@ -690,6 +953,38 @@ func (s *server) serveAPIImporters(resp http.ResponseWriter, req *http.Request)
 	return json.NewEncoder(resp).Encode(&data)
 }
 
+func (s *server) serveAPITransitiveImporters(resp http.ResponseWriter, req *http.Request) error {
+	if s.isRobot(req) {
+		return &httpError{status: http.StatusForbidden}
+	}
+
+	// Share the throttle of ?import-graph requests.
+	select {
+	case s.importGraphSem <- struct{}{}:
+	default:
+		return &httpError{status: http.StatusTooManyRequests}
+	}
+	defer func() { <-s.importGraphSem }()
+
+	importPath := strings.TrimPrefix(req.URL.Path, "/transitive-importers/")
+	importers, truncated, err := s.db.TransitiveImporters(importPath, importerDepth(req), maxTransitiveImporters)
+	if err != nil {
+		return err
+	}
+	if importers == nil {
+		importers = []database.Importer{}
+	}
+	data := struct {
+		Results   []database.Importer `json:"results"`
+		Truncated bool                `json:"truncated"`
+	}{
+		importers,
+		truncated,
+	}
+	resp.Header().Set("Content-Type", jsonMIMEType)
+	return json.NewEncoder(resp).Encode(&data)
+}
+
 func (s *server) serveAPIImports(resp http.ResponseWriter, req *http.Request) error {
 	importPath := strings.TrimPrefix(req.URL.Path, "/imports/")
 	pdoc, _, err := s.getDoc(req.Context(), importPath, robotRequest)
@@ -718,6 +1013,125 @@ func (s *server) serveAPIImports(resp http.ResponseWriter, req *http.Request) er
 	return json.NewEncoder(resp).Encode(&data)
 }
 
+func (s *server) serveAPIErrors(resp http.ResponseWriter, req *http.Request) error {
+	importPath := strings.TrimPrefix(req.URL.Path, "/errors/")
+	pdoc, _, err := s.getDoc(req.Context(), importPath, robotRequest)
+	if err != nil {
+		return err
+	}
+	if pdoc == nil || pdoc.Name == "" {
+		return &httpError{status: http.StatusNotFound}
+	}
+	data := struct {
+		Errors []*doc.ErrorDecl `json:"errors"`
+	}{
+		pdoc.ErrorCatalog,
+	}
+	resp.Header().Set("Content-Type", jsonMIMEType)
+	return json.NewEncoder(resp).Encode(&data)
+}
+
+// symbolResult is a symbol in the symbols API response.
+type symbolResult struct {
+	ID string `json:"id"`
+	*doc.Symbol
+}
+
+func (s *server) serveAPISymbols(resp http.ResponseWriter, req *http.Request) error {
+	importPath := strings.TrimPrefix(req.URL.Path, "/symbols/")
+	pdoc, _, err := s.getDoc(req.Context(), importPath, robotRequest)
+	if err != nil {
+		return err
+	}
+	if pdoc == nil || pdoc.Name == "" {
+		return &httpError{status: http.StatusNotFound}
+	}
+	symbols := pdoc.Symbols
+	if symbols == nil {
+		// Documents loaded from the database do not include the symbols.
+		symbols, err = s.db.Symbols(pdoc.ImportPath)
+		if err != nil {
+			return err
+		}
+	}
+	data := struct {
+		Results []symbolResult `json:"results"`
+	}{
+		make([]symbolResult, len(symbols)),
+	}
+	for i, sym := range symbols {
+		data.Results[i] = symbolResult{ID: doc.SymbolID(pdoc.ImportPath, sym.Name), Symbol: sym}
+	}
+	resp.Header().Set("Content-Type", jsonMIMEType)
+	return json.NewEncoder(resp).Encode(&data)
+}
+
+func (s *server) serveAPIVersions(resp http.ResponseWriter, req *http.Request) error {
+	importPath := strings.TrimPrefix(req.URL.Path, "/versions/")
+	versions, err := s.db.Versions(importPath)
+	if err != nil {
+		return err
+	}
+	if versions == nil {
+		versions = []database.Version{}
+	}
+	data := struct {
+		Versions []database.Version `json:"versions"`
+	}{
+		versions,
+	}
+	resp.Header().Set("Content-Type", jsonMIMEType)
+	return json.NewEncoder(resp).Encode(&data)
+}
+
+// serveAPIDoc serves the document of a package in the encoding described
+// by the schema served by serveAPISchema. The version parameter selects a
+// version in the history of the package. See serveAPIVersions.
+func (s *server) serveAPIDoc(resp http.ResponseWriter, req *http.Request) error {
+	importPath := strings.TrimPrefix(req.URL.Path, "/doc/")
+	var (
+		pdoc *doc.Package
+		err  error
+	)
+	if key := req.FormValue("version"); key != "" {
+		pdoc, err = s.db.GetVersion(importPath, key)
+	} else {
+		pdoc, _, err = s.getDoc(req.Context(), importPath, robotRequest)
+	}
+	if err != nil {
+		return err
+	}
+	if pdoc == nil {
+		return &httpError{status: http.StatusNotFound}
+	}
+	if err := s.db.LoadTypes(pdoc); err != nil {
+		return err
+	}
+	if pdoc.Symbols == nil {
+		pdoc.Symbols, err = s.db.Symbols(pdoc.ImportPath)
+		if err != nil {
+			return err
+		}
+	}
+	p, err := doc.EncodePackage(pdoc)
+	if err != nil {
+		return err
+	}
+	resp.Header().Set("Content-Type", jsonMIMEType)
+	_, err = resp.Write(p)
+	return err
+}
+
+func serveAPISchema(resp http.ResponseWriter, req *http.Request) error {
+	p, err := doc.PackageSchema()
+	if err != nil {
+		return err
+	}
+	resp.Header().Set("Content-Type", jsonMIMEType)
+	_, err = resp.Write(p)
+	return err
+}
+
 func serveAPIHome(resp http.ResponseWriter, req *http.Request) error {
 	return &httpError{status: http.StatusNotFound}
 }
@@ -877,6 +1291,9 @@ type server struct {
 
 	// A semaphore to limit concurrent ?import-graph requests.
 	importGraphSem chan struct{}
+
+	// Limits the background crawls in progress.
+	crawls *crawlLimiter
 }
 
 func newServer(ctx context.Context, v *viper.Viper) (*server, error) {
@@ -884,6 +1301,7 @@ func newServer(ctx context.Context, v *viper.Viper) (*server, error) {
 		v:              v,
 		httpClient:     newHTTPClient(v),
 		importGraphSem: make(chan struct{}, 10),
+		crawls:         newCrawlLimiter(v.GetInt(ConfigCrawlWorkers), v.GetInt(ConfigCrawlHostLimit)),
 	}
 
 	var err error
@@ -934,7 +1352,13 @@ func newServer(ctx context.Context, v *viper.Viper) (*server, error) {
 	apiMux.Handle("/search", apiHandler(s.serveAPISearch))
 	apiMux.Handle("/packages", apiHandler(s.serveAPIPackages))
 	apiMux.Handle("/importers/", apiHandler(s.serveAPIImporters))
+	apiMux.Handle("/transitive-importers/", apiHandler(s.serveAPITransitiveImporters))
 	apiMux.Handle("/imports/", apiHandler(s.serveAPIImports))
+	apiMux.Handle("/errors/", apiHandler(s.serveAPIErrors))
+	apiMux.Handle("/symbols/", apiHandler(s.serveAPISymbols))
+	apiMux.Handle("/doc/", apiHandler(s.serveAPIDoc))
+	apiMux.Handle("/versions/", apiHandler(s.serveAPIVersions))
+	apiMux.Handle("/schema", apiHandler(serveAPISchema))
 	apiMux.Handle("/", apiHandler(serveAPIHome))
 
 	mux := http.NewServeMux()
@@ -965,6 +1389,9 @@ func newServer(ctx context.Context, v *viper.Viper) (*server, error) {
 	mux.Handle("/-/go", handler(pkgGoDevRedirectHandler(s.serveGoIndex)))
 	mux.Handle("/-/subrepo", handler(s.serveGoSubrepoIndex))
 	mux.Handle("/-/refresh", handler(s.serveRefresh))
+	mux.Handle("/-/crawl-failures", handler(s.serveCrawlFailures))
+	mux.Handle("/-/suggest", handler(s.serveSuggest))
+	mux.Handle("/-/opensearch.xml", handler(s.serveOpenSearch))
 	mux.Handle("/about", http.RedirectHandler("/-/about", http.StatusMovedPermanently))
 	mux.Handle("/favicon.ico", staticServer.FileHandler("favicon.ico"))
 	mux.Handle("/google3d2f3cd4cc2bb44b.html", staticServer.FileHandler("google3d2f3cd4cc2bb44b.html"))
@@ -1106,6 +1533,10 @@ func (s *server) teeRequestToPkgGoDev(r *http.Request, latency time.Duration, st
 	}
 }
 
+// shutdownTimeout is how long the server waits for active requests when it
+// is interrupted.
+const shutdownTimeout = 10 * time.Second
+
 func main() {
 	ctx := context.Background()
 	v, err := loadConfig(ctx, os.Args)
@@ -1113,12 +1544,22 @@ func main() {
 		log.Fatal(ctx, "load config", "error", err.Error())
 	}
 	doc.SetDefaultGOOS(v.GetString(ConfigDefaultGOOS))
+	if err := doc.SetAnalyzers(v.GetStringSlice(ConfigAnalyzers)); err != nil {
+		log.Fatal(ctx, "set analyzers", "error", err.Error())
+	}
 
 	s, err := newServer(ctx, v)
 	if err != nil {
 		log.Fatal("error creating server:", err)
 	}
 
+	if s.db.RemoteClient == nil {
+		go func() {
+			if err := s.db.BuildSearchIndex(ctx); err != nil {
+				log.Printf("Build search index: %v", err)
+			}
+		}()
+	}
 	go func() {
 		for range time.Tick(s.v.GetDuration(ConfigCrawlInterval)) {
 			if err := s.doCrawl(ctx); err != nil {
@@ -1126,6 +1567,20 @@ func main() {
 			}
 		}
 	}()
+	go func() {
+		for range time.Tick(s.v.GetDuration(ConfigRankInterval)) {
+			if err := s.updateImportRanks(ctx); err != nil {
+				log.Printf("Task import ranks: %v", err)
+			}
+		}
+	}()
+	go func() {
+		for range time.Tick(s.v.GetDuration(ConfigRetryInterval)) {
+			if err := s.retryCrawlFailures(); err != nil {
+				log.Printf("Task crawl retries: %v", err)
+			}
+		}
+	}()
 	go func() {
 		for range time.Tick(s.v.GetDuration(ConfigGithubInterval)) {
 			if err := s.readGitHubUpdates(ctx); err != nil {
@@ -1134,5 +1589,22 @@ func main() {
 		}
 	}()
 	http.Handle("/", s)
-	log.Fatal(http.ListenAndServe(s.v.GetString(ConfigBindAddress), s))
+	srv := &http.Server{Addr: s.v.GetString(ConfigBindAddress), Handler: s}
+	go func() {
+		c := make(chan os.Signal, 1)
+		signal.Notify(c, os.Interrupt, syscall.SIGTERM)
+		<-c
+		ctx, cancel := context.WithTimeout(ctx, shutdownTimeout)
+		defer cancel()
+		if err := srv.Shutdown(ctx); err != nil {
+			log.Printf("Shutdown: %v", err)
+		}
+	}()
+	if err := srv.ListenAndServe(); err != http.ErrServerClosed {
+		log.Fatal(err)
+	}
+	// Save the pending changes of the file store.
+	if err := s.db.Close(); err != nil {
+		log.Fatal("error closing database:", err)
+	}
 }
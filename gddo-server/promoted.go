@@ -0,0 +1,127 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file or at
+// https://developers.google.com/open-source/licenses/bsd.
+
+package main
+
+import (
+	"context"
+	"strings"
+
+	"github.com/golang/gddo/doc"
+)
+
+// externalMembers returns the members promoted to the types in pdoc from
+// embedded types declared in other packages, keyed by type name. The
+// members are looked up in the stored documentation of the other packages.
+// Members of embedded types that are not in the database are omitted.
+func (s *server) externalMembers(ctx context.Context, pdoc *doc.Package) (map[string][]*doc.Member, error) {
+	docs := make(map[string]*doc.Package)
+	result := make(map[string][]*doc.Member)
+	for _, t := range pdoc.Types {
+		if len(t.ExternalEmbeds) == 0 {
+			continue
+		}
+
+		shadowed := make(map[string]bool)
+		for _, m := range t.Methods {
+			shadowed[m.Name] = true
+		}
+		for _, m := range t.Fields {
+			shadowed[m.Name] = true
+		}
+		for _, m := range t.Promoted {
+			shadowed[m.Name] = true
+		}
+
+		var members []*doc.Member
+		count := make(map[string]int)
+		for _, ref := range t.ExternalEmbeds {
+			edoc, ok := docs[ref.Path]
+			if !ok {
+				var err error
+				edoc, _, err = s.db.GetDoc(ctx, ref.Path)
+				if err != nil {
+					return nil, err
+				}
+				docs[ref.Path] = edoc
+			}
+			if edoc == nil {
+				continue
+			}
+			// The embedded type is a stub if the types of the package are
+			// stored separately.
+			if err := s.db.LoadTypes(edoc, ref.Name); err != nil {
+				return nil, err
+			}
+			for _, m := range embeddedMembers(edoc, ref) {
+				if !shadowed[m.Name] {
+					count[m.Name]++
+					members = append(members, m)
+				}
+			}
+		}
+
+		// Members with the same name from different embedded types are
+		// ambiguous and not promoted.
+		for _, m := range members {
+			if count[m.Name] == 1 {
+				result[t.Name] = append(result[t.Name], m)
+			}
+		}
+	}
+	return result, nil
+}
+
+// embeddedMembers returns the exported methods and fields of the type ref
+// declared in edoc.
+func embeddedMembers(edoc *doc.Package, ref *doc.TypeRef) []*doc.Member {
+	var et *doc.Type
+	for _, t := range edoc.Types {
+		if t.Name == ref.Name {
+			et = t
+			break
+		}
+	}
+	if et == nil {
+		return nil
+	}
+
+	from := doc.TypeRef{Path: ref.Path, Name: ref.Name}
+	var members []*doc.Member
+	for _, f := range et.Methods {
+		m := &doc.Member{
+			Name:   f.Name,
+			Method: true,
+			Text:   methodText(f),
+			From:   from,
+		}
+		m.From.Ptr = !ref.Ptr && strings.HasPrefix(f.Recv, "*")
+		members = append(members, m)
+	}
+	for _, p := range et.Promoted {
+		m := *p
+		if ref.Ptr {
+			m.From.Ptr = false
+		}
+		members = append(members, &m)
+	}
+	for _, f := range et.Fields {
+		m := *f
+		m.From = from
+		members = append(members, &m)
+	}
+	return members
+}
+
+// methodText returns the signature of method f without the receiver and
+// name.
+func methodText(f *doc.Func) string {
+	text := f.Decl.Text
+	if i := strings.Index(text, ") "+f.Name+"("); i >= 0 {
+		return text[i+len(") "+f.Name):]
+	}
+	return "()"
+}
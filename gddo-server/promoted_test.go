@@ -0,0 +1,130 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file or at
+// https://developers.google.com/open-source/licenses/bsd.
+
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/golang/gddo/database"
+	"github.com/golang/gddo/doc"
+)
+
+// newTestServer returns a server with a database in a temporary file.
+func newTestServer(t *testing.T) (s *server, cleanup func()) {
+	dir, err := ioutil.TempDir("", "gddo-server")
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, err := database.New("file://"+filepath.Join(dir, "gddo.db"), 0, false, "")
+	if err != nil {
+		os.RemoveAll(dir)
+		t.Fatal(err)
+	}
+	return &server{db: db}, func() {
+		db.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+func TestEmbeddedMembers(t *testing.T) {
+	edoc := &doc.Package{
+		ImportPath: "bytes",
+		Types: []*doc.Type{{
+			Name: "Buffer",
+			Methods: []*doc.Func{
+				{Name: "Len", Recv: "*Buffer", Decl: doc.Code{Text: "func (b *Buffer) Len() int"}},
+				{Name: "Read", Recv: "*Buffer", Decl: doc.Code{Text: "func (b *Buffer) Read(p []byte) (n int, err error)"}},
+				{Name: "String", Recv: "Buffer", Decl: doc.Code{Text: "func (b Buffer) String() string"}},
+			},
+			Fields: []*doc.Member{{Name: "Size", Text: "int"}},
+		}},
+	}
+
+	want := []*doc.Member{
+		{Name: "Len", Method: true, Text: "() int", From: doc.TypeRef{Path: "bytes", Name: "Buffer", Ptr: true}},
+		{Name: "Read", Method: true, Text: "(p []byte) (n int, err error)", From: doc.TypeRef{Path: "bytes", Name: "Buffer", Ptr: true}},
+		{Name: "String", Method: true, Text: "() string", From: doc.TypeRef{Path: "bytes", Name: "Buffer"}},
+		{Name: "Size", Text: "int", From: doc.TypeRef{Path: "bytes", Name: "Buffer"}},
+	}
+	got := embeddedMembers(edoc, &doc.TypeRef{Path: "bytes", Name: "Buffer"})
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("embeddedMembers(value) mismatch (-want +got):\n%s", diff)
+	}
+
+	for _, m := range want {
+		m.From.Ptr = false
+	}
+	got = embeddedMembers(edoc, &doc.TypeRef{Path: "bytes", Name: "Buffer", Ptr: true})
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("embeddedMembers(pointer) mismatch (-want +got):\n%s", diff)
+	}
+
+	if got := embeddedMembers(edoc, &doc.TypeRef{Path: "bytes", Name: "Reader"}); got != nil {
+		t.Errorf("embeddedMembers(missing) = %v, want nil", got)
+	}
+}
+
+func TestExternalMembersTypePages(t *testing.T) {
+	ctx := context.Background()
+	s, cleanup := newTestServer(t)
+	defer cleanup()
+
+	// The documentation of the types does not compress, so the types of
+	// the embedded package are stored separately.
+	r := rand.New(rand.NewSource(1))
+	edoc := &doc.Package{
+		ImportPath:  "example.com/big",
+		Name:        "big",
+		ProjectRoot: "example.com/big",
+		Etag:        doc.PackageVersion + "-1",
+	}
+	for i := 0; i < 20; i++ {
+		b := make([]byte, 75000)
+		r.Read(b)
+		name := "T" + strconv.Itoa(i)
+		edoc.Types = append(edoc.Types, &doc.Type{
+			Name:    name,
+			Decl:    doc.Code{Text: "type " + name + " struct{ Size int }"},
+			Doc:     base64.StdEncoding.EncodeToString(b),
+			Methods: []*doc.Func{{Name: "Len", Recv: name, Decl: doc.Code{Text: "func (t " + name + ") Len() int"}}},
+			Fields:  []*doc.Member{{Name: "Size", Text: "int"}},
+		})
+	}
+	if err := s.db.Put(ctx, edoc, time.Time{}, false); err != nil {
+		t.Fatal(err)
+	}
+
+	pdoc := &doc.Package{
+		ImportPath: "example.com/p",
+		Types: []*doc.Type{{
+			Name:           "T",
+			ExternalEmbeds: []*doc.TypeRef{{Path: "example.com/big", Name: "T3"}},
+		}},
+	}
+	got, err := s.externalMembers(ctx, pdoc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	from := doc.TypeRef{Path: "example.com/big", Name: "T3"}
+	want := map[string][]*doc.Member{"T": {
+		{Name: "Len", Method: true, Text: "() int", From: from},
+		{Name: "Size", Text: "int", From: from},
+	}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("externalMembers mismatch (-want +got):\n%s", diff)
+	}
+}
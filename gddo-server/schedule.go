@@ -0,0 +1,140 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file or at
+// https://developers.google.com/open-source/licenses/bsd.
+
+package main
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/gddo/database"
+	"github.com/golang/gddo/doc"
+)
+
+// The time between crawls of a package is the maximum age of a document
+// scaled by the observed time between new versions of the package, divided
+// by the crawl priority of the package (see database.CrawlPriority) and
+// bounded by the fractions of the maximum age below.
+const (
+	minCrawlInterval = 1.0 / 8 // of ConfigMaxAge
+	maxCrawlInterval = 60      // times ConfigMaxAge
+	maxCrawlBackoff  = 30      // times ConfigMaxAge
+)
+
+// crawlInterval returns the time until the next crawl of a package after a
+// successful crawl.
+func crawlInterval(maxAge time.Duration, importPath string, pdoc *doc.Package, st *database.CrawlState, priority float64) time.Duration {
+	f := 1.0
+	switch {
+	case strings.HasPrefix(importPath, "github.com/") || (pdoc != nil && len(pdoc.Errors) > 0):
+		// Updates to GitHub repositories are found by readGitHubUpdates
+		// and packages with errors are rarely fixed.
+		f = 7
+	case strings.HasPrefix(importPath, "gist.github.com/"):
+		// Don't spend time on gists. It's silly thing to do.
+		f = 30
+	}
+	if st.ChangeInterval > 0 {
+		// Crawl twice between new versions.
+		c := float64(st.ChangeInterval) / float64(2*maxAge)
+		f *= clamp(c, 1.0/4, 8)
+	}
+	if priority > 1 {
+		f /= priority
+	}
+	return time.Duration(float64(maxAge) * clamp(f, minCrawlInterval, maxCrawlInterval))
+}
+
+// crawlBackoff returns the time until the next crawl of a package after the
+// given number of consecutive failed crawls. The time doubles with each
+// failure.
+func crawlBackoff(maxAge time.Duration, failures int) time.Duration {
+	d := maxAge / 3
+	for i := 1; i < failures && d < maxCrawlBackoff*maxAge; i++ {
+		d *= 2
+	}
+	if d > maxCrawlBackoff*maxAge {
+		d = maxCrawlBackoff * maxAge
+	}
+	return d
+}
+
+func clamp(x, min, max float64) float64 {
+	switch {
+	case x < min:
+		return min
+	case x > max:
+		return max
+	}
+	return x
+}
+
+// crawlHost returns the host of the import path used to limit the crawls
+// in progress per host. Standard packages have the host "std".
+func crawlHost(importPath string) string {
+	host := importPath
+	if i := strings.IndexByte(host, '/'); i >= 0 {
+		host = host[:i]
+	}
+	if !strings.Contains(host, ".") {
+		return "std"
+	}
+	return host
+}
+
+// crawlLimiter limits the background crawls in progress, in total and per
+// host, so that a slow host does not hold up the crawls of other hosts.
+type crawlLimiter struct {
+	max     int // crawls in progress
+	maxHost int // crawls in progress per host
+
+	mu    sync.Mutex
+	paths map[string]bool // import paths being crawled
+	hosts map[string]int  // crawls in progress by host
+}
+
+func newCrawlLimiter(max, maxHost int) *crawlLimiter {
+	return &crawlLimiter{
+		max:     max,
+		maxHost: maxHost,
+		paths:   make(map[string]bool),
+		hosts:   make(map[string]int),
+	}
+}
+
+// full returns true if no crawl can be started.
+func (l *crawlLimiter) full() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.paths) >= l.max
+}
+
+// acquire starts a crawl of the import path if the limits allow it and the
+// path is not being crawled. The caller calls release when the crawl is
+// done.
+func (l *crawlLimiter) acquire(importPath string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	host := crawlHost(importPath)
+	if len(l.paths) >= l.max || l.hosts[host] >= l.maxHost || l.paths[importPath] {
+		return false
+	}
+	l.paths[importPath] = true
+	l.hosts[host]++
+	return true
+}
+
+// release ends a crawl started by acquire.
+func (l *crawlLimiter) release(importPath string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	host := crawlHost(importPath)
+	delete(l.paths, importPath)
+	if l.hosts[host]--; l.hosts[host] <= 0 {
+		delete(l.hosts, host)
+	}
+}
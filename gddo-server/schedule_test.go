@@ -0,0 +1,84 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file or at
+// https://developers.google.com/open-source/licenses/bsd.
+
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/gddo/database"
+	"github.com/golang/gddo/doc"
+)
+
+func TestCrawlInterval(t *testing.T) {
+	const maxAge = 24 * time.Hour
+	for _, tt := range []struct {
+		path     string
+		pdoc     *doc.Package
+		st       database.CrawlState
+		priority float64
+		want     time.Duration
+	}{
+		{"example.com/a", nil, database.CrawlState{}, 1, maxAge},
+		{"github.com/a/b", nil, database.CrawlState{}, 1, 7 * maxAge},
+		{"example.com/a", &doc.Package{Errors: []string{"x"}}, database.CrawlState{}, 1, 7 * maxAge},
+		{"gist.github.com/a/1", nil, database.CrawlState{}, 1, 30 * maxAge},
+		// Packages with new versions every day are crawled twice a day.
+		{"example.com/a", nil, database.CrawlState{ChangeInterval: maxAge}, 1, maxAge / 2},
+		{"example.com/a", nil, database.CrawlState{ChangeInterval: 100 * maxAge}, 1, 8 * maxAge},
+		{"example.com/a", nil, database.CrawlState{}, 4, maxAge / 4},
+		{"example.com/a", nil, database.CrawlState{ChangeInterval: maxAge}, 100, maxAge / 8},
+		{"gist.github.com/a/1", nil, database.CrawlState{ChangeInterval: 100 * maxAge}, 1, 60 * maxAge},
+	} {
+		if got := crawlInterval(maxAge, tt.path, tt.pdoc, &tt.st, tt.priority); got != tt.want {
+			t.Errorf("crawlInterval(%q, %+v, %g) = %v, want %v", tt.path, tt.st, tt.priority, got, tt.want)
+		}
+	}
+}
+
+func TestCrawlBackoff(t *testing.T) {
+	const maxAge = 24 * time.Hour
+	for _, tt := range []struct {
+		failures int
+		want     time.Duration
+	}{
+		{1, maxAge / 3},
+		{2, 2 * maxAge / 3},
+		{4, 8 * maxAge / 3},
+		{20, 30 * maxAge},
+	} {
+		if got := crawlBackoff(maxAge, tt.failures); got != tt.want {
+			t.Errorf("crawlBackoff(%d) = %v, want %v", tt.failures, got, tt.want)
+		}
+	}
+}
+
+func TestCrawlLimiter(t *testing.T) {
+	l := newCrawlLimiter(3, 2)
+	for _, tt := range []struct {
+		path string
+		want bool
+	}{
+		{"github.com/a/b", true},
+		{"github.com/a/b", false}, // already crawling
+		{"github.com/c/d", true},
+		{"github.com/e/f", false}, // host limit
+		{"fmt", true},
+		{"example.com/a", false}, // total limit
+	} {
+		if got := l.acquire(tt.path); got != tt.want {
+			t.Errorf("acquire(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+	if !l.full() {
+		t.Error("limiter is not full")
+	}
+	l.release("github.com/a/b")
+	if l.full() || !l.acquire("github.com/e/f") {
+		t.Error("acquire(github.com/e/f) failed after release")
+	}
+}
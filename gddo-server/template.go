@@ -11,6 +11,7 @@ import (
 	"encoding/base64"
 	"errors"
 	"fmt"
+	"go/ast"
 	godoc "go/doc"
 	htemp "html/template"
 	"io"
@@ -80,6 +81,11 @@ type tdoc struct {
 	*doc.Package
 	allExamples    []*texample
 	sourcegraphURL string
+	sourceViewer   bool
+
+	// Members promoted from embedded types in other packages, keyed by
+	// type name.
+	externalMembers map[string][]*doc.Member
 }
 
 type texample struct {
@@ -94,21 +100,213 @@ func newTDoc(v *viper.Viper, pdoc *doc.Package) *tdoc {
 	return &tdoc{
 		Package:        pdoc,
 		sourcegraphURL: v.GetString(ConfigSourcegraphURL),
+		sourceViewer:   v.GetBool(ConfigSourceViewer),
+	}
+}
+
+// Promoted returns the methods and fields promoted to type t from embedded
+// types.
+func (pdoc *tdoc) Promoted(t *doc.Type) []*doc.Member {
+	members := t.Promoted[:len(t.Promoted):len(t.Promoted)]
+	return append(members, pdoc.externalMembers[t.Name]...)
+}
+
+// PlatformSpecific returns true if some declarations in the package do not
+// exist on all platforms.
+func (pdoc *tdoc) PlatformSpecific() bool {
+	for _, v := range pdoc.Consts {
+		if v.Platforms != nil {
+			return true
+		}
+	}
+	for _, v := range pdoc.Vars {
+		if v.Platforms != nil {
+			return true
+		}
+	}
+	for _, f := range pdoc.Funcs {
+		if f.Platforms != nil {
+			return true
+		}
+	}
+	for _, t := range pdoc.Types {
+		if t.Platforms != nil {
+			return true
+		}
+		for _, v := range t.Consts {
+			if v.Platforms != nil {
+				return true
+			}
+		}
+		for _, v := range t.Vars {
+			if v.Platforms != nil {
+				return true
+			}
+		}
+		for _, f := range t.Funcs {
+			if f.Platforms != nil {
+				return true
+			}
+		}
+		for _, f := range t.Methods {
+			if f.Platforms != nil {
+				return true
+			}
+		}
+		for _, m := range t.Promoted {
+			if m.Platforms != nil {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// flagSet is the flags of a command or subcommand.
+type flagSet struct {
+	Name  string // "" for the command
+	Flags []*doc.Flag
+}
+
+// FlagSets returns the flags of the command grouped by subcommand. The flags
+// of the command are first.
+func (pdoc *tdoc) FlagSets() []*flagSet {
+	if len(pdoc.Flags) == 0 {
+		return nil
+	}
+	sets := []*flagSet{{}}
+	index := map[string]*flagSet{"": sets[0]}
+	for _, f := range pdoc.Flags {
+		fs := index[f.FlagSet]
+		if fs == nil {
+			fs = &flagSet{Name: f.FlagSet}
+			index[f.FlagSet] = fs
+			sets = append(sets, fs)
+		}
+		fs.Flags = append(fs.Flags, f)
+	}
+	if len(sets[0].Flags) == 0 {
+		sets = sets[1:]
+	}
+	return sets
+}
+
+// FlagName returns the flag as it is written on the command line.
+func (pdoc *tdoc) FlagName(f *doc.Flag) string {
+	if !f.Pflag {
+		return "-" + f.Name
+	}
+	if f.Shorthand != "" {
+		return "-" + f.Shorthand + ", --" + f.Name
+	}
+	return "--" + f.Name
+}
+
+// TestsOfKind returns the tests of the given kind: Test, Benchmark or Fuzz.
+func (pdoc *tdoc) TestsOfKind(kind string) []*doc.TestFunc {
+	var tests []*doc.TestFunc
+	for _, t := range pdoc.Tests {
+		if t.Kind == kind {
+			tests = append(tests, t)
+		}
+	}
+	return tests
+}
+
+// TestSourceLink returns a link to the source of test t or the name of the
+// test if the source is not available.
+func (pdoc *tdoc) TestSourceLink(t *doc.TestFunc) htemp.HTML {
+	if pdoc.LineFmt == "" || int(t.File) >= len(pdoc.TestFiles) || pdoc.TestFiles[t.File].URL == "" {
+		return htemp.HTML(htemp.HTMLEscapeString(t.Name))
 	}
+	u := fmt.Sprintf(pdoc.LineFmt, pdoc.TestFiles[t.File].URL, t.Line)
+	return htemp.HTML(fmt.Sprintf(`<a title="View Source" href="%s">%s</a>`,
+		htemp.HTMLEscapeString(u),
+		htemp.HTMLEscapeString(t.Name)))
+}
+
+// fileDirective is a //go:generate or //go:embed directive in a file.
+type fileDirective struct {
+	File string
+	Name string // generate or embed
+	Args string
+}
+
+// FileDirectives returns the //go:generate and //go:embed directives in the
+// Go files of the directory.
+func (pdoc *tdoc) FileDirectives() []fileDirective {
+	var directives []fileDirective
+	for _, f := range pdoc.SourceFiles {
+		for _, args := range f.Generate {
+			directives = append(directives, fileDirective{f.Name, "generate", args})
+		}
+		for _, args := range f.Embed {
+			directives = append(directives, fileDirective{f.Name, "embed", args})
+		}
+	}
+	return directives
+}
+
+// ReadmeHTML returns the rendered README file of the package. The HTML is
+// sanitized when the package document is built.
+func (pdoc *tdoc) ReadmeHTML() htemp.HTML {
+	if pdoc.README == nil {
+		return ""
+	}
+	return htemp.HTML(pdoc.README.HTML)
 }
 
 func (pdoc *tdoc) SourceLink(pos doc.Pos, text string, textOnlyOK bool) htemp.HTML {
-	if pos.Line == 0 || pdoc.LineFmt == "" || pdoc.Files[pos.File].URL == "" {
+	u := pdoc.sourceURL(pos)
+	if u == "" {
 		if textOnlyOK {
 			return htemp.HTML(htemp.HTMLEscapeString(text))
 		}
 		return ""
 	}
 	return htemp.HTML(fmt.Sprintf(`<a title="View Source" href="%s">%s</a>`,
-		htemp.HTMLEscapeString(fmt.Sprintf(pdoc.LineFmt, pdoc.Files[pos.File].URL, pos.Line)),
+		htemp.HTMLEscapeString(u),
 		htemp.HTMLEscapeString(text)))
 }
 
+// sourceURL returns the URL of the source at pos or "" if the source is not
+// available. The built-in source viewer is used if it is configured or if
+// the repository host does not have a source viewer.
+func (pdoc *tdoc) sourceURL(pos doc.Pos) string {
+	if pos.Line == 0 || int(pos.File) >= len(pdoc.Files) {
+		return ""
+	}
+	f := pdoc.Files[pos.File]
+	hosted := pdoc.LineFmt != "" && f.URL != ""
+	switch {
+	case f.HasSource && (pdoc.sourceViewer || !hosted):
+		return fmt.Sprintf("?file=%s#L%d", url.QueryEscape(f.Name), pos.Line)
+	case hosted:
+		return fmt.Sprintf(pdoc.LineFmt, f.URL, pos.Line)
+	}
+	return ""
+}
+
+// FileURL returns the URL of the source file f or "" if the source is not
+// available.
+func (pdoc *tdoc) FileURL(f *doc.File) string {
+	switch {
+	case f.HasSource && (pdoc.sourceViewer || f.URL == ""):
+		return "?file=" + url.QueryEscape(f.Name)
+	case f.URL != "":
+		return f.URL
+	}
+	return ""
+}
+
+// PosText returns pos formatted as file:line.
+func (pdoc *tdoc) PosText(pos doc.Pos) string {
+	if int(pos.File) >= len(pdoc.Files) {
+		return ""
+	}
+	return fmt.Sprintf("%s:%d", pdoc.Files[pos.File].Name, pos.Line)
+}
+
 // UsesLink generates a link to uses of a symbol definition.
 // title is used as the tooltip. defParts are parts of the symbol definition name.
 func (pdoc *tdoc) UsesLink(title string, defParts ...string) htemp.HTML {
@@ -468,6 +666,49 @@ func codeFn(c doc.Code, typ *doc.Type) htemp.HTML {
 	return htemp.HTML(buf.String())
 }
 
+// sourceFn formats annotated source code with line numbers. Each line has
+// an anchor of the form L123.
+func sourceFn(c doc.Code) htemp.HTML {
+	code := string(codeFn(c, nil))
+	code = strings.TrimSuffix(strings.TrimPrefix(code, "<pre>"), "</pre>")
+	var buf bytes.Buffer
+	buf.WriteString(`<pre class="source">`)
+	for i, line := range strings.Split(strings.TrimSuffix(code, "\n"), "\n") {
+		fmt.Fprintf(&buf, `<span id="L%d" class="line"><a href="#L%d">%d</a></span>%s`, i+1, i+1, i+1, line)
+		buf.WriteByte('\n')
+	}
+	buf.WriteString("</pre>")
+	return htemp.HTML(buf.String())
+}
+
+// typeRefFn formats a reference to a type as an HTML link. References to
+// types in the package with import path importPath link to the anchor on the
+// current page. The Ptr field is left to the template.
+func typeRefFn(r *doc.TypeRef, importPath string) htemp.HTML {
+	if r.Path == importPath && !ast.IsExported(r.Name) {
+		// Unexported types are not documented.
+		return htemp.HTML(htemp.HTMLEscapeString(r.Name))
+	}
+	var buf bytes.Buffer
+	buf.WriteString(`<a href="`)
+	switch r.Path {
+	case importPath:
+		buf.WriteString(formatPathFrag("", r.Name))
+	case "":
+		buf.WriteString(formatPathFrag("builtin", r.Name))
+	default:
+		buf.WriteString(formatPathFrag(r.Path, r.Name))
+	}
+	buf.WriteString(`">`)
+	if r.Path != importPath && r.Path != "" {
+		htemp.HTMLEscape(&buf, []byte(path.Base(r.Path)))
+		buf.WriteByte('.')
+	}
+	htemp.HTMLEscape(&buf, []byte(r.Name))
+	buf.WriteString(`</a>`)
+	return htemp.HTML(buf.String())
+}
+
 var isInterfacePat = regexp.MustCompile(`^type [^ ]+ interface`)
 
 func isInterfaceFn(t *doc.Type) bool {
@@ -485,6 +726,7 @@ func htmlCommentFn(s string) htemp.HTML {
 var mimeTypes = map[string]string{
 	".html": htmlMIMEType,
 	".txt":  textMIMEType,
+	".xml":  "application/opensearchdescription+xml",
 }
 
 type templateMap map[string]interface {
@@ -535,13 +777,20 @@ func parseTemplates(dir string, cb *httputil.CacheBusters, v *viper.Viper) (temp
 		{"home.html", "common.html", "layout.html"},
 		{"importers.html", "common.html", "layout.html"},
 		{"importers_robot.html", "common.html", "layout.html"},
+		{"transitive_importers.html", "common.html", "layout.html"},
 		{"imports.html", "common.html", "layout.html"},
 		{"notfound.html", "common.html", "layout.html"},
 		{"pkg.html", "common.html", "layout.html"},
 		{"results.html", "common.html", "layout.html"},
 		{"tools.html", "common.html", "layout.html"},
+		{"vet.html", "common.html", "layout.html"},
+		{"coverage.html", "common.html", "layout.html"},
+		{"tests.html", "common.html", "layout.html"},
+		{"files.html", "common.html", "layout.html"},
+		{"file.html", "common.html", "layout.html"},
 		{"std.html", "common.html", "layout.html"},
 		{"subrepo.html", "common.html", "layout.html"},
+		{"crawl_failures.html", "common.html", "layout.html"},
 		{"graph.html", "common.html"},
 	}
 	hfuncs := htemp.FuncMap{
@@ -558,7 +807,9 @@ func parseTemplates(dir string, cb *httputil.CacheBusters, v *viper.Viper) (temp
 		"noteTitle":         noteTitleFn,
 		"relativePath":      relativePathFn,
 		"sidebarEnabled":    func() bool { return v.GetBool(ConfigSidebar) },
+		"source":            sourceFn,
 		"staticPath":        func(p string) string { return cb.AppendQueryParam(p, "v") },
+		"typeRef":           typeRefFn,
 		"notVendorPath":     func(p string) bool { return !strings.Contains(p, "/vendor") },
 	}
 	for _, set := range htmlSets {
@@ -588,6 +839,7 @@ func parseTemplates(dir string, cb *httputil.CacheBusters, v *viper.Viper) (temp
 		{"notfound.txt", "common.txt"},
 		{"pkg.txt", "common.txt"},
 		{"results.txt", "common.txt"},
+		{"opensearch.xml"},
 	}
 	tfuncs := ttemp.FuncMap{
 		"comment": commentTextFn,
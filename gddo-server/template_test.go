@@ -13,6 +13,8 @@ import (
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+
+	"github.com/golang/gddo/doc"
 )
 
 func TestFlashMessages(t *testing.T) {
@@ -32,3 +34,32 @@ func TestFlashMessages(t *testing.T) {
 		t.Errorf("got messages %+v, want %+v", actualMessages, expectedMessages)
 	}
 }
+
+func TestSourceURL(t *testing.T) {
+	pdoc := &doc.Package{
+		LineFmt: "%s#L%d",
+		Files: []*doc.File{
+			{Name: "a.go", URL: "https://host/a.go", HasSource: true},
+			{Name: "b.go", HasSource: true},
+			{Name: "c.go", URL: "https://host/c.go"},
+			{Name: "d.go"},
+		},
+	}
+	tests := []struct {
+		file         int16
+		sourceViewer bool
+		want         string
+	}{
+		{0, false, "https://host/a.go#L3"},
+		{0, true, "?file=a.go#L3"},
+		{1, false, "?file=b.go#L3"},
+		{2, true, "https://host/c.go#L3"},
+		{3, false, ""},
+	}
+	for _, tt := range tests {
+		tdoc := &tdoc{Package: pdoc, sourceViewer: tt.sourceViewer}
+		if got := tdoc.sourceURL(doc.Pos{File: tt.file, Line: 3}); got != tt.want {
+			t.Errorf("sourceURL(%s) with sourceViewer=%v = %q, want %q", pdoc.Files[tt.file].Name, tt.sourceViewer, got, tt.want)
+		}
+	}
+}
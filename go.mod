@@ -1,42 +1,60 @@
 module github.com/golang/gddo
 
-go 1.13
+go 1.22.0
 
 require (
 	cloud.google.com/go v0.16.0
+	github.com/garyburd/redigo v1.1.1-0.20170914051019-70e1b1943d4f
+	github.com/golang/lint v0.0.0-20170918230701-e5d664eb928e
+	github.com/golang/snappy v0.0.0-20170215233205-553a64147049
+	github.com/google/go-cmp v0.6.0
+	github.com/gregjones/httpcache v0.0.0-20170920190843-316c5e0ff04e
+	github.com/inconshreveable/log15 v0.0.0-20170622235902-74a0988b5f80
+	github.com/spf13/pflag v1.0.1-0.20170901120850-7aff26db30c1
+	github.com/spf13/viper v1.0.0
+	github.com/yuin/goldmark v1.5.2
+	go.etcd.io/bbolt v1.3.6
+	golang.org/x/mod v0.21.0
+	golang.org/x/net v0.30.0
+	golang.org/x/oauth2 v0.0.0-20170912212905-13449ad91cb2
+	golang.org/x/tools v0.26.0
+	google.golang.org/appengine v1.6.5
+)
+
+require (
 	github.com/BurntSushi/toml v0.3.1 // indirect
 	github.com/bradfitz/gomemcache v0.0.0-20170208213004-1952afaa557d // indirect
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/fsnotify/fsnotify v1.4.3-0.20170329110642-4da3e2cfbabc // indirect
-	github.com/garyburd/redigo v1.1.1-0.20170914051019-70e1b1943d4f
 	github.com/go-stack/stack v1.6.0 // indirect
-	github.com/golang/lint v0.0.0-20170918230701-e5d664eb928e
-	github.com/golang/snappy v0.0.0-20170215233205-553a64147049
-	github.com/google/go-cmp v0.1.1-0.20171103154506-982329095285
+	github.com/golang/protobuf v1.3.1 // indirect
 	github.com/googleapis/gax-go v2.0.0+incompatible // indirect
-	github.com/gregjones/httpcache v0.0.0-20170920190843-316c5e0ff04e
 	github.com/hashicorp/hcl v0.0.0-20170914154624-68e816d1c783 // indirect
-	github.com/inconshreveable/log15 v0.0.0-20170622235902-74a0988b5f80
 	github.com/kr/pretty v0.2.0 // indirect
+	github.com/kr/pty v1.1.1 // indirect
+	github.com/kr/text v0.1.0 // indirect
 	github.com/magiconair/properties v1.7.4-0.20170902060319-8d7837e64d3c // indirect
 	github.com/mattn/go-colorable v0.0.10-0.20170816031813-ad5389df28cd // indirect
 	github.com/mattn/go-isatty v0.0.2 // indirect
 	github.com/mitchellh/mapstructure v0.0.0-20170523030023-d0303fe80992 // indirect
 	github.com/pelletier/go-toml v1.0.1-0.20170904195809-1d6b12b7cb29 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
 	github.com/spf13/afero v0.0.0-20170901052352-ee1bd8ee15a1 // indirect
 	github.com/spf13/cast v1.1.0 // indirect
 	github.com/spf13/jwalterweatherman v0.0.0-20170901151539-12bd96e66386 // indirect
-	github.com/spf13/pflag v1.0.1-0.20170901120850-7aff26db30c1
-	github.com/spf13/viper v1.0.0
+	github.com/stretchr/objx v0.1.0 // indirect
 	github.com/stretchr/testify v1.4.0 // indirect
-	golang.org/x/net v0.0.0-20190603091049-60506f45cf65
-	golang.org/x/oauth2 v0.0.0-20170912212905-13449ad91cb2
-	golang.org/x/sync v0.0.0-20170517211232-f52d1811a629 // indirect
+	golang.org/x/crypto v0.28.0 // indirect
+	golang.org/x/sync v0.8.0 // indirect
+	golang.org/x/sys v0.26.0 // indirect
+	golang.org/x/telemetry v0.0.0-20240521205824-bda55230c457 // indirect
+	golang.org/x/term v0.25.0 // indirect
+	golang.org/x/text v0.19.0 // indirect
 	golang.org/x/time v0.0.0-20170424234030-8be79e1e0910 // indirect
-	golang.org/x/tools v0.0.0-20180917221912-90fa682c2a6e
+	golang.org/x/xerrors v0.0.0-20190717185122-a985d3407aa7 // indirect
 	google.golang.org/api v0.0.0-20170921000349-586095a6e407 // indirect
-	google.golang.org/appengine v1.6.5
 	google.golang.org/genproto v0.0.0-20170918111702-1e559d0a00ee // indirect
 	google.golang.org/grpc v1.2.1-0.20170921194603-d4b75ebd4f9f // indirect
 	gopkg.in/check.v1 v1.0.0-20190902080502-41f04d3bba15 // indirect
+	gopkg.in/yaml.v2 v2.2.2 // indirect
 )
@@ -48,7 +48,10 @@ func expand(template string, match map[string]string, subs ...string) string {
 	return string(p)
 }
 
-var readmePat = regexp.MustCompile(`(?i)^readme(?:$|\.)`)
+var (
+	readmePat  = regexp.MustCompile(`(?i)^readme(?:$|\.)`)
+	licensePat = regexp.MustCompile(`(?i)^(?:licen[cs]e|copying|unlicense)(?:$|[.-])`)
+)
 
 // isDocFile returns true if a file with name n should be included in the
 // documentation.
@@ -56,7 +59,7 @@ func isDocFile(n string) bool {
 	if strings.HasSuffix(n, ".go") && n[0] != '_' && n[0] != '.' {
 		return true
 	}
-	return readmePat.MatchString(n)
+	return readmePat.MatchString(n) || licensePat.MatchString(n)
 }
 
 var linePat = regexp.MustCompile(`(?m)^//line .*$`)